@@ -1,4 +1,4 @@
-// basics_syntax_types.go
+// functions_and_types.go
 package main
 
 import "fmt"
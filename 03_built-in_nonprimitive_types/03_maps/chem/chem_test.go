@@ -0,0 +1,154 @@
+package chem
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		formula  string
+		expected map[string]int
+	}{
+		{"H2O", map[string]int{"H": 2, "O": 1}},
+		{"Ca(OH)2", map[string]int{"Ca": 1, "O": 2, "H": 2}},
+		{"CuSO4·5H2O", map[string]int{"Cu": 1, "S": 1, "O": 9, "H": 10}},
+		{"CuSO4.5H2O", map[string]int{"Cu": 1, "S": 1, "O": 9, "H": 10}},
+		{"K4[Fe(CN)6]", map[string]int{"K": 4, "Fe": 1, "C": 6, "N": 6}},
+		{"Al2(SO4)3", map[string]int{"Al": 2, "S": 3, "O": 12}},
+		{"CH3COOH", map[string]int{"C": 2, "H": 4, "O": 2}},
+	}
+
+	for _, test := range tests {
+		f, err := Parse(test.formula)
+		if err != nil {
+			t.Errorf("Parse(%q) returned an error: %v", test.formula, err)
+		} else if !reflect.DeepEqual(f.Counts(), test.expected) {
+			t.Errorf("Parse(%q) returned %v. Expected %v", test.formula, f.Counts(), test.expected)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var tests = []struct {
+		formula string
+		col     int
+	}{
+		{"", 1},
+		{"Xy2", 1},
+		{"H2Oq", 3},
+		{"Ca(OH2", 3},
+		{"Ca(OH))2", 7},
+		{"NaCl0", 5},
+		{"CuSO4·", 7},
+		{"H2 O", 3},
+		{"()", 2},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.formula)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("Parse(%q) returned %v. Expected a *ParseError", test.formula, err)
+		} else if pe.Col != test.col {
+			t.Errorf("Parse(%q) reported column %d (%v). Expected %d", test.formula, pe.Col, err, test.col)
+		}
+	}
+}
+
+func TestParseErrorContext(t *testing.T) {
+	_, err := Parse("Ca(OH2")
+	expected := "Ca(OH2\n  ^"
+	if got := err.(*ParseError).Context(); got != expected {
+		t.Errorf("Context() returned %q. Expected %q", got, expected)
+	}
+}
+
+func TestMolarMass(t *testing.T) {
+	var tests = []struct {
+		formula  string
+		expected float64
+	}{
+		{"H2O", 18.015},
+		{"NaCl", 58.44},
+		{"Ca(OH)2", 74.092},
+		{"CuSO4·5H2O", 249.68},
+	}
+
+	for _, test := range tests {
+		m, err := MolarMass(test.formula)
+		if err != nil {
+			t.Errorf("MolarMass(%q) returned an error: %v", test.formula, err)
+		} else if math.Abs(m-test.expected) > 0.01 {
+			t.Errorf("MolarMass(%q) returned %.3f. Expected %.3f", test.formula, m, test.expected)
+		}
+	}
+}
+
+func TestHill(t *testing.T) {
+	var tests = []struct {
+		formula  string
+		expected string
+	}{
+		{"CH3COOH", "C2H4O2"},
+		{"H2SO4", "H2O4S"},
+		{"NaCl", "ClNa"},
+	}
+
+	for _, test := range tests {
+		f, _ := Parse(test.formula)
+		if got := f.Hill(); got != test.expected {
+			t.Errorf("Hill(%q) returned %q. Expected %q", test.formula, got, test.expected)
+		}
+	}
+}
+
+func TestBalance(t *testing.T) {
+	var tests = []struct {
+		equation string
+		expected string
+	}{
+		{"H2 + O2 -> H2O", "2H2 + O2 -> 2H2O"},
+		{"CH4 + O2 = CO2 + H2O", "CH4 + 2O2 -> CO2 + 2H2O"},
+		{"Fe + O2 → Fe2O3", "4Fe + 3O2 -> 2Fe2O3"},
+		{"C3H8 + O2 -> CO2 + H2O", "C3H8 + 5O2 -> 3CO2 + 4H2O"},
+		{"Ca(OH)2 + H3PO4 -> Ca3(PO4)2 + H2O", "3Ca(OH)2 + 2H3PO4 -> Ca3(PO4)2 + 6H2O"},
+		{"2KClO3 -> KCl + O2", "2KClO3 -> 2KCl + 3O2"},
+	}
+
+	for _, test := range tests {
+		eq, err := Balance(test.equation)
+		if err != nil {
+			t.Errorf("Balance(%q) returned an error: %v", test.equation, err)
+		} else if eq.String() != test.expected {
+			t.Errorf("Balance(%q) returned %q. Expected %q", test.equation, eq, test.expected)
+		}
+	}
+}
+
+func TestBalanceErrors(t *testing.T) {
+	if _, err := Balance("H2 -> O2"); !errors.Is(err, ErrUnbalanceable) {
+		t.Errorf("Balance(H2 -> O2) returned %v. Expected ErrUnbalanceable", err)
+	}
+
+	var tests = []struct {
+		equation string
+		col      int
+	}{
+		{"H2 + O2", 8},
+		{"H2 + -> H2O", 6},
+		{"H2 -> H2O -> O", 11},
+		{"H2 + O2 -> H2Q", 14},
+	}
+	for _, test := range tests {
+		_, err := Balance(test.equation)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("Balance(%q) returned %v. Expected a *ParseError", test.equation, err)
+		} else if pe.Col != test.col {
+			t.Errorf("Balance(%q) reported column %d (%v). Expected %d", test.equation, pe.Col, err, test.col)
+		}
+	}
+}
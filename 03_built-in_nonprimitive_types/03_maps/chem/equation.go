@@ -0,0 +1,223 @@
+package chem
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// ErrUnbalanceable is returned when an equation has no unique set of positive
+// integer coefficients, e.g. because an element appears on one side only or
+// the reaction combines several independent reactions.
+var ErrUnbalanceable = errors.New("chem: equation cannot be balanced")
+
+// Term is a species in an equation with its stoichiometric coefficient.
+type Term struct {
+	Coef    int
+	Formula *Formula
+}
+
+// Equation is a balanced chemical equation.
+type Equation struct {
+	Reactants []Term
+	Products  []Term
+}
+
+func (e *Equation) String() string {
+	side := func(terms []Term) string {
+		parts := make([]string, len(terms))
+		for i, t := range terms {
+			if t.Coef == 1 {
+				parts[i] = t.Formula.Text
+			} else {
+				parts[i] = fmt.Sprintf("%d%s", t.Coef, t.Formula.Text)
+			}
+		}
+		return strings.Join(parts, " + ")
+	}
+	return side(e.Reactants) + " -> " + side(e.Products)
+}
+
+// Balance parses an equation such as "H2 + O2 -> H2O" and returns it with the
+// smallest positive integer coefficients. Sides are separated by "->", "→" or
+// "="; any coefficients already present in the input are ignored.
+func Balance(equation string) (*Equation, error) {
+	reactants, products, err := parseEquation(equation)
+	if err != nil {
+		return nil, err
+	}
+	species := append(append([]*Formula(nil), reactants...), products...)
+
+	// Build the element x species matrix, with products negated so that a
+	// balanced equation is a vector in its null space.
+	var elements []string
+	seen := map[string]bool{}
+	for _, f := range species {
+		for _, sym := range f.order {
+			if !seen[sym] {
+				seen[sym] = true
+				elements = append(elements, sym)
+			}
+		}
+	}
+	m := make([][]*big.Rat, len(elements))
+	for i, sym := range elements {
+		m[i] = make([]*big.Rat, len(species))
+		for j, f := range species {
+			n := int64(f.counts[sym])
+			if j >= len(reactants) {
+				n = -n
+			}
+			m[i][j] = big.NewRat(n, 1)
+		}
+	}
+
+	coefs, err := nullVector(m, len(species))
+	if err != nil {
+		return nil, err
+	}
+	eq := &Equation{}
+	for j, f := range species {
+		t := Term{Coef: coefs[j], Formula: f}
+		if j < len(reactants) {
+			eq.Reactants = append(eq.Reactants, t)
+		} else {
+			eq.Products = append(eq.Products, t)
+		}
+	}
+	return eq, nil
+}
+
+// nullVector reduces m to row echelon form and returns the unique (up to
+// scale) null space vector as the smallest positive integers.
+func nullVector(m [][]*big.Rat, cols int) ([]int, error) {
+	var pivots []int
+	row := 0
+	for col := 0; col < cols && row < len(m); col++ {
+		p := -1
+		for r := row; r < len(m); r++ {
+			if m[r][col].Sign() != 0 {
+				p = r
+				break
+			}
+		}
+		if p < 0 {
+			continue
+		}
+		m[row], m[p] = m[p], m[row]
+		inv := new(big.Rat).Inv(m[row][col])
+		for c := col; c < cols; c++ {
+			m[row][c].Mul(m[row][c], inv)
+		}
+		for r := range m {
+			if r == row || m[r][col].Sign() == 0 {
+				continue
+			}
+			f := new(big.Rat).Set(m[r][col])
+			for c := col; c < cols; c++ {
+				m[r][c].Sub(m[r][c], new(big.Rat).Mul(f, m[row][c]))
+			}
+		}
+		pivots = append(pivots, col)
+		row++
+	}
+	if cols-len(pivots) != 1 {
+		return nil, ErrUnbalanceable
+	}
+
+	free := 0
+	for i, p := range pivots {
+		if p != i {
+			break
+		}
+		free = i + 1
+	}
+	x := make([]*big.Rat, cols)
+	x[free] = big.NewRat(1, 1)
+	for r, p := range pivots {
+		x[p] = new(big.Rat).Neg(m[r][free])
+	}
+
+	lcm := big.NewInt(1)
+	for _, v := range x {
+		d := v.Denom()
+		g := new(big.Int).GCD(nil, nil, lcm, d)
+		lcm.Mul(lcm, new(big.Int).Quo(d, g))
+	}
+	ints := make([]*big.Int, cols)
+	gcd := new(big.Int)
+	for i, v := range x {
+		n := new(big.Int).Mul(v.Num(), new(big.Int).Quo(lcm, v.Denom()))
+		ints[i] = n
+		gcd.GCD(nil, nil, gcd, new(big.Int).Abs(n))
+	}
+	out := make([]int, cols)
+	for i, n := range ints {
+		n.Quo(n, gcd)
+		if n.Sign() <= 0 || !n.IsInt64() {
+			return nil, ErrUnbalanceable
+		}
+		out[i] = int(n.Int64())
+	}
+	return out, nil
+}
+
+// parseEquation splits an equation into reactant and product formulas,
+// reporting errors with columns relative to the whole equation.
+func parseEquation(equation string) (reactants, products []*Formula, err error) {
+	p := &parser{input: equation, runes: []rune(equation)}
+	side := &reactants
+	arrow := false
+	for {
+		p.skipSpace()
+		if p.pos == len(p.runes) {
+			return nil, nil, p.errorf("expected a formula")
+		}
+		for isDigit(p.peek()) {
+			p.pos++ // a coefficient already given is recomputed
+		}
+		f, err := p.formula()
+		if err != nil {
+			return nil, nil, err
+		}
+		*side = append(*side, f)
+
+		p.skipSpace()
+		at := p.pos
+		switch {
+		case p.pos == len(p.runes):
+			if !arrow {
+				return nil, nil, p.errorf("expected \"->\" or \"=\"")
+			}
+			return reactants, products, nil
+		case p.peek() == '+':
+			p.pos++
+		case p.consume("->") || p.consume("→") || p.consume("="):
+			if arrow {
+				p.pos = at
+				return nil, nil, p.errorf("more than one arrow")
+			}
+			arrow = true
+			side = &products
+		default:
+			return nil, nil, p.errorf("unexpected %q", p.peek())
+		}
+	}
+}
+
+func (p *parser) skipSpace() {
+	for unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(s string) bool {
+	r := []rune(s)
+	if p.pos+len(r) > len(p.runes) || string(p.runes[p.pos:p.pos+len(r)]) != s {
+		return false
+	}
+	p.pos += len(r)
+	return true
+}
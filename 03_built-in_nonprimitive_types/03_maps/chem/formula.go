@@ -0,0 +1,264 @@
+// Package chem parses chemical formulas such as "H2O", "Ca(OH)2" and
+// "CuSO4·5H2O" into element counts, computes molar masses from the periodic
+// package, and balances simple reaction equations.
+package chem
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go-labs/03_built-in_nonprimitive_types/03_maps/periodic"
+)
+
+// ParseError reports where a formula or equation failed to parse. Col is a
+// 1-based rune column into Input.
+type ParseError struct {
+	Input string
+	Col   int
+	Msg   string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("chem: column %d: %s", e.Col, e.Msg)
+}
+
+// Context returns the input with a caret under the offending column:
+//
+//	Ca(OH2
+//	      ^
+func (e *ParseError) Context() string {
+	return e.Input + "\n" + strings.Repeat(" ", e.Col-1) + "^"
+}
+
+// Formula is a parsed chemical formula.
+type Formula struct {
+	Text   string
+	counts map[string]int
+	order  []string // element symbols in order of first appearance
+}
+
+// Counts returns the number of atoms of each element.
+func (f *Formula) Counts() map[string]int {
+	out := make(map[string]int, len(f.counts))
+	for k, v := range f.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// Elements returns the element symbols in order of first appearance.
+func (f *Formula) Elements() []string {
+	return append([]string(nil), f.order...)
+}
+
+// MolarMass returns the molar mass in g/mol.
+func (f *Formula) MolarMass() float64 {
+	total := 0.0
+	for _, sym := range f.order {
+		el, _ := periodic.BySymbol(sym)
+		total += el.Mass * float64(f.counts[sym])
+	}
+	return total
+}
+
+// Hill returns the formula in Hill notation: C first, then H, then the other
+// elements alphabetically; without carbon all elements are alphabetical.
+func (f *Formula) Hill() string {
+	syms := f.Elements()
+	_, hasC := f.counts["C"]
+	sort.Slice(syms, func(i, j int) bool {
+		if hasC {
+			for _, first := range []string{"C", "H"} {
+				if syms[i] == first || syms[j] == first {
+					return syms[i] == first && syms[j] != first
+				}
+			}
+		}
+		return syms[i] < syms[j]
+	})
+	var b strings.Builder
+	for _, s := range syms {
+		b.WriteString(s)
+		if n := f.counts[s]; n > 1 {
+			fmt.Fprint(&b, n)
+		}
+	}
+	return b.String()
+}
+
+func (f *Formula) String() string {
+	return f.Text
+}
+
+// MolarMass parses formula and returns its molar mass in g/mol.
+func MolarMass(formula string) (float64, error) {
+	f, err := Parse(formula)
+	if err != nil {
+		return 0, err
+	}
+	return f.MolarMass(), nil
+}
+
+// Parse parses a formula. Groups may be nested in parentheses or square
+// brackets, and hydrates are joined with "·", "." or "*", each part taking
+// an optional leading coefficient.
+func Parse(formula string) (*Formula, error) {
+	p := &parser{input: formula, runes: []rune(formula)}
+	f, err := p.formula()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.runes) {
+		return nil, p.errorf("unexpected %q", p.runes[p.pos])
+	}
+	return f, nil
+}
+
+type parser struct {
+	input string
+	runes []rune
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &ParseError{Input: p.input, Col: p.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) peek() rune {
+	if p.pos < len(p.runes) {
+		return p.runes[p.pos]
+	}
+	return 0
+}
+
+// formula parses the runes from p.pos up to the first rune that cannot be part
+// of a formula, such as the end of input, whitespace or '+'.
+func (p *parser) formula() (*Formula, error) {
+	start := p.pos
+	f := &Formula{counts: map[string]int{}}
+	for {
+		var coef int
+		if err := p.count(&coef); err != nil {
+			return nil, err
+		}
+		if err := p.group(f, coef, 0); err != nil {
+			return nil, err
+		}
+		if r := p.peek(); r == '·' || r == '.' || r == '*' || r == '•' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	if len(f.order) == 0 {
+		return nil, p.errorf("expected a formula")
+	}
+	f.Text = string(p.runes[start:p.pos])
+	return f, nil
+}
+
+// group parses a sequence of elements and bracketed sub-groups until a
+// closing bracket or the end of the formula, adding each count times mult.
+func (p *parser) group(f *Formula, mult, depth int) error {
+	begin := p.pos
+	for {
+		r := p.peek()
+		switch {
+		case unicode.IsUpper(r):
+			start := p.pos
+			p.pos++
+			for unicode.IsLower(p.peek()) {
+				p.pos++
+			}
+			sym, n := string(p.runes[start:p.pos]), 0
+			if _, ok := periodic.BySymbol(sym); !ok {
+				p.pos = start
+				return p.errorf("unknown element %q", sym)
+			}
+			if err := p.count(&n); err != nil {
+				return err
+			}
+			f.add(sym, n*mult)
+		case r == '(' || r == '[':
+			open := p.pos
+			p.pos++
+			sub := &Formula{counts: map[string]int{}}
+			if err := p.group(sub, 1, depth+1); err != nil {
+				return err
+			}
+			want := map[rune]rune{'(': ')', '[': ']'}[r]
+			if p.peek() != want {
+				if p.pos == len(p.runes) {
+					p.pos = open
+					return p.errorf("unclosed %q", r)
+				}
+				return p.errorf("expected %q", want)
+			}
+			p.pos++
+			var n int
+			if err := p.count(&n); err != nil {
+				return err
+			}
+			for _, sym := range sub.order {
+				f.add(sym, sub.counts[sym]*n*mult)
+			}
+		case r == ')' || r == ']':
+			if depth == 0 {
+				return p.errorf("unexpected %q", r)
+			}
+			if p.pos == begin {
+				return p.errorf("empty group")
+			}
+			return nil
+		case unicode.IsLower(r):
+			return p.errorf("element symbols must start with an upper-case letter")
+		case isDigit(r):
+			return p.errorf("unexpected count")
+		default:
+			if p.pos == begin {
+				if r == 0 {
+					return p.errorf("expected an element")
+				}
+				return p.errorf("unexpected %q", r)
+			}
+			return nil
+		}
+	}
+}
+
+// number parses an optional unsigned integer, returning def if there is none.
+func (p *parser) number(def int) int {
+	if !isDigit(p.peek()) {
+		return def
+	}
+	n := 0
+	for isDigit(p.peek()) {
+		n = n*10 + int(p.peek()-'0')
+		p.pos++
+	}
+	return n
+}
+
+// count parses an optional positive count into n, defaulting to 1.
+func (p *parser) count(n *int) error {
+	start := p.pos
+	*n = p.number(1)
+	if *n == 0 {
+		p.pos = start
+		return p.errorf("count must be positive")
+	}
+	return nil
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func (f *Formula) add(sym string, n int) {
+	if _, ok := f.counts[sym]; !ok {
+		f.order = append(f.order, sym)
+	}
+	f.counts[sym] += n
+}
@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"go-labs/03_built-in_nonprimitive_types/03_maps/periodic"
+)
 
 func mapExample() {
 	fmt.Println("Example 1: \"map\" data type")
@@ -72,9 +76,23 @@ func mapLookupVerificationExample() {
 	}
 }
 
+func periodicTableExample() {
+	fmt.Println("Example 5: Typed lookups with the periodic package")
+	// The periodic package keeps all 118 elements in a slice of Element structs
+	// and indexes them with maps, so lookups still use the comma-ok idiom.
+	if el, ok := periodic.BySymbol("Na"); ok {
+		fmt.Println(el.Name, el.State, el.Mass)
+	}
+	for _, el := range periodic.Filter(periodic.InState(periodic.Solid), periodic.InPeriod(3)) {
+		fmt.Print(el.Symbol, " ")
+	}
+	fmt.Println()
+}
+
 func main() {
 	mapExample()
 	shorterMapInitialization()
 	compositeMapping()
 	mapLookupVerificationExample()
+	periodicTableExample()
 }
@@ -0,0 +1,199 @@
+// Package periodic provides the 118 chemical elements as typed values, with
+// lookups by symbol, atomic number and name, and predicate-based filters.
+//
+// The data is embedded from elements.csv. Masses are standard atomic weights
+// in g/mol; for elements without a stable isotope the mass number of the
+// longest-lived isotope is used. Lanthanides and actinides have Group 0.
+package periodic
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// State is the phase of an element at standard temperature and pressure.
+type State string
+
+const (
+	Solid        State = "solid"
+	Liquid       State = "liquid"
+	Gas          State = "gas"
+	UnknownState State = "unknown"
+)
+
+// Category is the chemical family an element belongs to.
+type Category string
+
+const (
+	AlkaliMetal         Category = "alkali metal"
+	AlkalineEarthMetal  Category = "alkaline earth metal"
+	TransitionMetal     Category = "transition metal"
+	PostTransitionMetal Category = "post-transition metal"
+	Metalloid           Category = "metalloid"
+	Nonmetal            Category = "nonmetal"
+	Halogen             Category = "halogen"
+	NobleGas            Category = "noble gas"
+	Lanthanide          Category = "lanthanide"
+	Actinide            Category = "actinide"
+)
+
+// Element describes a single chemical element.
+type Element struct {
+	Number   int
+	Symbol   string
+	Name     string
+	Mass     float64
+	Group    int
+	Period   int
+	State    State
+	Category Category
+}
+
+func (e Element) String() string {
+	return fmt.Sprintf("%d %s (%s)", e.Number, e.Symbol, e.Name)
+}
+
+//go:embed elements.csv
+var elementsCSV []byte
+
+var (
+	elements []Element // ordered by atomic number
+	bySymbol map[string]int
+	byName   map[string]int
+)
+
+func init() {
+	var err error
+	elements, err = parse(elementsCSV)
+	if err != nil {
+		panic("periodic: " + err.Error())
+	}
+	bySymbol = make(map[string]int, len(elements))
+	byName = make(map[string]int, len(elements))
+	for i, e := range elements {
+		bySymbol[e.Symbol] = i
+		byName[strings.ToLower(e.Name)] = i
+	}
+}
+
+func parse(data []byte) ([]Element, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty dataset")
+	}
+	out := make([]Element, 0, len(records)-1)
+	for line, r := range records[1:] {
+		var e Element
+		var errs [4]error
+		e.Number, errs[0] = strconv.Atoi(r[0])
+		e.Symbol, e.Name = r[1], r[2]
+		e.Mass, errs[1] = strconv.ParseFloat(r[3], 64)
+		e.Group, errs[2] = strconv.Atoi(r[4])
+		e.Period, errs[3] = strconv.Atoi(r[5])
+		e.State, e.Category = State(r[6]), Category(r[7])
+		for _, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line+2, err)
+			}
+		}
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Number < out[j].Number })
+	return out, nil
+}
+
+// All returns every element ordered by atomic number.
+func All() []Element {
+	out := make([]Element, len(elements))
+	copy(out, elements)
+	return out
+}
+
+// BySymbol looks up an element by its case-sensitive symbol, e.g. "Li".
+func BySymbol(symbol string) (Element, bool) {
+	i, ok := bySymbol[symbol]
+	if !ok {
+		return Element{}, false
+	}
+	return elements[i], true
+}
+
+// ByNumber looks up an element by atomic number.
+func ByNumber(n int) (Element, bool) {
+	if n < 1 || n > len(elements) {
+		return Element{}, false
+	}
+	return elements[n-1], true
+}
+
+// ByName looks up an element by its case-insensitive English name.
+// Both "Aluminium" and "Aluminum", "Caesium" and "Cesium", and "Sulfur" and
+// "Sulphur" are accepted.
+func ByName(name string) (Element, bool) {
+	key := strings.ToLower(name)
+	if alt, ok := spellings[key]; ok {
+		key = alt
+	}
+	i, ok := byName[key]
+	if !ok {
+		return Element{}, false
+	}
+	return elements[i], true
+}
+
+var spellings = map[string]string{
+	"aluminum": "aluminium",
+	"cesium":   "caesium",
+	"sulphur":  "sulfur",
+}
+
+// Predicate selects elements in Filter.
+type Predicate func(Element) bool
+
+// Filter returns the elements, in atomic number order, matching all predicates.
+func Filter(preds ...Predicate) []Element {
+	var out []Element
+next:
+	for _, e := range elements {
+		for _, p := range preds {
+			if !p(e) {
+				continue next
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// InGroup matches elements in group g (1-18).
+func InGroup(g int) Predicate {
+	return func(e Element) bool { return e.Group == g }
+}
+
+// InPeriod matches elements in period p (1-7).
+func InPeriod(p int) Predicate {
+	return func(e Element) bool { return e.Period == p }
+}
+
+// InState matches elements in state s at standard conditions.
+func InState(s State) Predicate {
+	return func(e Element) bool { return e.State == s }
+}
+
+// InCategory matches elements of category c.
+func InCategory(c Category) Predicate {
+	return func(e Element) bool { return e.Category == c }
+}
+
+// MassBetween matches elements with lo <= mass <= hi.
+func MassBetween(lo, hi float64) Predicate {
+	return func(e Element) bool { return e.Mass >= lo && e.Mass <= hi }
+}
@@ -0,0 +1,91 @@
+package periodic
+
+import "testing"
+
+func TestDataset(t *testing.T) {
+	all := All()
+	if len(all) != 118 {
+		t.Fatalf("All() returned %d elements. Expected 118", len(all))
+	}
+	symbols := map[string]bool{}
+	for i, e := range all {
+		if e.Number != i+1 {
+			t.Errorf("element %d has number %d", i+1, e.Number)
+		}
+		if symbols[e.Symbol] {
+			t.Errorf("duplicate symbol %s", e.Symbol)
+		}
+		symbols[e.Symbol] = true
+		if e.Period < 1 || e.Period > 7 || e.Group < 0 || e.Group > 18 || e.Mass <= 0 {
+			t.Errorf("element %v has invalid group %d, period %d or mass %v", e, e.Group, e.Period, e.Mass)
+		}
+		if i > 0 && e.Mass < all[i-1].Mass && !inversions[e.Symbol] {
+			t.Errorf("unexpected mass inversion at %v", e)
+		}
+	}
+}
+
+// inversions are the elements known to be lighter than their predecessor.
+var inversions = map[string]bool{"K": true, "Ni": true, "I": true, "Pa": true, "Np": true, "Am": true, "Hs": true}
+
+func TestLookups(t *testing.T) {
+	li, ok := BySymbol("Li")
+	if !ok || li.Name != "Lithium" || li.State != Solid {
+		t.Errorf("BySymbol(Li) returned %+v, %v", li, ok)
+	}
+	if _, ok := BySymbol("Un"); ok {
+		t.Error("Expected BySymbol(Un) to fail")
+	}
+	if e, ok := ByNumber(118); !ok || e.Symbol != "Og" {
+		t.Errorf("ByNumber(118) returned %+v, %v", e, ok)
+	}
+	if _, ok := ByNumber(0); ok {
+		t.Error("Expected ByNumber(0) to fail")
+	}
+	var names = []struct {
+		name   string
+		symbol string
+	}{
+		{"neon", "Ne"},
+		{"Aluminum", "Al"},
+		{"CESIUM", "Cs"},
+		{"Sulphur", "S"},
+	}
+	for _, n := range names {
+		if e, ok := ByName(n.name); !ok || e.Symbol != n.symbol {
+			t.Errorf("ByName(%q) returned %+v, %v. Expected %s", n.name, e, ok, n.symbol)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	var tests = []struct {
+		name     string
+		preds    []Predicate
+		expected []string
+	}{
+		{"noble gases", []Predicate{InCategory(NobleGas)}, []string{"He", "Ne", "Ar", "Kr", "Xe", "Rn", "Og"}},
+		{"solids in period 3", []Predicate{InState(Solid), InPeriod(3)}, []string{"Na", "Mg", "Al", "Si", "P", "S"}},
+		{"liquids", []Predicate{InState(Liquid)}, []string{"Br", "Hg"}},
+		{"group 1 metals", []Predicate{InGroup(1), InCategory(AlkaliMetal)}, []string{"Li", "Na", "K", "Rb", "Cs", "Fr"}},
+		{"light", []Predicate{MassBetween(0, 5)}, []string{"H", "He"}},
+	}
+
+	for _, test := range tests {
+		got := Filter(test.preds...)
+		symbols := make([]string, len(got))
+		for i, e := range got {
+			symbols[i] = e.Symbol
+		}
+		if len(symbols) != len(test.expected) {
+			t.Errorf("%s: Filter returned %v. Expected %v", test.name, symbols, test.expected)
+			continue
+		}
+		for i := range symbols {
+			if symbols[i] != test.expected[i] {
+				t.Errorf("%s: Filter returned %v. Expected %v", test.name, symbols, test.expected)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package stats
+
+import "math"
+
+// Accumulator computes count, mean, variance, minimum and maximum of a
+// stream in constant memory using Welford's online algorithm, which avoids
+// the cancellation of the naive sum-of-squares formula. The zero value is an
+// empty accumulator ready to use.
+//
+// Medians and percentiles need all the data and are not available here.
+type Accumulator struct {
+	n        int
+	mean, m2 float64
+	min, max float64
+}
+
+// Add adds one value.
+func (a *Accumulator) Add(x float64) {
+	a.n++
+	if a.n == 1 {
+		a.min, a.max = x, x
+	} else {
+		a.min = math.Min(a.min, x)
+		a.max = math.Max(a.max, x)
+	}
+	d := x - a.mean
+	a.mean += d / float64(a.n)
+	a.m2 += d * (x - a.mean)
+}
+
+// AddAll adds every value of xs.
+func (a *Accumulator) AddAll(xs ...float64) {
+	for _, x := range xs {
+		a.Add(x)
+	}
+}
+
+// Merge adds the values seen by b, as if they had been added to a, so that
+// partial results from several goroutines or files can be combined (Chan et
+// al.'s parallel update).
+func (a *Accumulator) Merge(b Accumulator) {
+	switch {
+	case b.n == 0:
+		return
+	case a.n == 0:
+		*a = b
+		return
+	}
+	n := a.n + b.n
+	d := b.mean - a.mean
+	a.m2 += b.m2 + d*d*float64(a.n)*float64(b.n)/float64(n)
+	a.mean += d * float64(b.n) / float64(n)
+	a.min = math.Min(a.min, b.min)
+	a.max = math.Max(a.max, b.max)
+	a.n = n
+}
+
+// Count returns the number of values added.
+func (a *Accumulator) Count() int {
+	return a.n
+}
+
+// Mean returns the mean of the values added so far.
+func (a *Accumulator) Mean() (float64, error) {
+	if a.n == 0 {
+		return 0, ErrEmpty
+	}
+	return a.mean, nil
+}
+
+// Variance returns the population variance.
+func (a *Accumulator) Variance() (float64, error) {
+	if a.n == 0 {
+		return 0, ErrEmpty
+	}
+	return a.m2 / float64(a.n), nil
+}
+
+// SampleVariance returns the unbiased sample variance.
+func (a *Accumulator) SampleVariance() (float64, error) {
+	if a.n == 0 {
+		return 0, ErrEmpty
+	}
+	if a.n < 2 {
+		return 0, ErrTooFew
+	}
+	return a.m2 / float64(a.n-1), nil
+}
+
+// StdDev returns the population standard deviation.
+func (a *Accumulator) StdDev() (float64, error) {
+	v, err := a.Variance()
+	return math.Sqrt(v), err
+}
+
+// SampleStdDev returns the sample standard deviation.
+func (a *Accumulator) SampleStdDev() (float64, error) {
+	v, err := a.SampleVariance()
+	return math.Sqrt(v), err
+}
+
+// Min returns the smallest value added.
+func (a *Accumulator) Min() (float64, error) {
+	if a.n == 0 {
+		return 0, ErrEmpty
+	}
+	return a.min, nil
+}
+
+// Max returns the largest value added.
+func (a *Accumulator) Max() (float64, error) {
+	if a.n == 0 {
+		return 0, ErrEmpty
+	}
+	return a.max, nil
+}
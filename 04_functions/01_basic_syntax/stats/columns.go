@@ -0,0 +1,79 @@
+package stats
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Column is a named column of numbers read by ReadColumns.
+type Column struct {
+	Name    string
+	Values  []float64
+	Invalid int // non-empty cells that were not numbers
+}
+
+// ReadColumns reads delimited text, such as CSV or one number per line, into
+// columns. If any field of the first row is not a number, that row is taken
+// as the header; otherwise columns are named "col1", "col2" and so on. Rows
+// may have different lengths, empty cells are skipped and other cells that
+// are not finite numbers, including NaN and ±Inf, are counted in Invalid.
+func ReadColumns(r io.Reader, comma rune) ([]Column, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	var cols []Column
+	first := true
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return cols, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			first = false
+			if isHeader(row) {
+				for _, name := range row {
+					cols = append(cols, Column{Name: strings.TrimSpace(name)})
+				}
+				continue
+			}
+		}
+		for i, cell := range row {
+			for len(cols) <= i {
+				cols = append(cols, Column{Name: "col" + strconv.Itoa(len(cols)+1)})
+			}
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(cell, 64)
+			if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+				cols[i].Invalid++
+				continue
+			}
+			cols[i].Values = append(cols[i].Values, v)
+		}
+	}
+}
+
+func isHeader(row []string) bool {
+	for _, cell := range row {
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// Bin is one histogram bar covering [Lo, Hi); the last bin also includes Hi.
+type Bin struct {
+	Lo    float64 `json:"lo"`
+	Hi    float64 `json:"hi"`
+	Count int     `json:"count"`
+}
+
+// Histogram sorts xs into the given number of equal-width bins spanning
+// the data's minimum to maximum. If every value is equal there is a single
+// bin. The range must be finite: infinite values, or a spread too wide for a
+// float64, are an error.
+func Histogram(xs []float64, bins int) ([]Bin, error) {
+	if bins < 1 {
+		return nil, errors.New("stats: histogram needs at least one bin")
+	}
+	lo, hi, err := MinMax(xs)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(hi-lo, 0) || math.IsNaN(hi-lo) {
+		return nil, errors.New("stats: histogram range is not finite")
+	}
+	if lo == hi {
+		return []Bin{{Lo: lo, Hi: hi, Count: len(xs)}}, nil
+	}
+	width := (hi - lo) / float64(bins)
+	out := make([]Bin, bins)
+	for i := range out {
+		out[i].Lo = lo + float64(i)*width
+		out[i].Hi = lo + float64(i+1)*width
+	}
+	out[bins-1].Hi = hi
+	for _, x := range xs {
+		i := int(math.Floor((x - lo) / width))
+		if i >= bins {
+			i = bins - 1
+		}
+		out[i].Count++
+	}
+	return out, nil
+}
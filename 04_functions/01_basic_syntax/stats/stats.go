@@ -0,0 +1,232 @@
+// Package stats grows the lab's average function into descriptive
+// statistics. Unlike average, which returns NaN for an empty slice, every
+// function here returns ErrEmpty when there is no data.
+//
+// Functions never modify their input; the ones that need sorted data sort a
+// copy. For data that does not fit in memory, use an Accumulator.
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var (
+	// ErrEmpty is returned when a statistic is requested for no data.
+	ErrEmpty = errors.New("stats: empty input")
+	// ErrTooFew is returned by sample statistics given a single value.
+	ErrTooFew = errors.New("stats: need at least two values")
+	// ErrPercentile is returned for percentiles outside [0, 100].
+	ErrPercentile = errors.New("stats: percentile must be between 0 and 100")
+)
+
+// Sum returns the sum of xs, which is 0 for no data. It uses Kahan
+// summation so that many small values are not lost next to large ones.
+func Sum(xs []float64) float64 {
+	var sum, c float64
+	for _, x := range xs {
+		y := x - c
+		t := sum + y
+		c = (t - sum) - y
+		sum = t
+	}
+	return sum
+}
+
+// Mean returns the arithmetic mean, the result of the lab's average.
+func Mean(xs []float64) (float64, error) {
+	if len(xs) == 0 {
+		return 0, ErrEmpty
+	}
+	return Sum(xs) / float64(len(xs)), nil
+}
+
+// Median returns the middle value, or the mean of the two middle values for
+// an even count.
+func Median(xs []float64) (float64, error) {
+	return Percentile(xs, 50)
+}
+
+// Mode returns the most frequent values in ascending order. Every value is a
+// mode when all occur equally often.
+func Mode(xs []float64) ([]float64, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	counts := make(map[float64]int, len(xs))
+	best := 0
+	for _, x := range xs {
+		counts[x]++
+		if counts[x] > best {
+			best = counts[x]
+		}
+	}
+	var modes []float64
+	for x, n := range counts {
+		if n == best {
+			modes = append(modes, x)
+		}
+	}
+	sort.Float64s(modes)
+	return modes, nil
+}
+
+// Variance returns the population variance: the mean squared deviation from
+// the mean.
+func Variance(xs []float64) (float64, error) {
+	ss, err := sumSquares(xs)
+	if err != nil {
+		return 0, err
+	}
+	return ss / float64(len(xs)), nil
+}
+
+// SampleVariance returns the unbiased sample variance, dividing by n-1.
+func SampleVariance(xs []float64) (float64, error) {
+	ss, err := sumSquares(xs)
+	if err != nil {
+		return 0, err
+	}
+	if len(xs) < 2 {
+		return 0, ErrTooFew
+	}
+	return ss / float64(len(xs)-1), nil
+}
+
+// StdDev returns the population standard deviation.
+func StdDev(xs []float64) (float64, error) {
+	v, err := Variance(xs)
+	return math.Sqrt(v), err
+}
+
+// SampleStdDev returns the sample standard deviation.
+func SampleStdDev(xs []float64) (float64, error) {
+	v, err := SampleVariance(xs)
+	return math.Sqrt(v), err
+}
+
+// sumSquares returns the sum of squared deviations from the mean, computed
+// in two passes for accuracy.
+func sumSquares(xs []float64) (float64, error) {
+	m, err := Mean(xs)
+	if err != nil {
+		return 0, err
+	}
+	return deviations(xs, m), nil
+}
+
+// deviations returns the sum of squared deviations of xs from their mean m.
+func deviations(xs []float64, m float64) float64 {
+	var ss, comp float64
+	for _, x := range xs {
+		d := x - m
+		ss += d * d
+		comp += d
+	}
+	// The compensation term corrects for rounding error in the mean.
+	return ss - comp*comp/float64(len(xs))
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 100) using linear
+// interpolation between closest ranks, the method of NumPy's default and
+// Excel's PERCENTILE.INC.
+func Percentile(xs []float64, p float64) (float64, error) {
+	if len(xs) == 0 {
+		return 0, ErrEmpty
+	}
+	if !(p >= 0 && p <= 100) {
+		return 0, fmt.Errorf("%w: %g", ErrPercentile, p)
+	}
+	return percentile(sorted(xs), p), nil
+}
+
+// Percentiles returns several percentiles, sorting the data only once.
+func Percentiles(xs []float64, ps ...float64) ([]float64, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	s := sorted(xs)
+	out := make([]float64, len(ps))
+	for i, p := range ps {
+		if !(p >= 0 && p <= 100) {
+			return nil, fmt.Errorf("%w: %g", ErrPercentile, p)
+		}
+		out[i] = percentile(s, p)
+	}
+	return out, nil
+}
+
+func percentile(s []float64, p float64) float64 {
+	rank := p / 100 * float64(len(s)-1)
+	lo := int(math.Floor(rank))
+	if lo >= len(s)-1 {
+		return s[len(s)-1]
+	}
+	frac := rank - float64(lo)
+	return s[lo] + frac*(s[lo+1]-s[lo])
+}
+
+func sorted(xs []float64) []float64 {
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	return s
+}
+
+// Min returns the smallest value.
+func Min(xs []float64) (float64, error) {
+	lo, _, err := MinMax(xs)
+	return lo, err
+}
+
+// Max returns the largest value.
+func Max(xs []float64) (float64, error) {
+	_, hi, err := MinMax(xs)
+	return hi, err
+}
+
+// MinMax returns the smallest and largest values in one pass.
+func MinMax(xs []float64) (lo, hi float64, err error) {
+	if len(xs) == 0 {
+		return 0, 0, ErrEmpty
+	}
+	lo, hi = xs[0], xs[0]
+	for _, x := range xs[1:] {
+		lo = math.Min(lo, x)
+		hi = math.Max(hi, x)
+	}
+	return lo, hi, nil
+}
+
+// Summary is a five-number summary plus count, mean and standard deviation.
+type Summary struct {
+	Count  int
+	Mean   float64
+	StdDev float64 // population standard deviation
+	Min    float64
+	Q1     float64
+	Median float64
+	Q3     float64
+	Max    float64
+}
+
+// Describe summarizes xs.
+func Describe(xs []float64) (Summary, error) {
+	if len(xs) == 0 {
+		return Summary{}, ErrEmpty
+	}
+	s := sorted(xs)
+	n := float64(len(s))
+	mean := Sum(s) / n
+	return Summary{
+		Count:  len(s),
+		Mean:   mean,
+		StdDev: math.Sqrt(deviations(s, mean) / n),
+		Min:    s[0],
+		Q1:     percentile(s, 25),
+		Median: percentile(s, 50),
+		Q3:     percentile(s, 75),
+		Max:    s[len(s)-1],
+	}, nil
+}
@@ -0,0 +1,226 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+var data = []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+func TestStatistics(t *testing.T) {
+	var tests = []struct {
+		name     string
+		fn       func([]float64) (float64, error)
+		xs       []float64
+		expected float64
+	}{
+		{"Mean", Mean, []float64{1, 2, 3, 4, 5}, 3},
+		{"Mean", Mean, data, 5},
+		{"Median", Median, data, 4.5},
+		{"Median", Median, []float64{3, 1, 2}, 2},
+		{"Variance", Variance, data, 4},
+		{"StdDev", StdDev, data, 2},
+		{"SampleVariance", SampleVariance, data, 32.0 / 7},
+		{"SampleStdDev", SampleStdDev, data, math.Sqrt(32.0 / 7)},
+		{"Min", Min, data, 2},
+		{"Max", Max, data, 9},
+		{"Variance", Variance, []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}, 22.5},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn(test.xs)
+		if err != nil {
+			t.Errorf("%s(%v) returned an error: %v", test.name, test.xs, err)
+		} else if !near(got, test.expected) {
+			t.Errorf("%s(%v) returned %v. Expected %v", test.name, test.xs, got, test.expected)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	fns := map[string]func([]float64) (float64, error){
+		"Mean": Mean, "Median": Median, "Variance": Variance, "SampleVariance": SampleVariance,
+		"StdDev": StdDev, "SampleStdDev": SampleStdDev, "Min": Min, "Max": Max,
+	}
+	for name, fn := range fns {
+		if _, err := fn(nil); err != ErrEmpty {
+			t.Errorf("%s(nil) returned %v. Expected ErrEmpty", name, err)
+		}
+	}
+	if _, err := Mode(nil); err != ErrEmpty {
+		t.Errorf("Mode(nil) returned %v. Expected ErrEmpty", err)
+	}
+	if _, err := Describe([]float64{}); err != ErrEmpty {
+		t.Errorf("Describe([]) returned %v. Expected ErrEmpty", err)
+	}
+	if _, err := SampleVariance([]float64{1}); err != ErrTooFew {
+		t.Errorf("SampleVariance([1]) returned %v. Expected ErrTooFew", err)
+	}
+}
+
+func TestMode(t *testing.T) {
+	var tests = []struct {
+		xs       []float64
+		expected []float64
+	}{
+		{data, []float64{4}},
+		{[]float64{3, 1, 3, 1, 2}, []float64{1, 3}},
+		{[]float64{5}, []float64{5}},
+	}
+	for _, test := range tests {
+		got, err := Mode(test.xs)
+		if err != nil || !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Mode(%v) returned %v, %v. Expected %v", test.xs, got, err, test.expected)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	xs := []float64{15, 20, 35, 40, 50}
+	var tests = []struct {
+		p        float64
+		expected float64
+	}{
+		{0, 15}, {25, 20}, {40, 29}, {50, 35}, {90, 46}, {100, 50},
+	}
+	for _, test := range tests {
+		got, err := Percentile(xs, test.p)
+		if err != nil || !near(got, test.expected) {
+			t.Errorf("Percentile(%v, %v) returned %v, %v. Expected %v", xs, test.p, got, err, test.expected)
+		}
+	}
+	if _, err := Percentile(xs, 101); !errors.Is(err, ErrPercentile) {
+		t.Errorf("Percentile(xs, 101) returned %v. Expected ErrPercentile", err)
+	}
+	got, err := Percentiles(xs, 25, 50, 75)
+	if err != nil || !reflect.DeepEqual(got, []float64{20, 35, 40}) {
+		t.Errorf("Percentiles(xs, 25, 50, 75) returned %v, %v. Expected [20 35 40]", got, err)
+	}
+	if xs[0] != 15 || xs[4] != 50 {
+		t.Errorf("Percentile modified its input: %v", xs)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	got, err := Describe(data)
+	expected := Summary{Count: 8, Mean: 5, StdDev: 2, Min: 2, Q1: 4, Median: 4.5, Q3: 5.5, Max: 9}
+	if err != nil || got != expected {
+		t.Errorf("Describe(%v) returned %+v, %v. Expected %+v", data, got, err, expected)
+	}
+}
+
+func TestAccumulator(t *testing.T) {
+	var a Accumulator
+	if _, err := a.Mean(); err != ErrEmpty {
+		t.Errorf("Mean of an empty Accumulator returned %v. Expected ErrEmpty", err)
+	}
+	a.AddAll(data...)
+	check := func(name string, got, expected float64, err error) {
+		t.Helper()
+		if err != nil || !near(got, expected) {
+			t.Errorf("Accumulator.%s returned %v, %v. Expected %v", name, got, err, expected)
+		}
+	}
+	m, err := a.Mean()
+	check("Mean", m, 5, err)
+	v, err := a.Variance()
+	check("Variance", v, 4, err)
+	sv, err := a.SampleStdDev()
+	check("SampleStdDev", sv, math.Sqrt(32.0/7), err)
+	lo, err := a.Min()
+	check("Min", lo, 2, err)
+	hi, err := a.Max()
+	check("Max", hi, 9, err)
+	if a.Count() != len(data) {
+		t.Errorf("Accumulator.Count returned %d. Expected %d", a.Count(), len(data))
+	}
+}
+
+func TestAccumulatorMerge(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	xs := make([]float64, 1000)
+	for i := range xs {
+		xs[i] = rng.NormFloat64()*10 + 1e6
+	}
+
+	var whole, left, right, empty Accumulator
+	whole.AddAll(xs...)
+	left.AddAll(xs[:300]...)
+	right.AddAll(xs[300:]...)
+	left.Merge(right)
+	left.Merge(empty)
+	empty.Merge(left)
+
+	for _, a := range []Accumulator{left, empty} {
+		wm, _ := whole.Mean()
+		am, _ := a.Mean()
+		wv, _ := whole.Variance()
+		av, _ := a.Variance()
+		bv, _ := Variance(xs)
+		if a.Count() != 1000 || !near(am, wm) || !near(av, wv) || !near(av, bv) {
+			t.Errorf("merged Accumulator has n=%d mean=%v var=%v. Expected n=1000 mean=%v var=%v",
+				a.Count(), am, av, wm, bv)
+		}
+	}
+}
+
+func BenchmarkAccumulator(b *testing.B) {
+	var a Accumulator
+	for i := 0; i < b.N; i++ {
+		a.Add(float64(i))
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	got, err := Histogram([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 5)
+	expected := []Bin{{0, 2, 2}, {2, 4, 2}, {4, 6, 2}, {6, 8, 2}, {8, 10, 3}}
+	if err != nil || !reflect.DeepEqual(got, expected) {
+		t.Errorf("Histogram(0..10, 5) returned %v, %v. Expected %v", got, err, expected)
+	}
+	got, err = Histogram([]float64{3, 3, 3}, 4)
+	if err != nil || !reflect.DeepEqual(got, []Bin{{3, 3, 3}}) {
+		t.Errorf("Histogram([3 3 3], 4) returned %v, %v. Expected one bin", got, err)
+	}
+	if _, err := Histogram(nil, 4); err != ErrEmpty {
+		t.Errorf("Histogram(nil, 4) returned %v. Expected ErrEmpty", err)
+	}
+	for _, xs := range [][]float64{{1, math.Inf(1)}, {math.Inf(-1), 1}, {-math.MaxFloat64, math.MaxFloat64}} {
+		if got, err := Histogram(xs, 4); err == nil {
+			t.Errorf("Histogram(%v, 4) returned %v. Expected an error", xs, got)
+		}
+	}
+}
+
+func TestReadColumns(t *testing.T) {
+	var tests = []struct {
+		input    string
+		comma    rune
+		expected []Column
+	}{
+		{"1\n2\n\n3.5\n", ',', []Column{{Name: "col1", Values: []float64{1, 2, 3.5}}}},
+		{"height, weight\n180, 75\n165,n/a\n# comment\n170,\n", ',', []Column{
+			{Name: "height", Values: []float64{180, 165, 170}},
+			{Name: "weight", Values: []float64{75}, Invalid: 1},
+		}},
+		{"1\t2\n3\t4\t5\n", '\t', []Column{
+			{Name: "col1", Values: []float64{1, 3}},
+			{Name: "col2", Values: []float64{2, 4}},
+			{Name: "col3", Values: []float64{5}},
+		}},
+		{"1\ninf\n-Inf\nNaN\n2\n", ',', []Column{{Name: "col1", Values: []float64{1, 2}, Invalid: 3}}},
+	}
+	for _, test := range tests {
+		got, err := ReadColumns(strings.NewReader(test.input), test.comma)
+		if err != nil || !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("ReadColumns(%q) returned %+v, %v. Expected %+v", test.input, got, err, test.expected)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Command statstool prints descriptive statistics for columns of numbers,
+// the command-line counterpart of the average lab.
+//
+// Usage:
+//
+//	statstool [-d DELIM] [-bins N] [-width W] [-json] [FILE]
+//
+// Input is CSV, or any delimited text, from FILE or standard input. One
+// number per line is a single column. A first row that is not numeric is
+// used as column names. For each column statstool prints count, mean,
+// standard deviation and the five-number summary, then a histogram:
+//
+//	$ printf '1\n2\n2\n3\n9\n' | statstool -bins 4
+//	column  count  invalid  mean  stddev  min  q1  median  q3  max
+//	col1    5      0        3.4   2.871   1    2   2       3   9
+//
+//	col1
+//	  [1, 3)  ######################################## 3
+//	  [3, 5)  ############## 1
+//	  [5, 7)   0
+//	  [7, 9]  ############## 1
+//
+// With -json the same results are written as a JSON array for scripts.
+//
+//golden:args -bins 4 testdata/heights.csv
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"go-labs/04_functions/01_basic_syntax/stats"
+)
+
+// result is the JSON form of one column.
+type result struct {
+	Column    string      `json:"column"`
+	Invalid   int         `json:"invalid"`
+	Summary   *summary    `json:"summary,omitempty"`
+	Histogram []stats.Bin `json:"histogram,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+type summary struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Q1     float64 `json:"q1"`
+	Median float64 `json:"median"`
+	Q3     float64 `json:"q3"`
+	Max    float64 `json:"max"`
+}
+
+func main() {
+	delim := flag.String("d", ",", "field delimiter (use \\t for tabs)")
+	bins := flag.Int("bins", 10, "histogram bins per column (0 to disable)")
+	width := flag.Int("width", 40, "width of the longest histogram bar")
+	asJSON := flag.Bool("json", false, "write results as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: statstool [-d DELIM] [-bins N] [-width W] [-json] [FILE]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *bins < 0 || *width < 1 {
+		fmt.Fprintln(os.Stderr, "statstool: -bins must be at least 0 and -width at least 1")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Args(), *delim, *bins, *width, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, "statstool:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, delim string, bins, width int, asJSON bool) error {
+	comma, err := parseDelim(delim)
+	if err != nil {
+		return err
+	}
+	var in io.Reader = os.Stdin
+	switch {
+	case len(args) > 1:
+		return errors.New("at most one FILE may be given")
+	case len(args) == 1 && args[0] != "-":
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	cols, err := stats.ReadColumns(in, comma)
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		return stats.ErrEmpty
+	}
+	results := make([]result, len(cols))
+	for i, c := range cols {
+		results[i] = analyze(c, bins)
+	}
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	printTable(os.Stdout, results)
+	if bins > 0 {
+		for _, r := range results {
+			printHistogram(os.Stdout, r, width)
+		}
+	}
+	return nil
+}
+
+func parseDelim(s string) (rune, error) {
+	switch s {
+	case `\t`, "tab":
+		return '\t', nil
+	case "space":
+		return ' ', nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) {
+		return 0, fmt.Errorf("delimiter must be a single character, got %q", s)
+	}
+	return r, nil
+}
+
+func analyze(c stats.Column, bins int) result {
+	r := result{Column: c.Name, Invalid: c.Invalid}
+	s, err := stats.Describe(c.Values)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Summary = &summary{s.Count, s.Mean, s.StdDev, s.Min, s.Q1, s.Median, s.Q3, s.Max}
+	if bins > 0 {
+		r.Histogram, err = stats.Histogram(c.Values, bins)
+		if err != nil {
+			r.Error = err.Error()
+		}
+	}
+	return r
+}
+
+func printTable(w io.Writer, results []result) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "column\tcount\tinvalid\tmean\tstddev\tmin\tq1\tmedian\tq3\tmax")
+	for _, r := range results {
+		if r.Summary == nil {
+			fmt.Fprintf(tw, "%s\t0\t%d\t-\t-\t-\t-\t-\t-\t-\n", r.Column, r.Invalid)
+			continue
+		}
+		s := r.Summary
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Column, s.Count, r.Invalid,
+			num(s.Mean), num(s.StdDev), num(s.Min), num(s.Q1), num(s.Median), num(s.Q3), num(s.Max))
+	}
+	tw.Flush()
+}
+
+// printHistogram draws one bar per bin, scaled so the fullest bin is width
+// characters long.
+func printHistogram(w io.Writer, r result, width int) {
+	if len(r.Histogram) == 0 {
+		if r.Summary != nil && r.Error != "" {
+			fmt.Fprintf(w, "\n%s\n  %s\n", r.Column, r.Error)
+		}
+		return
+	}
+	most := 0
+	labels := make([]string, len(r.Histogram))
+	labelWidth := 0
+	for i, b := range r.Histogram {
+		if b.Count > most {
+			most = b.Count
+		}
+		end := ")"
+		if i == len(r.Histogram)-1 {
+			end = "]"
+		}
+		labels[i] = "[" + num(b.Lo) + ", " + num(b.Hi) + end
+		if n := utf8.RuneCountInString(labels[i]); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	fmt.Fprintf(w, "\n%s\n", r.Column)
+	for i, b := range r.Histogram {
+		bar := 0
+		if most > 0 {
+			bar = (b.Count*width + most - 1) / most
+		}
+		fmt.Fprintf(w, "  %-*s  %s %d\n", labelWidth, labels[i], strings.Repeat("#", bar), b.Count)
+	}
+}
+
+// num formats a statistic with at most four significant decimals.
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
@@ -1,4 +1,6 @@
-// maps_examples.go
+// maps.go
+
+//golden:unordered (?m)(^  \w+ -> \d+\n)+
 package main
 
 import (
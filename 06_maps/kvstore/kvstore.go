@@ -0,0 +1,342 @@
+// Package kvstore is an embedded key-value store with a map-like API, backed
+// by an append-only log file and an in-memory hash index.
+//
+// Every Put or Delete appends a checksummed record to the log; the index maps
+// each live key to the offset of its latest record. Overwritten and deleted
+// records stay in the file until Compact rewrites it with live records only.
+// When a DB is opened, the log is replayed to rebuild the index, and a torn
+// or corrupt tail (for example from a crash mid-write) is truncated away.
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Options configure a DB.
+type Options struct {
+	// Sync calls fsync after every write. Without it, recent writes may be
+	// lost on power failure, though the log stays consistent.
+	Sync bool
+}
+
+// Stats describe the state of the log file.
+type Stats struct {
+	Keys      int   // live keys
+	Records   int   // records in the log, including stale ones
+	FileSize  int64 // bytes, including the file header
+	DeadBytes int64 // bytes of stale records reclaimable by Compact
+	Recovered int64 // bytes of torn or corrupt tail truncated by Open
+}
+
+type entry struct {
+	off  int64
+	size int64
+}
+
+// DB is an open store. It is safe for concurrent use.
+type DB struct {
+	mu    sync.RWMutex
+	path  string
+	opts  Options
+	f     *os.File
+	index map[string]entry
+	stats Stats
+}
+
+// Open opens or creates the data file at path and rebuilds the index from it.
+func Open(path string, opts *Options) (*DB, error) {
+	db := &DB{path: path, index: map[string]entry{}}
+	if opts != nil {
+		db.opts = *opts
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	db.f = f
+	if err := db.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// load validates the file header and replays the log.
+func (db *DB) load() error {
+	info, err := db.f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		if _, err := db.f.WriteAt([]byte(magic), 0); err != nil {
+			return err
+		}
+		db.stats.FileSize = int64(len(magic))
+		return db.sync()
+	}
+	hdr := make([]byte, len(magic))
+	if _, err := db.f.ReadAt(hdr, 0); err != nil || string(hdr) != magic {
+		return fmt.Errorf("%w: %s", ErrBadMagic, db.path)
+	}
+
+	off := int64(len(magic))
+	for {
+		rec, err := readRecord(db.f, off)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !errors.Is(err, ErrCorrupt) {
+				return err
+			}
+			// Everything from the first bad record on is discarded.
+			db.stats.Recovered = info.Size() - off
+			if err := db.f.Truncate(off); err != nil {
+				return err
+			}
+			break
+		}
+		db.apply(rec)
+		off += rec.Size
+	}
+	db.stats.FileSize = off
+	return nil
+}
+
+// apply updates the index and statistics for a record at the end of the log.
+func (db *DB) apply(rec Record) {
+	db.stats.Records++
+	if old, ok := db.index[rec.Key]; ok {
+		db.stats.DeadBytes += old.size
+	}
+	if rec.Tombstone {
+		delete(db.index, rec.Key)
+		db.stats.DeadBytes += rec.Size
+		return
+	}
+	db.index[rec.Key] = entry{off: rec.Offset, size: rec.Size}
+}
+
+func (db *DB) sync() error {
+	if db.opts.Sync {
+		return db.f.Sync()
+	}
+	return nil
+}
+
+// Get returns the value stored under key, or ErrNotFound.
+func (db *DB) Get(key string) ([]byte, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.f == nil {
+		return nil, ErrClosed
+	}
+	e, ok := db.index[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	rec, err := readRecord(db.f, e.off)
+	if err != nil {
+		return nil, err
+	}
+	return rec.Value, nil
+}
+
+// Has reports whether key is in the store.
+func (db *DB) Has(key string) bool {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	_, ok := db.index[key]
+	return ok
+}
+
+// Len returns the number of live keys.
+func (db *DB) Len() int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return len(db.index)
+}
+
+// Put stores value under key, replacing any previous value.
+func (db *DB) Put(key string, value []byte) error {
+	if len(key) > MaxKeySize || len(value) > MaxValueSize {
+		return fmt.Errorf("kvstore: key or value too large")
+	}
+	return db.append(key, value, false)
+}
+
+// Delete removes key. Like the built-in delete, it is a no-op for missing keys.
+func (db *DB) Delete(key string) error {
+	return db.append(key, nil, true)
+}
+
+func (db *DB) append(key string, value []byte, tombstone bool) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.f == nil {
+		return ErrClosed
+	}
+	if _, ok := db.index[key]; tombstone && !ok {
+		return nil
+	}
+	buf := encodeRecord(key, value, tombstone)
+	off := db.stats.FileSize
+	if _, err := db.f.WriteAt(buf, off); err != nil {
+		// Drop a partial write so the log stays well-formed.
+		_ = db.f.Truncate(off)
+		return err
+	}
+	if err := db.sync(); err != nil {
+		return err
+	}
+	db.stats.FileSize += int64(len(buf))
+	db.apply(Record{Offset: off, Size: int64(len(buf)), Key: key, Tombstone: tombstone})
+	return nil
+}
+
+// Keys returns the live keys with the given prefix in sorted order.
+func (db *DB) Keys(prefix string) []string {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.keys(prefix)
+}
+
+func (db *DB) keys(prefix string) []string {
+	var keys []string
+	for k := range db.index {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Scan calls fn for each live key with the given prefix, in sorted key order,
+// stopping at the first error fn returns. fn must not modify the DB.
+func (db *DB) Scan(prefix string, fn func(key string, value []byte) error) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.f == nil {
+		return ErrClosed
+	}
+	for _, k := range db.keys(prefix) {
+		rec, err := readRecord(db.f, db.index[k].off)
+		if err != nil {
+			return err
+		}
+		if err := fn(k, rec.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats returns statistics about the log file.
+func (db *DB) Stats() Stats {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	s := db.stats
+	s.Keys = len(db.index)
+	return s
+}
+
+// Compact rewrites the log so that it only holds the latest record of each
+// live key. The new file is written next to the old one and renamed over it,
+// so a crash during compaction leaves the original file intact.
+func (db *DB) Compact() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.f == nil {
+		return ErrClosed
+	}
+
+	tmpPath := db.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	fail := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	index := make(map[string]entry, len(db.index))
+	off := int64(len(magic))
+	if _, err := tmp.WriteAt([]byte(magic), 0); err != nil {
+		return fail(err)
+	}
+	for _, k := range db.keys("") {
+		rec, err := readRecord(db.f, db.index[k].off)
+		if err != nil {
+			return fail(err)
+		}
+		buf := encodeRecord(k, rec.Value, false)
+		if _, err := tmp.WriteAt(buf, off); err != nil {
+			return fail(err)
+		}
+		index[k] = entry{off: off, size: int64(len(buf))}
+		off += int64(len(buf))
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := os.Rename(tmpPath, db.path); err != nil {
+		return fail(err)
+	}
+
+	db.f.Close()
+	db.f = tmp
+	db.index = index
+	db.stats = Stats{Records: len(index), FileSize: off}
+	return nil
+}
+
+// Close closes the data file. Further operations return ErrClosed.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.f == nil {
+		return ErrClosed
+	}
+	err := db.f.Close()
+	db.f = nil
+	return err
+}
+
+// Inspect reads the data file at path without modifying it and calls fn for
+// every record in log order. A torn or corrupt record ends the walk with an
+// error that reports its offset.
+func Inspect(path string, fn func(Record) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hdr := make([]byte, len(magic))
+	if _, err := f.ReadAt(hdr, 0); err != nil || string(hdr) != magic {
+		return fmt.Errorf("%w: %s", ErrBadMagic, path)
+	}
+	off := int64(len(magic))
+	for {
+		rec, err := readRecord(f, off)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+		off += rec.Size
+	}
+}
@@ -0,0 +1,251 @@
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func openTemp(t *testing.T) (*DB, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.kv")
+	db, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, path
+}
+
+func TestPutGetDelete(t *testing.T) {
+	db, _ := openTemp(t)
+	defer db.Close()
+
+	if err := db.Put("Alice", []byte("30")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put("Bob", []byte("25")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put("Bob", []byte("26")); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := db.Get("Bob"); err != nil || string(v) != "26" {
+		t.Errorf("Get(Bob) returned %q, %v. Expected 26", v, err)
+	}
+	if err := db.Delete("Alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get("Alice"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(Alice) after delete returned %v. Expected ErrNotFound", err)
+	}
+	if err := db.Delete("Charlie"); err != nil {
+		t.Errorf("Delete of a missing key returned %v. Expected nil", err)
+	}
+	if db.Len() != 1 {
+		t.Errorf("Len() returned %d. Expected 1", db.Len())
+	}
+	if s := db.Stats(); s.Records != 4 {
+		t.Errorf("Stats().Records returned %d. Expected 4", s.Records)
+	}
+}
+
+func TestScanPrefix(t *testing.T) {
+	db, _ := openTemp(t)
+	defer db.Close()
+
+	for _, k := range []string{"fruit/banana", "veg/carrot", "fruit/apple", "fruit/orange"} {
+		if err := db.Put(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []string
+	err := db.Scan("fruit/", func(key string, value []byte) error {
+		if key != string(value) {
+			t.Errorf("Scan returned value %q for key %q", value, key)
+		}
+		got = append(got, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"fruit/apple", "fruit/banana", "fruit/orange"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Scan(fruit/) visited %v. Expected %v", got, expected)
+	}
+
+	stop := errors.New("stop")
+	n := 0
+	err = db.Scan("", func(string, []byte) error { n++; return stop })
+	if err != stop || n != 1 {
+		t.Errorf("Scan did not stop at the first error: %v after %d calls", err, n)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	db, path := openTemp(t)
+	for i := 0; i < 10; i++ {
+		if err := db.Put(fmt.Sprintf("k%d", i%3), []byte(fmt.Sprint(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Delete("k1")
+	db.Close()
+
+	db, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if got := db.Keys(""); !reflect.DeepEqual(got, []string{"k0", "k2"}) {
+		t.Errorf("Keys after reopen returned %v. Expected [k0 k2]", got)
+	}
+	if v, _ := db.Get("k0"); string(v) != "9" {
+		t.Errorf("Get(k0) after reopen returned %q. Expected 9", v)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	db, path := openTemp(t)
+	defer db.Close()
+	for i := 0; i < 100; i++ {
+		db.Put("counter", []byte(fmt.Sprint(i)))
+	}
+	db.Put("gone", []byte("x"))
+	db.Delete("gone")
+
+	before := db.Stats()
+	if before.DeadBytes == 0 {
+		t.Fatal("Expected dead bytes before compaction")
+	}
+	if err := db.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	after := db.Stats()
+	if after.DeadBytes != 0 || after.Records != 1 || after.FileSize >= before.FileSize {
+		t.Errorf("Stats after Compact: %+v (before %+v)", after, before)
+	}
+	if v, _ := db.Get("counter"); string(v) != "99" {
+		t.Errorf("Get(counter) after Compact returned %q. Expected 99", v)
+	}
+	if err := db.Put("new", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(path)
+	if info.Size() != db.Stats().FileSize {
+		t.Errorf("file size %d does not match Stats().FileSize %d", info.Size(), db.Stats().FileSize)
+	}
+}
+
+func TestRecoverTornWrite(t *testing.T) {
+	db, path := openTemp(t)
+	db.Put("a", []byte("1"))
+	db.Put("b", []byte("2"))
+	good := db.Stats().FileSize
+	db.Close()
+
+	// Simulate a crash in the middle of appending a record.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.Write(encodeRecord("c", []byte("3"), false)[:7])
+	f.Close()
+
+	db, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if s := db.Stats(); s.Recovered != 7 || s.FileSize != good || s.Keys != 2 {
+		t.Errorf("Stats after recovery: %+v", s)
+	}
+	if err := db.Put("c", []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := db.Get("c"); string(v) != "3" {
+		t.Errorf("Get(c) after recovery returned %q. Expected 3", v)
+	}
+}
+
+func TestCorruptRecord(t *testing.T) {
+	db, path := openTemp(t)
+	db.Put("a", []byte("hello"))
+	db.Put("b", []byte("world"))
+	db.Close()
+
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 0xff // flip a bit in the last value
+	os.WriteFile(path, data, 0o644)
+
+	err := Inspect(path, func(Record) error { return nil })
+	if !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Inspect returned %v. Expected ErrCorrupt", err)
+	}
+
+	db, err = Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if db.Has("b") || !db.Has("a") {
+		t.Errorf("Expected the corrupt record for b to be dropped, keys: %v", db.Keys(""))
+	}
+}
+
+func TestBadMagic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not.kv")
+	os.WriteFile(path, []byte("hello world"), 0o644)
+	if _, err := Open(path, nil); !errors.Is(err, ErrBadMagic) {
+		t.Errorf("Open returned %v. Expected ErrBadMagic", err)
+	}
+}
+
+func TestClosed(t *testing.T) {
+	db, _ := openTemp(t)
+	db.Close()
+	if err := db.Put("a", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Put after Close returned %v. Expected ErrClosed", err)
+	}
+	if _, err := db.Get("a"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Get after Close returned %v. Expected ErrClosed", err)
+	}
+}
+
+// failingReader returns the bytes of a record, then fails.
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (r failingReader) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(r.data)) {
+		return 0, r.err
+	}
+	n := copy(p, r.data[off:])
+	if n < len(p) {
+		return n, r.err
+	}
+	return n, nil
+}
+
+func TestReadRecordShort(t *testing.T) {
+	rec := encodeRecord("key", []byte("value"), false)
+	errDisk := errors.New("disk failure")
+	var tests = []struct {
+		r        failingReader
+		expected error
+	}{
+		{failingReader{rec[:len(rec)-2], io.EOF}, ErrCorrupt},
+		{failingReader{rec[:5], io.EOF}, ErrCorrupt},
+		{failingReader{rec[:len(rec)-2], errDisk}, errDisk},
+		{failingReader{rec[:5], errDisk}, errDisk},
+	}
+	for _, test := range tests {
+		if _, err := readRecord(test.r, 0); !errors.Is(err, test.expected) {
+			t.Errorf("readRecord of %d of %d bytes, then %v, returned %v. Expected %v",
+				len(test.r.data), len(rec), test.r.err, err, test.expected)
+		}
+	}
+}
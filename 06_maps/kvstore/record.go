@@ -0,0 +1,113 @@
+package kvstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// On-disk layout. A data file starts with an 8-byte magic and is followed by
+// records, each of which is:
+//
+//	crc32   uint32  // Castagnoli CRC of everything after this field
+//	flags   uint8   // flagTombstone for deletions
+//	keyLen  uint32
+//	valLen  uint32
+//	key     [keyLen]byte
+//	value   [valLen]byte
+//
+// All integers are little-endian.
+const (
+	magic      = "GOLABSKV"
+	headerSize = 4 + 1 + 4 + 4
+
+	flagTombstone = 1 << 0
+
+	// MaxKeySize and MaxValueSize bound record sizes so that a corrupt length
+	// field cannot trigger a huge allocation during recovery.
+	MaxKeySize   = 1 << 16
+	MaxValueSize = 1 << 28
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var (
+	// ErrNotFound is returned by Get for keys that are not in the store.
+	ErrNotFound = errors.New("kvstore: key not found")
+	// ErrCorrupt is returned when a record fails its CRC check or has
+	// impossible lengths.
+	ErrCorrupt = errors.New("kvstore: corrupt record")
+	// ErrClosed is returned by operations on a closed DB.
+	ErrClosed = errors.New("kvstore: database is closed")
+	// ErrBadMagic is returned when a file is not a kvstore data file.
+	ErrBadMagic = errors.New("kvstore: not a kvstore data file")
+)
+
+// Record is a decoded log record, as reported by Inspect.
+type Record struct {
+	Offset    int64
+	Size      int64
+	Key       string
+	Value     []byte
+	Tombstone bool
+}
+
+func encodeRecord(key string, value []byte, tombstone bool) []byte {
+	buf := make([]byte, headerSize+len(key)+len(value))
+	if tombstone {
+		buf[4] = flagTombstone
+	}
+	binary.LittleEndian.PutUint32(buf[5:], uint32(len(key)))
+	binary.LittleEndian.PutUint32(buf[9:], uint32(len(value)))
+	copy(buf[headerSize:], key)
+	copy(buf[headerSize+len(key):], value)
+	binary.LittleEndian.PutUint32(buf[0:], crc32.Checksum(buf[4:], crcTable))
+	return buf
+}
+
+// readRecord decodes the record at off. A clean end of file yields io.EOF,
+// and a record cut short by the end of the file, like a damaged one, an
+// error wrapping ErrCorrupt. Other read errors are returned as they are.
+func readRecord(r io.ReaderAt, off int64) (Record, error) {
+	var hdr [headerSize]byte
+	n, err := r.ReadAt(hdr[:], off)
+	if n == 0 && err == io.EOF {
+		return Record{}, io.EOF
+	}
+	if n < headerSize {
+		return Record{}, shortRead(err, off)
+	}
+	flags := hdr[4]
+	keyLen := binary.LittleEndian.Uint32(hdr[5:])
+	valLen := binary.LittleEndian.Uint32(hdr[9:])
+	if flags&^flagTombstone != 0 || keyLen > MaxKeySize || valLen > MaxValueSize {
+		return Record{}, fmt.Errorf("%w at offset %d", ErrCorrupt, off)
+	}
+
+	body := make([]byte, int(keyLen)+int(valLen))
+	if n, err := r.ReadAt(body, off+headerSize); n < len(body) {
+		return Record{}, shortRead(err, off)
+	}
+	crc := crc32.Update(crc32.Checksum(hdr[4:], crcTable), crcTable, body)
+	if crc != binary.LittleEndian.Uint32(hdr[0:]) {
+		return Record{}, fmt.Errorf("%w at offset %d: checksum mismatch", ErrCorrupt, off)
+	}
+	return Record{
+		Offset:    off,
+		Size:      int64(headerSize + len(body)),
+		Key:       string(body[:keyLen]),
+		Value:     body[keyLen:],
+		Tombstone: flags&flagTombstone != 0,
+	}, nil
+}
+
+// shortRead returns the error for a record at off that could not be read in
+// full: err itself, unless the file simply ended.
+func shortRead(err error, off int64) error {
+	if err == nil || err == io.EOF {
+		return fmt.Errorf("%w at offset %d: truncated record", ErrCorrupt, off)
+	}
+	return err
+}
@@ -0,0 +1,281 @@
+// Package mappath reads and writes nested map[string]any data, such as the
+// result of decoding JSON into an interface{}, using dotted paths
+// ("row1.col2", "items[0].name") or JSON Pointers ("/row1/col2").
+//
+// It replaces the two-step lookups and manual inner-map creation needed by
+// composite maps like map[string]map[string]string:
+//
+//	data := map[string]any{}
+//	_ = mappath.Set(data, "Li.state", "solid") // creates data["Li"] on the fly
+//	state, _ := mappath.Lookup[string](data, "Li.state")
+package mappath
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrSyntax is returned when a path cannot be parsed.
+	ErrSyntax = errors.New("invalid path syntax")
+	// ErrNotFound is returned when a key or index does not exist.
+	ErrNotFound = errors.New("segment not found")
+	// ErrTypeMismatch is returned when a segment cannot be applied to the value
+	// it addresses, e.g. an index on a map or a key on a string.
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrIndexOutOfRange is returned for slice indices past the end of a slice.
+	ErrIndexOutOfRange = errors.New("index out of range")
+)
+
+// PathError records the path and the segment at which an operation failed.
+type PathError struct {
+	Path    string
+	Segment string
+	Err     error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("mappath: %s at %q in %q", e.Err, e.Segment, e.Path)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Get returns the value at path. Besides map[string]any and []any it also
+// walks other maps with string keys and other slices, so composite maps such
+// as map[string]map[string]string can be read directly.
+func Get(data any, path string) (any, error) {
+	p, err := Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(data, path)
+}
+
+// Get returns the value at p. The name is used in errors.
+func (p Path) Get(data any, name string) (any, error) {
+	cur := data
+	for i, seg := range p {
+		next, err := step(cur, seg)
+		if err != nil {
+			return nil, &PathError{Path: name, Segment: p[:i+1].String(), Err: err}
+		}
+		cur = next
+	}
+	return cur, nil
+}
+
+// step applies a single segment to a container.
+func step(cur any, seg Segment) (any, error) {
+	switch c := cur.(type) {
+	case map[string]any:
+		if seg.IsIndex {
+			return nil, ErrTypeMismatch
+		}
+		v, ok := c[seg.Key]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		return v, nil
+	case []any:
+		i, ok := seg.index(len(c))
+		if !ok {
+			return nil, ErrTypeMismatch
+		}
+		if i >= len(c) {
+			return nil, ErrIndexOutOfRange
+		}
+		return c[i], nil
+	case nil:
+		return nil, ErrNotFound
+	}
+
+	rv := reflect.ValueOf(cur)
+	switch rv.Kind() {
+	case reflect.Map:
+		if seg.IsIndex || rv.Type().Key().Kind() != reflect.String {
+			return nil, ErrTypeMismatch
+		}
+		v := rv.MapIndex(reflect.ValueOf(seg.Key).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, ErrNotFound
+		}
+		return v.Interface(), nil
+	case reflect.Slice, reflect.Array:
+		i, ok := seg.index(rv.Len())
+		if !ok {
+			return nil, ErrTypeMismatch
+		}
+		if i >= rv.Len() {
+			return nil, ErrIndexOutOfRange
+		}
+		return rv.Index(i).Interface(), nil
+	}
+	return nil, ErrTypeMismatch
+}
+
+// Lookup returns the value at path converted to T. A value of another type
+// yields an error wrapping ErrTypeMismatch.
+func Lookup[T any](data any, path string) (T, error) {
+	var zero T
+	v, err := Get(data, path)
+	if err != nil {
+		return zero, err
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, &PathError{
+			Path:    path,
+			Segment: path,
+			Err:     fmt.Errorf("%w: have %T, want %T", ErrTypeMismatch, v, zero),
+		}
+	}
+	return t, nil
+}
+
+// Has reports whether a value exists at path.
+func Has(data any, path string) bool {
+	_, err := Get(data, path)
+	return err == nil
+}
+
+// Set stores value at path, creating intermediate maps as needed. A bracketed
+// index segment creates a []any instead; slices grow by one when the index
+// equals their length (or with the JSON Pointer "-" token).
+func Set(data map[string]any, path string, value any) error {
+	if data == nil {
+		return &PathError{Path: path, Segment: "", Err: ErrTypeMismatch}
+	}
+	p, err := Parse(path)
+	if err != nil {
+		return err
+	}
+	if len(p) == 0 {
+		return &PathError{Path: path, Segment: "", Err: ErrSyntax}
+	}
+	_, err = set(data, p, 0, path, value)
+	return err
+}
+
+// set writes value below cur and returns the (possibly reallocated) container
+// so that the caller can store grown slices back into their parent.
+func set(cur any, p Path, i int, name string, value any) (any, error) {
+	seg := p[i]
+	last := i == len(p)-1
+	fail := func(err error) (any, error) {
+		return nil, &PathError{Path: name, Segment: p[:i+1].String(), Err: err}
+	}
+
+	switch c := cur.(type) {
+	case map[string]any:
+		if seg.IsIndex {
+			return fail(ErrTypeMismatch)
+		}
+		if last {
+			c[seg.Key] = value
+			return c, nil
+		}
+		child, ok := c[seg.Key]
+		if !ok || child == nil {
+			child = newContainer(p[i+1])
+		}
+		child, err := set(child, p, i+1, name, value)
+		if err != nil {
+			return nil, err
+		}
+		c[seg.Key] = child
+		return c, nil
+	case []any:
+		n, ok := seg.index(len(c))
+		if !ok {
+			return fail(ErrTypeMismatch)
+		}
+		if n > len(c) {
+			return fail(ErrIndexOutOfRange)
+		}
+		if n == len(c) {
+			c = append(c, nil)
+		}
+		if last {
+			c[n] = value
+			return c, nil
+		}
+		child := c[n]
+		if child == nil {
+			child = newContainer(p[i+1])
+		}
+		child, err := set(child, p, i+1, name, value)
+		if err != nil {
+			return nil, err
+		}
+		c[n] = child
+		return c, nil
+	}
+	return fail(ErrTypeMismatch)
+}
+
+// newContainer returns an empty container suitable for the next segment.
+func newContainer(next Segment) any {
+	if next.IsIndex {
+		return []any{}
+	}
+	return map[string]any{}
+}
+
+// Delete removes the value at path. Deleting from a slice removes the element
+// and shifts the remaining ones down.
+func Delete(data map[string]any, path string) error {
+	p, err := Parse(path)
+	if err != nil {
+		return err
+	}
+	if len(p) == 0 {
+		return &PathError{Path: path, Segment: "", Err: ErrSyntax}
+	}
+	parentPath, last := p[:len(p)-1], p[len(p)-1]
+	fail := func(err error) error {
+		return &PathError{Path: path, Segment: p.String(), Err: err}
+	}
+
+	if len(parentPath) > 0 {
+		grand, err := parentPath[:len(parentPath)-1].Get(data, path)
+		if err != nil {
+			return err
+		}
+		parent, err := parentPath.Get(data, path)
+		if err != nil {
+			return err
+		}
+		if s, ok := parent.([]any); ok {
+			n, ok := last.index(len(s))
+			if !ok {
+				return fail(ErrTypeMismatch)
+			}
+			if n >= len(s) {
+				return fail(ErrIndexOutOfRange)
+			}
+			s = append(s[:n], s[n+1:]...)
+			_, err := set(grand, parentPath, len(parentPath)-1, path, s)
+			return err
+		}
+		m, ok := parent.(map[string]any)
+		if !ok || last.IsIndex {
+			return fail(ErrTypeMismatch)
+		}
+		return deleteKey(m, last.Key, fail)
+	}
+	if last.IsIndex {
+		return fail(ErrTypeMismatch)
+	}
+	return deleteKey(data, last.Key, fail)
+}
+
+func deleteKey(m map[string]any, key string, fail func(error) error) error {
+	if _, ok := m[key]; !ok {
+		return fail(ErrNotFound)
+	}
+	delete(m, key)
+	return nil
+}
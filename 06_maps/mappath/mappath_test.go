@@ -0,0 +1,169 @@
+package mappath
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const doc = `{
+	"matrix": {"row1": {"col1": 1, "col2": 2}, "row2": {"col1": 3}},
+	"groups": {"fruits": ["apple", "banana"]},
+	"a/b": {"m~n": true}
+}`
+
+func decode(t *testing.T) map[string]any {
+	t.Helper()
+	var data map[string]any
+	if err := json.Unmarshal([]byte(doc), &data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestGet(t *testing.T) {
+	data := decode(t)
+	var tests = []struct {
+		path     string
+		expected any
+	}{
+		{"matrix.row1.col2", 2.0},
+		{"/matrix/row2/col1", 3.0},
+		{"groups.fruits[1]", "banana"},
+		{"groups.fruits.0", "apple"},
+		{"/groups/fruits/0", "apple"},
+		{"/a~1b/m~0n", true},
+	}
+
+	for _, test := range tests {
+		got, err := Get(data, test.path)
+		if err != nil {
+			t.Errorf("Get(%q) returned an error: %v", test.path, err)
+		} else if got != test.expected {
+			t.Errorf("Get(%q) returned %v. Expected %v", test.path, got, test.expected)
+		}
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	data := decode(t)
+	var tests = []struct {
+		path     string
+		expected error
+	}{
+		{"matrix.row3", ErrNotFound},
+		{"matrix.row1.col1.x", ErrTypeMismatch},
+		{"matrix[0]", ErrTypeMismatch},
+		{"groups.fruits[5]", ErrIndexOutOfRange},
+		{"groups.fruits.x", ErrTypeMismatch},
+		{"groups..fruits", ErrSyntax},
+		{"groups.fruits[x]", ErrSyntax},
+		{"/a~2b", ErrSyntax},
+	}
+
+	for _, test := range tests {
+		_, err := Get(data, test.path)
+		if !errors.Is(err, test.expected) {
+			t.Errorf("Get(%q) returned %v. Expected %v", test.path, err, test.expected)
+		}
+		var pe *PathError
+		if !errors.As(err, &pe) {
+			t.Errorf("Get(%q) error %v is not a *PathError", test.path, err)
+		}
+	}
+}
+
+func TestGetCompositeMap(t *testing.T) {
+	elements := map[string]map[string]string{
+		"Li": {"name": "Lithium", "state": "solid"},
+	}
+	name, err := Lookup[string](elements, "Li.name")
+	if err != nil || name != "Lithium" {
+		t.Errorf("Lookup(Li.name) returned %q, %v. Expected Lithium", name, err)
+	}
+	if Has(elements, "Un.name") {
+		t.Error("Expected Has(Un.name) to be false")
+	}
+}
+
+func TestLookupTypeMismatch(t *testing.T) {
+	data := decode(t)
+	_, err := Lookup[string](data, "matrix.row1.col1")
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Lookup[string] returned %v. Expected ErrTypeMismatch", err)
+	}
+}
+
+func TestSet(t *testing.T) {
+	data := map[string]any{}
+	steps := []struct {
+		path  string
+		value any
+	}{
+		{"matrix.row1.col1", 1},
+		{"/matrix/row3/col1", 5},
+		{"groups.fruits[0]", "apple"},
+		{"groups.fruits[1]", "banana"},
+		{"/groups/fruits/-", "orange"},
+		{"groups.fruits[0]", "pear"},
+	}
+	for _, s := range steps {
+		if err := Set(data, s.path, s.value); err != nil {
+			t.Fatalf("Set(%q) returned an error: %v", s.path, err)
+		}
+	}
+
+	expected := map[string]any{
+		"matrix": map[string]any{
+			"row1": map[string]any{"col1": 1},
+			"row3": map[string]any{"col1": 5},
+		},
+		"groups": map[string]any{
+			"fruits": []any{"pear", "banana", "orange"},
+		},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Set produced %v. Expected %v", data, expected)
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	data := decode(t)
+	var tests = []struct {
+		path     string
+		expected error
+	}{
+		{"matrix.row1.col1.x", ErrTypeMismatch},
+		{"groups.fruits[3]", ErrIndexOutOfRange},
+		{"matrix[0]", ErrTypeMismatch},
+		{"", ErrSyntax},
+	}
+
+	for _, test := range tests {
+		err := Set(data, test.path, 0)
+		if !errors.Is(err, test.expected) {
+			t.Errorf("Set(%q) returned %v. Expected %v", test.path, err, test.expected)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	data := decode(t)
+	if err := Delete(data, "groups.fruits[0]"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(data, "/matrix/row1"); err != nil {
+		t.Fatal(err)
+	}
+	fruits, _ := Get(data, "groups.fruits")
+	if !reflect.DeepEqual(fruits, []any{"banana"}) {
+		t.Errorf("groups.fruits is %v after delete. Expected [banana]", fruits)
+	}
+	if Has(data, "matrix.row1") {
+		t.Error("Expected matrix.row1 to be deleted")
+	}
+	if err := Delete(data, "matrix.row1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete of a missing key returned %v. Expected ErrNotFound", err)
+	}
+}
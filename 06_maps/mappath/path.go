@@ -0,0 +1,153 @@
+package mappath
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Segment is a single step in a path: either a map key or a slice index.
+type Segment struct {
+	Key     string
+	Index   int
+	IsIndex bool // true for bracketed indices such as [0] and for the JSON Pointer "-" token
+	Append  bool // true for the JSON Pointer "-" token, which addresses one past the last element
+}
+
+// String returns the segment as it would appear in a dotted path.
+func (s Segment) String() string {
+	switch {
+	case s.Append:
+		return "[-]"
+	case s.IsIndex:
+		return "[" + strconv.Itoa(s.Index) + "]"
+	default:
+		return s.Key
+	}
+}
+
+// Path is a parsed sequence of segments.
+type Path []Segment
+
+// String renders the path in dotted notation.
+func (p Path) String() string {
+	var b strings.Builder
+	for i, s := range p {
+		if i > 0 && !s.IsIndex {
+			b.WriteByte('.')
+		}
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
+
+// Parse parses either a JSON Pointer (RFC 6901, starting with "/") or a dotted
+// path such as "rows.row1.cols[0]". The empty string addresses the root value.
+func Parse(path string) (Path, error) {
+	if path == "" {
+		return Path{}, nil
+	}
+	if strings.HasPrefix(path, "/") {
+		return parsePointer(path)
+	}
+	return parseDotted(path)
+}
+
+// parsePointer parses a JSON Pointer. Tokens are always keys; numeric tokens
+// are treated as indices when they are applied to a slice.
+func parsePointer(path string) (Path, error) {
+	tokens := strings.Split(path[1:], "/")
+	p := make(Path, 0, len(tokens))
+	for _, tok := range tokens {
+		if tok == "-" {
+			p = append(p, Segment{Key: tok, IsIndex: true, Append: true})
+			continue
+		}
+		key, err := unescapePointer(tok)
+		if err != nil {
+			return nil, &PathError{Path: path, Segment: tok, Err: err}
+		}
+		p = append(p, Segment{Key: key})
+	}
+	return p, nil
+}
+
+// unescapePointer decodes the ~0 and ~1 escapes of a JSON Pointer token.
+func unescapePointer(tok string) (string, error) {
+	if !strings.Contains(tok, "~") {
+		return tok, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(tok); i++ {
+		if tok[i] != '~' {
+			b.WriteByte(tok[i])
+			continue
+		}
+		if i+1 == len(tok) {
+			return "", ErrSyntax
+		}
+		switch tok[i+1] {
+		case '0':
+			b.WriteByte('~')
+		case '1':
+			b.WriteByte('/')
+		default:
+			return "", ErrSyntax
+		}
+		i++
+	}
+	return b.String(), nil
+}
+
+// parseDotted parses keys separated by dots, with optional [n] index suffixes.
+func parseDotted(path string) (Path, error) {
+	var p Path
+	i := 0
+	for i < len(path) {
+		switch path[i] {
+		case '.':
+			if i == 0 || i+1 == len(path) || path[i+1] == '.' || path[i+1] == '[' {
+				return nil, &PathError{Path: path, Segment: path[i:], Err: ErrSyntax}
+			}
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, &PathError{Path: path, Segment: path[i:], Err: ErrSyntax}
+			}
+			raw := path[i+1 : i+end]
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 {
+				return nil, &PathError{Path: path, Segment: path[i : i+end+1], Err: ErrSyntax}
+			}
+			p = append(p, Segment{Key: raw, Index: n, IsIndex: true})
+			i += end + 1
+			if i < len(path) && path[i] != '.' && path[i] != '[' {
+				return nil, &PathError{Path: path, Segment: path[i:], Err: ErrSyntax}
+			}
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			p = append(p, Segment{Key: path[i : i+end]})
+			i += end
+		}
+	}
+	return p, nil
+}
+
+// index converts a segment into a slice index for a slice of length n.
+// Key segments are accepted when they are non-negative integers.
+func (s Segment) index(n int) (int, bool) {
+	if s.Append {
+		return n, true
+	}
+	if s.IsIndex {
+		return s.Index, true
+	}
+	i, err := strconv.Atoi(s.Key)
+	if err != nil || i < 0 || (len(s.Key) > 1 && s.Key[0] == '0') {
+		return 0, false
+	}
+	return i, true
+}
@@ -0,0 +1,94 @@
+package sparse
+
+import (
+	"fmt"
+	"sort"
+)
+
+// COO stores a matrix as a list of (row, col, value) triplets. It is cheap to
+// build incrementally and is converted to CSR for arithmetic.
+type COO struct {
+	rows, cols int
+	RowIdx     []int
+	ColIdx     []int
+	Val        []float64
+}
+
+// NewCOO returns an empty rows x cols matrix in coordinate format.
+func NewCOO(rows, cols int) *COO {
+	if rows < 0 || cols < 0 {
+		panic(fmt.Sprintf("sparse: negative dimensions %dx%d", rows, cols))
+	}
+	return &COO{rows: rows, cols: cols}
+}
+
+// Dims returns the number of rows and columns.
+func (m *COO) Dims() (rows, cols int) {
+	return m.rows, m.cols
+}
+
+// NNZ returns the number of stored triplets, including duplicates.
+func (m *COO) NNZ() int {
+	return len(m.Val)
+}
+
+// Add appends v at (i, j). Duplicate coordinates are summed on conversion.
+func (m *COO) Add(i, j int, v float64) {
+	m.checkBounds(i, j)
+	if v == 0 {
+		return
+	}
+	m.RowIdx = append(m.RowIdx, i)
+	m.ColIdx = append(m.ColIdx, j)
+	m.Val = append(m.Val, v)
+}
+
+func (m *COO) checkBounds(i, j int) {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("sparse: index (%d, %d) out of range for %dx%d matrix", i, j, m.rows, m.cols))
+	}
+}
+
+// ToCSR converts the matrix to compressed sparse row format, summing
+// duplicate entries and dropping explicit zeros.
+func (m *COO) ToCSR() *CSR {
+	order := make([]int, len(m.Val))
+	for k := range order {
+		order[k] = k
+	}
+	sort.Slice(order, func(a, b int) bool {
+		ka, kb := order[a], order[b]
+		if m.RowIdx[ka] != m.RowIdx[kb] {
+			return m.RowIdx[ka] < m.RowIdx[kb]
+		}
+		return m.ColIdx[ka] < m.ColIdx[kb]
+	})
+
+	c := &CSR{rows: m.rows, cols: m.cols, RowPtr: make([]int, m.rows+1)}
+	for n := 0; n < len(order); {
+		k := order[n]
+		i, j, v := m.RowIdx[k], m.ColIdx[k], m.Val[k]
+		for n++; n < len(order) && m.RowIdx[order[n]] == i && m.ColIdx[order[n]] == j; n++ {
+			v += m.Val[order[n]]
+		}
+		if v == 0 {
+			continue
+		}
+		c.ColIdx = append(c.ColIdx, j)
+		c.Val = append(c.Val, v)
+		c.RowPtr[i+1]++
+	}
+	for i := 0; i < m.rows; i++ {
+		c.RowPtr[i+1] += c.RowPtr[i]
+	}
+	return c
+}
+
+// ToDense converts the matrix to a dense [][]float64.
+func (m *COO) ToDense() [][]float64 {
+	d := newDense(m.rows, m.cols)
+	for k, v := range m.Val {
+		d[m.RowIdx[k]][m.ColIdx[k]] += v
+	}
+	return d
+}
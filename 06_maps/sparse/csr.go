@@ -0,0 +1,179 @@
+package sparse
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CSR stores a matrix in compressed sparse row format: the non-zero values of
+// row i are Val[RowPtr[i]:RowPtr[i+1]], at the columns in the same range of
+// ColIdx. Columns are sorted within each row.
+type CSR struct {
+	rows, cols int
+	RowPtr     []int
+	ColIdx     []int
+	Val        []float64
+}
+
+// Dims returns the number of rows and columns.
+func (m *CSR) Dims() (rows, cols int) {
+	return m.rows, m.cols
+}
+
+// NNZ returns the number of stored non-zero values.
+func (m *CSR) NNZ() int {
+	return len(m.Val)
+}
+
+// At returns the value at (i, j).
+func (m *CSR) At(i, j int) float64 {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("sparse: index (%d, %d) out of range for %dx%d matrix", i, j, m.rows, m.cols))
+	}
+	lo, hi := m.RowPtr[i], m.RowPtr[i+1]
+	k := lo + sort.SearchInts(m.ColIdx[lo:hi], j)
+	if k < hi && m.ColIdx[k] == j {
+		return m.Val[k]
+	}
+	return 0
+}
+
+// Add returns m + b.
+func (m *CSR) Add(b *CSR) (*CSR, error) {
+	if m.rows != b.rows || m.cols != b.cols {
+		return nil, dimError("add", m, b)
+	}
+	c := &CSR{rows: m.rows, cols: m.cols, RowPtr: make([]int, m.rows+1)}
+	for i := 0; i < m.rows; i++ {
+		p, pEnd := m.RowPtr[i], m.RowPtr[i+1]
+		q, qEnd := b.RowPtr[i], b.RowPtr[i+1]
+		for p < pEnd || q < qEnd {
+			var j int
+			var v float64
+			switch {
+			case q == qEnd || (p < pEnd && m.ColIdx[p] < b.ColIdx[q]):
+				j, v = m.ColIdx[p], m.Val[p]
+				p++
+			case p == pEnd || b.ColIdx[q] < m.ColIdx[p]:
+				j, v = b.ColIdx[q], b.Val[q]
+				q++
+			default:
+				j, v = m.ColIdx[p], m.Val[p]+b.Val[q]
+				p++
+				q++
+			}
+			if v != 0 {
+				c.ColIdx = append(c.ColIdx, j)
+				c.Val = append(c.Val, v)
+			}
+		}
+		c.RowPtr[i+1] = len(c.Val)
+	}
+	return c, nil
+}
+
+// Mul returns the matrix product m * b.
+func (m *CSR) Mul(b *CSR) (*CSR, error) {
+	if m.cols != b.rows {
+		return nil, dimError("multiply", m, b)
+	}
+	c := &CSR{rows: m.rows, cols: b.cols, RowPtr: make([]int, m.rows+1)}
+
+	// acc is a dense accumulator for one output row; touched lists the
+	// columns written so that only those need to be gathered and reset.
+	acc := make([]float64, b.cols)
+	seen := make([]bool, b.cols)
+	var touched []int
+	for i := 0; i < m.rows; i++ {
+		touched = touched[:0]
+		for p := m.RowPtr[i]; p < m.RowPtr[i+1]; p++ {
+			k, a := m.ColIdx[p], m.Val[p]
+			for q := b.RowPtr[k]; q < b.RowPtr[k+1]; q++ {
+				j := b.ColIdx[q]
+				if !seen[j] {
+					seen[j] = true
+					touched = append(touched, j)
+				}
+				acc[j] += a * b.Val[q]
+			}
+		}
+		sort.Ints(touched)
+		for _, j := range touched {
+			if acc[j] != 0 {
+				c.ColIdx = append(c.ColIdx, j)
+				c.Val = append(c.Val, acc[j])
+			}
+			acc[j], seen[j] = 0, false
+		}
+		c.RowPtr[i+1] = len(c.Val)
+	}
+	return c, nil
+}
+
+// MulVec returns the matrix-vector product m * x.
+func (m *CSR) MulVec(x []float64) ([]float64, error) {
+	if len(x) != m.cols {
+		return nil, fmt.Errorf("%w: cannot multiply %dx%d matrix by vector of length %d",
+			ErrDimensionMismatch, m.rows, m.cols, len(x))
+	}
+	y := make([]float64, m.rows)
+	for i := 0; i < m.rows; i++ {
+		sum := 0.0
+		for p := m.RowPtr[i]; p < m.RowPtr[i+1]; p++ {
+			sum += m.Val[p] * x[m.ColIdx[p]]
+		}
+		y[i] = sum
+	}
+	return y, nil
+}
+
+// Transpose returns the transpose of m.
+func (m *CSR) Transpose() *CSR {
+	t := &CSR{
+		rows:   m.cols,
+		cols:   m.rows,
+		RowPtr: make([]int, m.cols+1),
+		ColIdx: make([]int, len(m.Val)),
+		Val:    make([]float64, len(m.Val)),
+	}
+	for _, j := range m.ColIdx {
+		t.RowPtr[j+1]++
+	}
+	for j := 0; j < m.cols; j++ {
+		t.RowPtr[j+1] += t.RowPtr[j]
+	}
+	next := make([]int, m.cols)
+	copy(next, t.RowPtr[:m.cols])
+	// Visiting rows in order keeps the columns of each transposed row sorted.
+	for i := 0; i < m.rows; i++ {
+		for p := m.RowPtr[i]; p < m.RowPtr[i+1]; p++ {
+			j := m.ColIdx[p]
+			t.ColIdx[next[j]] = i
+			t.Val[next[j]] = m.Val[p]
+			next[j]++
+		}
+	}
+	return t
+}
+
+// ToCOO converts the matrix to coordinate format.
+func (m *CSR) ToCOO() *COO {
+	c := NewCOO(m.rows, m.cols)
+	for i := 0; i < m.rows; i++ {
+		for p := m.RowPtr[i]; p < m.RowPtr[i+1]; p++ {
+			c.Add(i, m.ColIdx[p], m.Val[p])
+		}
+	}
+	return c
+}
+
+// ToDense converts the matrix to a dense [][]float64.
+func (m *CSR) ToDense() [][]float64 {
+	d := newDense(m.rows, m.cols)
+	for i := 0; i < m.rows; i++ {
+		for p := m.RowPtr[i]; p < m.RowPtr[i+1]; p++ {
+			d[i][m.ColIdx[p]] = m.Val[p]
+		}
+	}
+	return d
+}
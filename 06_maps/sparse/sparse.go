@@ -0,0 +1,60 @@
+// Package sparse implements numeric sparse matrices. It generalizes the
+// map[string]map[string]int "matrix" from the maps lab, which only stores the
+// cells that have a value, into COO (coordinate) and CSR (compressed sparse
+// row) storage with the usual linear algebra operations.
+package sparse
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDimensionMismatch is returned when operand shapes are incompatible.
+var ErrDimensionMismatch = errors.New("sparse: dimension mismatch")
+
+// Matrix is implemented by both storage formats.
+type Matrix interface {
+	Dims() (rows, cols int)
+	NNZ() int
+	ToDense() [][]float64
+}
+
+// FromDense builds a CSR matrix from a dense, rectangular [][]float64,
+// skipping zero entries.
+func FromDense(d [][]float64) (*CSR, error) {
+	rows := len(d)
+	cols := 0
+	if rows > 0 {
+		cols = len(d[0])
+	}
+	m := &CSR{rows: rows, cols: cols, RowPtr: make([]int, rows+1)}
+	for i, row := range d {
+		if len(row) != cols {
+			return nil, fmt.Errorf("%w: row %d has %d columns, expected %d",
+				ErrDimensionMismatch, i, len(row), cols)
+		}
+		for j, v := range row {
+			if v != 0 {
+				m.ColIdx = append(m.ColIdx, j)
+				m.Val = append(m.Val, v)
+			}
+		}
+		m.RowPtr[i+1] = len(m.Val)
+	}
+	return m, nil
+}
+
+func newDense(rows, cols int) [][]float64 {
+	d := make([][]float64, rows)
+	backing := make([]float64, rows*cols)
+	for i := range d {
+		d[i] = backing[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return d
+}
+
+func dimError(op string, a, b Matrix) error {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	return fmt.Errorf("%w: cannot %s %dx%d and %dx%d", ErrDimensionMismatch, op, ar, ac, br, bc)
+}
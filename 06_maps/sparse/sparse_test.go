@@ -0,0 +1,218 @@
+package sparse
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var a = [][]float64{
+	{1, 0, 2},
+	{0, 0, 3},
+	{4, 5, 0},
+}
+
+var b = [][]float64{
+	{0, 1, 0},
+	{2, 0, 0},
+	{0, 0, 6},
+}
+
+func mustCSR(t testing.TB, d [][]float64) *CSR {
+	t.Helper()
+	m, err := FromDense(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDenseRoundTrip(t *testing.T) {
+	m := mustCSR(t, a)
+	if m.NNZ() != 5 {
+		t.Errorf("NNZ() returned %d. Expected 5", m.NNZ())
+	}
+	if got := m.ToDense(); !reflect.DeepEqual(got, a) {
+		t.Errorf("ToDense() returned %v. Expected %v", got, a)
+	}
+	if got := m.ToCOO().ToCSR().ToDense(); !reflect.DeepEqual(got, a) {
+		t.Errorf("CSR -> COO -> CSR returned %v. Expected %v", got, a)
+	}
+	if m.At(2, 1) != 5 || m.At(1, 1) != 0 {
+		t.Errorf("At returned %v and %v. Expected 5 and 0", m.At(2, 1), m.At(1, 1))
+	}
+}
+
+func TestCOODuplicates(t *testing.T) {
+	c := NewCOO(2, 2)
+	c.Add(1, 1, 2)
+	c.Add(0, 1, 1)
+	c.Add(1, 1, 3)
+	c.Add(0, 0, 4)
+	c.Add(0, 0, -4)
+
+	expected := [][]float64{{0, 1}, {0, 5}}
+	m := c.ToCSR()
+	if got := m.ToDense(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ToCSR() returned %v. Expected %v", got, expected)
+	}
+	if m.NNZ() != 2 {
+		t.Errorf("NNZ() after summing duplicates returned %d. Expected 2", m.NNZ())
+	}
+}
+
+func TestOperations(t *testing.T) {
+	ma, mb := mustCSR(t, a), mustCSR(t, b)
+
+	sum, err := ma.Add(mb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sum.ToDense(); !reflect.DeepEqual(got, denseAdd(a, b)) {
+		t.Errorf("Add returned %v. Expected %v", got, denseAdd(a, b))
+	}
+
+	prod, err := ma.Mul(mb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prod.ToDense(); !reflect.DeepEqual(got, denseMul(a, b)) {
+		t.Errorf("Mul returned %v. Expected %v", got, denseMul(a, b))
+	}
+
+	expectedT := [][]float64{{1, 0, 4}, {0, 0, 5}, {2, 3, 0}}
+	if got := ma.Transpose().ToDense(); !reflect.DeepEqual(got, expectedT) {
+		t.Errorf("Transpose returned %v. Expected %v", got, expectedT)
+	}
+
+	x := []float64{1, 2, 3}
+	y, err := ma.MulVec(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(y, denseMulVec(a, x)) {
+		t.Errorf("MulVec returned %v. Expected %v", y, denseMulVec(a, x))
+	}
+}
+
+func TestDimensionMismatch(t *testing.T) {
+	ma := mustCSR(t, a)
+	wide := mustCSR(t, [][]float64{{1, 2, 3, 4}})
+
+	if _, err := ma.Add(wide); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Add returned %v. Expected ErrDimensionMismatch", err)
+	}
+	if _, err := ma.Mul(wide); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Mul returned %v. Expected ErrDimensionMismatch", err)
+	}
+	if _, err := ma.MulVec([]float64{1}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("MulVec returned %v. Expected ErrDimensionMismatch", err)
+	}
+	if _, err := FromDense([][]float64{{1, 2}, {3}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("FromDense of a ragged slice returned %v. Expected ErrDimensionMismatch", err)
+	}
+}
+
+// Dense baseline implementations used to check results and for benchmarks.
+
+func denseAdd(x, y [][]float64) [][]float64 {
+	out := newDense(len(x), len(x[0]))
+	for i := range x {
+		for j := range x[i] {
+			out[i][j] = x[i][j] + y[i][j]
+		}
+	}
+	return out
+}
+
+func denseMul(x, y [][]float64) [][]float64 {
+	out := newDense(len(x), len(y[0]))
+	for i := range x {
+		for k, v := range x[i] {
+			for j := range y[k] {
+				out[i][j] += v * y[k][j]
+			}
+		}
+	}
+	return out
+}
+
+func denseMulVec(x [][]float64, v []float64) []float64 {
+	out := make([]float64, len(x))
+	for i := range x {
+		for j, xv := range x[i] {
+			out[i] += xv * v[j]
+		}
+	}
+	return out
+}
+
+// randomDense returns an n x n matrix with roughly density*n*n non-zeros.
+func randomDense(n int, density float64) [][]float64 {
+	r := rand.New(rand.NewSource(1))
+	d := newDense(n, n)
+	for i := range d {
+		for j := range d[i] {
+			if r.Float64() < density {
+				d[i][j] = r.Float64()
+			}
+		}
+	}
+	return d
+}
+
+const benchSize, benchDensity = 300, 0.01
+
+func BenchmarkMulVecSparse(b *testing.B) {
+	d := randomDense(benchSize, benchDensity)
+	m := mustCSR(b, d)
+	x := make([]float64, benchSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.MulVec(x)
+	}
+}
+
+func BenchmarkMulVecDense(b *testing.B) {
+	d := randomDense(benchSize, benchDensity)
+	x := make([]float64, benchSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		denseMulVec(d, x)
+	}
+}
+
+func BenchmarkMulSparse(b *testing.B) {
+	d := randomDense(benchSize, benchDensity)
+	m := mustCSR(b, d)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.Mul(m)
+	}
+}
+
+func BenchmarkMulDense(b *testing.B) {
+	d := randomDense(benchSize, benchDensity)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		denseMul(d, d)
+	}
+}
+
+func BenchmarkAddSparse(b *testing.B) {
+	d := randomDense(benchSize, benchDensity)
+	m := mustCSR(b, d)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.Add(m)
+	}
+}
+
+func BenchmarkAddDense(b *testing.B) {
+	d := randomDense(benchSize, benchDensity)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		denseAdd(d, d)
+	}
+}
@@ -1,4 +1,4 @@
-// struct_examples.go
+// structs_and_methods.go
 package main
 
 import (
@@ -181,12 +181,17 @@ func main() {
 	// JSON marshaling/unmarshaling
 	// ------------------------------------------------------------
 
-	jsonData, _ := json.Marshal(p2)
+	jsonData, err := json.Marshal(p2)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
 	fmt.Println("JSON:", string(jsonData))
 
 	var decoded Person
-	err := json.Unmarshal(jsonData, &decoded)
+	err = json.Unmarshal(jsonData, &decoded)
 	if err != nil {
+		fmt.Println("Error:", err)
 		return
 	}
 	fmt.Println("Decoded JSON:", decoded)
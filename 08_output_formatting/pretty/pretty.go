@@ -0,0 +1,468 @@
+// Package pretty prints nested Go values in an indented, Go-like layout. It
+// is for values such as the structs lab's Employee or the maps lab's matrix
+// and groups, which fmt.Println squeezes onto one hard-to-read line:
+//
+//	pretty.Println(matrix)
+//
+//	map[string]map[string]int{
+//	  "row1": {"col1": 1, "col2": 2},
+//	  "row2": {"col1": 3, "col2": 4},
+//	  "row3": {"col1": 5},
+//	}
+//
+// Map keys are sorted, pointer cycles are printed as <cycle> instead of
+// recursing forever, and values that implement fmt.Formatter, fmt.Stringer
+// or error are printed with their own text. Config enables ANSI colours and
+// other options.
+package pretty
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config controls the output. The zero value is usable and matches the
+// package-level functions.
+type Config struct {
+	Indent       string // per level; defaults to two spaces
+	Width        int    // line width for inlining short collections; defaults to 80
+	Color        bool   // highlight with ANSI escape codes
+	MaxDepth     int    // nesting levels to print before eliding with "…"; 0 is unlimited
+	ExportedOnly bool   // omit unexported struct fields
+	NoMethods    bool   // ignore String, Error and Format methods
+}
+
+var defaultConfig = &Config{}
+
+// Sprint returns the pretty form of v.
+func Sprint(v any) string {
+	return defaultConfig.Sprint(v)
+}
+
+// Fprint writes the pretty form of v, followed by a newline, to w.
+func Fprint(w io.Writer, v any) error {
+	return defaultConfig.Fprint(w, v)
+}
+
+// Println writes the pretty form of each value to standard output.
+func Println(vs ...any) {
+	for _, v := range vs {
+		defaultConfig.Fprint(os.Stdout, v)
+	}
+}
+
+// Sprint returns the pretty form of v.
+func (c *Config) Sprint(v any) string {
+	p := &printer{Config: *c, onPath: map[visit]bool{}}
+	if p.Indent == "" {
+		p.Indent = "  "
+	}
+	if p.Width == 0 {
+		p.Width = 80
+	}
+	p.value(reflect.ValueOf(v), 0, true)
+	return p.String()
+}
+
+// Fprint writes the pretty form of v, followed by a newline, to w.
+func (c *Config) Fprint(w io.Writer, v any) error {
+	_, err := io.WriteString(w, c.Sprint(v)+"\n")
+	return err
+}
+
+// ANSI colours for each kind of token.
+const (
+	reset     = "\x1b[0m"
+	colType   = "\x1b[2m"  // faint
+	colKey    = "\x1b[34m" // blue
+	colString = "\x1b[32m" // green
+	colNumber = "\x1b[36m" // cyan
+	colBool   = "\x1b[33m" // yellow
+	colNil    = "\x1b[35m" // magenta
+)
+
+// visit identifies a reference-like value on the current path.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type printer struct {
+	Config
+	strings.Builder
+	onPath map[visit]bool
+}
+
+var (
+	formatterType = reflect.TypeOf((*fmt.Formatter)(nil)).Elem()
+	stringerType  = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+func (p *printer) paint(color, s string) {
+	if p.Color {
+		p.WriteString(color + s + reset)
+	} else {
+		p.WriteString(s)
+	}
+}
+
+// value prints v at the given depth. showType is false where the type is
+// implied by the enclosing collection, as in Go composite literals.
+func (p *printer) value(v reflect.Value, depth int, showType bool) {
+	if !v.IsValid() {
+		p.paint(colNil, "nil")
+		return
+	}
+	if p.method(v) {
+		return
+	}
+	if p.MaxDepth > 0 && depth > p.MaxDepth && isComposite(v) {
+		p.WriteString("…")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		p.paint(colBool, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p.paint(colNumber, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		p.paint(colNumber, strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		p.paint(colNumber, strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+	case reflect.Complex64, reflect.Complex128:
+		p.paint(colNumber, strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits()))
+	case reflect.String:
+		p.paint(colString, strconv.Quote(v.String()))
+	case reflect.Interface:
+		if v.IsNil() {
+			p.paint(colNil, "nil")
+			return
+		}
+		p.value(v.Elem(), depth, true)
+	case reflect.Pointer:
+		if v.IsNil() {
+			p.paint(colNil, "nil")
+			return
+		}
+		if p.enter(v) {
+			defer p.leave(v)
+			p.WriteString("&")
+			p.value(v.Elem(), depth, true)
+		}
+	case reflect.Struct:
+		p.structValue(v, depth, showType)
+	case reflect.Map:
+		if v.IsNil() {
+			p.paint(colNil, "nil")
+			return
+		}
+		if p.enter(v) {
+			defer p.leave(v)
+			p.mapValue(v, depth, showType)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			p.paint(colNil, "nil")
+			return
+		}
+		if p.enter(v) {
+			defer p.leave(v)
+			p.listValue(v, depth, showType)
+		}
+	case reflect.Array:
+		p.listValue(v, depth, showType)
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			p.paint(colNil, "nil")
+			return
+		}
+		p.paint(colType, fmt.Sprintf("(%s)(%#x)", v.Type(), v.Pointer()))
+	default:
+		p.WriteString(v.String())
+	}
+}
+
+// method prints v with its Format, Error or String method, if it has one
+// and may be called.
+func (p *printer) method(v reflect.Value) bool {
+	if !p.hasMethod(v) {
+		return false
+	}
+	s := fmt.Sprint(v.Interface())
+	if v.Kind() == reflect.String {
+		s = strconv.Quote(s)
+	}
+	p.paint(colString, s)
+	return true
+}
+
+func (p *printer) hasMethod(v reflect.Value) bool {
+	if p.NoMethods || !v.CanInterface() {
+		return false
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return false
+	}
+	t := v.Type()
+	return t.Implements(formatterType) || t.Implements(stringerType) || t.Implements(errorType)
+}
+
+// enter marks a reference as being printed and reports whether it was not
+// already on the current path; otherwise it prints a cycle marker.
+func (p *printer) enter(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice && v.Len() == 0 {
+		return true
+	}
+	key := visit{v.Pointer(), v.Type()}
+	if p.onPath[key] {
+		p.paint(colNil, "<cycle "+v.Type().String()+">")
+		return false
+	}
+	p.onPath[key] = true
+	return true
+}
+
+func (p *printer) leave(v reflect.Value) {
+	delete(p.onPath, visit{v.Pointer(), v.Type()})
+}
+
+func (p *printer) typeName(v reflect.Value, showType bool) {
+	if showType {
+		p.paint(colType, v.Type().String())
+	}
+}
+
+func (p *printer) structValue(v reflect.Value, depth int, showType bool) {
+	p.typeName(v, showType)
+	t := v.Type()
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		if p.ExportedOnly && !t.Field(i).IsExported() {
+			continue
+		}
+		fields = append(fields, i)
+	}
+	if len(fields) == 0 {
+		p.WriteString("{}")
+		return
+	}
+	p.WriteString("{\n")
+	for _, i := range fields {
+		p.indent(depth + 1)
+		p.paint(colKey, t.Field(i).Name)
+		p.WriteString(": ")
+		p.value(v.Field(i), depth+1, true)
+		p.WriteString(",\n")
+	}
+	p.indent(depth)
+	p.WriteString("}")
+}
+
+func (p *printer) mapValue(v reflect.Value, depth int, showType bool) {
+	p.typeName(v, showType)
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	elemType := !isConcrete(v.Type().Elem())
+	keyType := !isConcrete(v.Type().Key())
+
+	if p.inline(v, depth, func(q *printer) {
+		for i, k := range keys {
+			if i > 0 {
+				q.WriteString(", ")
+			}
+			q.value(k, depth+1, keyType)
+			q.WriteString(": ")
+			q.value(v.MapIndex(k), depth+1, elemType)
+		}
+	}) {
+		return
+	}
+	p.WriteString("{\n")
+	for _, k := range keys {
+		p.indent(depth + 1)
+		p.value(k, depth+1, keyType)
+		p.WriteString(": ")
+		p.value(v.MapIndex(k), depth+1, elemType)
+		p.WriteString(",\n")
+	}
+	p.indent(depth)
+	p.WriteString("}")
+}
+
+func (p *printer) listValue(v reflect.Value, depth int, showType bool) {
+	p.typeName(v, showType)
+	elemType := !isConcrete(v.Type().Elem())
+	if p.inline(v, depth, func(q *printer) {
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				q.WriteString(", ")
+			}
+			q.value(v.Index(i), depth+1, elemType)
+		}
+	}) {
+		return
+	}
+	p.WriteString("{\n")
+	for i := 0; i < v.Len(); i++ {
+		p.indent(depth + 1)
+		p.value(v.Index(i), depth+1, elemType)
+		p.WriteString(",\n")
+	}
+	p.indent(depth)
+	p.WriteString("}")
+}
+
+// inline prints a collection of scalars on one line, "{a, b, c}", when it
+// fits within Width, and reports whether it did.
+func (p *printer) inline(v reflect.Value, depth int, body func(*printer)) bool {
+	if v.Len() == 0 {
+		p.WriteString("{}")
+		return true
+	}
+	if !p.scalarElems(v) {
+		return false
+	}
+	q := &printer{Config: p.Config, onPath: p.onPath}
+	q.WriteString("{")
+	body(q)
+	q.WriteString("}")
+	s := q.String()
+	if visibleLen(p.lastLine())+visibleLen(s) > p.Width {
+		return false
+	}
+	p.WriteString(s)
+	return true
+}
+
+func (p *printer) lastLine() string {
+	s := p.String()
+	return s[strings.LastIndexByte(s, '\n')+1:]
+}
+
+func (p *printer) indent(depth int) {
+	p.WriteString(strings.Repeat(p.Indent, depth))
+}
+
+// scalarElems reports whether every element (and key) of a map, slice or
+// array prints on one line.
+func (p *printer) scalarElems(v reflect.Value) bool {
+	if v.Kind() == reflect.Map {
+		for _, k := range v.MapKeys() {
+			if !p.isScalar(k) || !p.isScalar(v.MapIndex(k)) {
+				return false
+			}
+		}
+		return true
+	}
+	for i := 0; i < v.Len(); i++ {
+		if !p.isScalar(v.Index(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isScalar reports whether v prints as a single token.
+func (p *printer) isScalar(v reflect.Value) bool {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if p.hasMethod(v) {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Array:
+		return false
+	case reflect.Map, reflect.Slice, reflect.Pointer:
+		return v.IsNil()
+	}
+	return true
+}
+
+func isComposite(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// isConcrete reports whether values of type t can be printed without their
+// type, because the enclosing collection's type names it.
+func isConcrete(t reflect.Type) bool {
+	return t.Kind() != reflect.Interface
+}
+
+// less orders map keys: numbers numerically, strings and other values by
+// their text, with nil first.
+func less(a, b reflect.Value) bool {
+	for a.Kind() == reflect.Interface && !a.IsNil() {
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Interface && !b.IsNil() {
+		b = b.Elem()
+	}
+	if a.Kind() != b.Kind() {
+		return a.Kind() < b.Kind()
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	case reflect.Pointer, reflect.Chan:
+		return a.Pointer() < b.Pointer()
+	case reflect.Struct, reflect.Array:
+		n := a.NumField
+		if a.Kind() == reflect.Array {
+			n = a.Len
+		}
+		for i := 0; i < n(); i++ {
+			x, y := field(a, i), field(b, i)
+			if less(x, y) {
+				return true
+			}
+			if less(y, x) {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+func field(v reflect.Value, i int) reflect.Value {
+	if v.Kind() == reflect.Array {
+		return v.Index(i)
+	}
+	return v.Field(i)
+}
+
+// visibleLen returns the length of s without ANSI escape sequences.
+func visibleLen(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' {
+			for i < len(s) && s[i] != 'm' {
+				i++
+			}
+			continue
+		}
+		if s[i]&0xC0 != 0x80 {
+			n++
+		}
+	}
+	return n
+}
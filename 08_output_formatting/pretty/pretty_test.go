@@ -0,0 +1,203 @@
+package pretty
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Person struct {
+	Name   string
+	Age    int
+	active bool
+}
+
+type Address struct {
+	City  string
+	State string
+}
+
+type Employee struct {
+	Person
+	Position string
+	Address
+	Manager *Employee
+	Tags    []string
+}
+
+type Node struct {
+	Value int
+	Next  *Node
+}
+
+type Money int
+
+func (m Money) String() string { return fmt.Sprintf("$%d.%02d", m/100, m%100) }
+
+// Badge implements fmt.Formatter like the formatter lab's Person.
+type Badge struct{ Name string }
+
+func (b Badge) Format(f fmt.State, c rune) { fmt.Fprintf(f, "<%s>", b.Name) }
+
+func lines(ls ...string) string { return strings.Join(ls, "\n") }
+
+func TestSprint(t *testing.T) {
+	boss := &Employee{Person: Person{Name: "Grace", Age: 50}, Position: "CTO"}
+	var tests = []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"nil", nil, "nil"},
+		{"scalars", []any{1, "two", 3.5, true, nil}, `[]interface {}{1, "two", 3.5, true, nil}`},
+		{"empty", map[string]int{}, "map[string]int{}"},
+		{"nil slice", []int(nil), "nil"},
+		{"groups", map[string][]string{
+			"vegetables": {"carrot", "potato"},
+			"fruits":     {"apple", "banana", "orange"},
+		}, lines(
+			"map[string][]string{",
+			`  "fruits": {"apple", "banana", "orange"},`,
+			`  "vegetables": {"carrot", "potato"},`,
+			"}",
+		)},
+		{"matrix", map[string]map[string]int{
+			"row2": {"col2": 4, "col1": 3},
+			"row1": {"col1": 1, "col2": 2},
+		}, lines(
+			"map[string]map[string]int{",
+			`  "row1": {"col1": 1, "col2": 2},`,
+			`  "row2": {"col1": 3, "col2": 4},`,
+			"}",
+		)},
+		{"int keys", map[int]bool{10: true, 9: false, -1: true}, "map[int]bool{-1: true, 9: false, 10: true}"},
+		{"employee", Employee{
+			Person:   Person{Name: "Alice", Age: 30, active: true},
+			Position: "Engineer",
+			Address:  Address{City: "Berlin"},
+			Manager:  boss,
+		}, lines(
+			"pretty.Employee{",
+			"  Person: pretty.Person{",
+			`    Name: "Alice",`,
+			"    Age: 30,",
+			"    active: true,",
+			"  },",
+			`  Position: "Engineer",`,
+			"  Address: pretty.Address{",
+			`    City: "Berlin",`,
+			`    State: "",`,
+			"  },",
+			"  Manager: &pretty.Employee{",
+			"    Person: pretty.Person{",
+			`      Name: "Grace",`,
+			"      Age: 50,",
+			"      active: false,",
+			"    },",
+			`    Position: "CTO",`,
+			"    Address: pretty.Address{",
+			`      City: "",`,
+			`      State: "",`,
+			"    },",
+			"    Manager: nil,",
+			"    Tags: nil,",
+			"  },",
+			"  Tags: nil,",
+			"}",
+		)},
+		{"methods", []any{Money(1999), Badge{"admin"}, errors.New("boom"), time.Duration(1500) * time.Millisecond},
+			`[]interface {}{$19.99, <admin>, boom, 1.5s}`},
+		{"struct of methods", struct {
+			Price Money
+			Badge Badge
+		}{250, Badge{"x"}}, lines(
+			"struct { Price pretty.Money; Badge pretty.Badge }{",
+			"  Price: $2.50,",
+			"  Badge: <x>,",
+			"}",
+		)},
+		{"slice of structs", []Address{{"Paris", "IDF"}}, lines(
+			"[]pretty.Address{",
+			"  {",
+			`    City: "Paris",`,
+			`    State: "IDF",`,
+			"  },",
+			"}",
+		)},
+	}
+
+	for _, test := range tests {
+		if got := Sprint(test.value); got != test.expected {
+			t.Errorf("Sprint(%s) returned:\n%s\nExpected:\n%s", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestCycles(t *testing.T) {
+	a := &Node{Value: 1}
+	a.Next = &Node{Value: 2, Next: a}
+	expected := lines(
+		"&pretty.Node{",
+		"  Value: 1,",
+		"  Next: &pretty.Node{",
+		"    Value: 2,",
+		"    Next: <cycle *pretty.Node>,",
+		"  },",
+		"}",
+	)
+	if got := Sprint(a); got != expected {
+		t.Errorf("Sprint(cycle) returned:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	m := map[string]any{"name": "loop"}
+	m["self"] = m
+	expected = lines(
+		"map[string]interface {}{",
+		`  "name": "loop",`,
+		"  \"self\": <cycle map[string]interface {}>,",
+		"}",
+	)
+	if got := Sprint(m); got != expected {
+		t.Errorf("Sprint(self-map) returned:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	// The same pointer twice, without a cycle, is printed twice.
+	shared := &Node{Value: 7}
+	if got := Sprint([]*Node{shared, shared}); strings.Contains(got, "cycle") {
+		t.Errorf("Sprint(shared pointers) reported a cycle:\n%s", got)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	p := Person{Name: "Ann", Age: 3, active: true}
+	c := &Config{ExportedOnly: true, Indent: "\t"}
+	if got, expected := c.Sprint(p), "pretty.Person{\n\tName: \"Ann\",\n\tAge: 3,\n}"; got != expected {
+		t.Errorf("ExportedOnly returned:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	c = &Config{MaxDepth: 1}
+	got := c.Sprint(map[string]map[string][]int{"a": {"b": {1}}})
+	if expected := "map[string]map[string][]int{\n  \"a\": {\n    \"b\": …,\n  },\n}"; got != expected {
+		t.Errorf("MaxDepth returned:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	c = &Config{NoMethods: true}
+	if got := c.Sprint(Money(5)); got != "5" {
+		t.Errorf("NoMethods returned %q. Expected \"5\"", got)
+	}
+
+	c = &Config{Width: 20}
+	got = c.Sprint([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	if !strings.HasPrefix(got, "[]int{\n  1,\n") {
+		t.Errorf("Width 20 did not wrap:\n%s", got)
+	}
+
+	c = &Config{Color: true}
+	got = c.Sprint(map[string]int{"a": 1})
+	expected := "\x1b[2mmap[string]int\x1b[0m{\x1b[32m\"a\"\x1b[0m: \x1b[36m1\x1b[0m}"
+	if got != expected {
+		t.Errorf("Color returned %q. Expected %q", got, expected)
+	}
+}
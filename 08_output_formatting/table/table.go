@@ -0,0 +1,373 @@
+// Package table renders slices of structs as text tables. It grows the
+// Person.Format example of the formatter lab into a reusable printer: a
+// *Table is itself a fmt.Formatter, so it can be printed with Printf.
+//
+//	type Person struct {
+//		Name string
+//		Age  int `table:"Age (years)"`
+//	}
+//	t, _ := table.New([]Person{{"Ada", 36}, {"Linus", 54}})
+//	fmt.Printf("%v", t)   // aligned plain text
+//	fmt.Printf("%m", t)   // Markdown
+//	fmt.Printf("%c", t)   // CSV
+//	fmt.Printf("%.8v", t) // plain text, cells cut to 8 columns
+//
+// Columns come from the exported fields, in declaration order, including
+// those of embedded structs. A `table:"..."` tag renames a column, and its
+// options set the alignment and a maximum width:
+//
+//	Notes string `table:"Notes,width=20"`
+//	Price Money  `table:",right"`
+//	token string `table:"-"` // skipped
+//
+// Cells are formatted with fmt.Sprint, so fields that implement fmt.Stringer
+// or fmt.Formatter control their own text. Widths count terminal columns:
+// CJK characters and most emoji are two columns, combining marks none.
+package table
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Mode selects the output format.
+type Mode int
+
+const (
+	Plain Mode = iota
+	Markdown
+	CSV
+)
+
+// Align is the horizontal alignment of a column.
+type Align int
+
+const (
+	// Auto aligns numbers right and everything else left.
+	Auto Align = iota
+	Left
+	Right
+	Center
+)
+
+// ErrNotStructSlice is returned by New for values that are not a slice or
+// array of structs or struct pointers.
+var ErrNotStructSlice = errors.New("table: need a slice of structs")
+
+// Column describes one column of a table.
+type Column struct {
+	Header   string
+	Align    Align
+	MaxWidth int // 0 means unlimited
+}
+
+// Table is a rendered grid of strings ready to print.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+	Mode    Mode // format used by %v, %s, Write and String
+	// MaxWidth truncates every cell to this many columns; 0 means no limit.
+	// A column's own MaxWidth takes precedence.
+	MaxWidth int
+}
+
+// New builds a table from a slice or array of structs or pointers to
+// structs. Nil pointers become empty rows.
+func New(rows any) (*Table, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w, got %T", ErrNotStructSlice, rows)
+	}
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w, got %T", ErrNotStructSlice, rows)
+	}
+
+	fields, err := columns(elem, nil)
+	if err != nil {
+		return nil, err
+	}
+	t := &Table{Columns: make([]Column, len(fields))}
+	numeric := make([]bool, len(fields))
+	for i, f := range fields {
+		t.Columns[i] = f.Column
+		numeric[i] = true
+	}
+	for i := 0; i < v.Len(); i++ {
+		rv := v.Index(i)
+		row := make([]string, len(fields))
+		if rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				t.Rows = append(t.Rows, row)
+				continue
+			}
+			rv = rv.Elem()
+		}
+		for j, f := range fields {
+			fv, ok := fieldByIndex(rv, f.index)
+			if !ok {
+				continue
+			}
+			row[j] = fmt.Sprint(fv.Interface())
+			if !isNumber(fv, row[j]) {
+				numeric[j] = false
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	for i := range t.Columns {
+		if t.Columns[i].Align == Auto && numeric[i] && len(t.Rows) > 0 {
+			t.Columns[i].Align = Right
+		}
+	}
+	return t, nil
+}
+
+type field struct {
+	Column
+	index []int
+}
+
+// columns lists the exported fields of t, descending into embedded structs.
+// A width option that is not a non-negative integer is an error.
+func columns(t reflect.Type, index []int) ([]field, error) {
+	var out []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		idx := append(append([]int(nil), index...), i)
+		tag, hasTag := sf.Tag.Lookup("table")
+		if tag == "-" {
+			continue
+		}
+		if sf.Anonymous && !hasTag {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded, err := columns(ft, idx)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, embedded...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		c := Column{Header: sf.Name}
+		opts := strings.Split(tag, ",")
+		if opts[0] != "" {
+			c.Header = opts[0]
+		}
+		for _, opt := range opts[1:] {
+			switch {
+			case opt == "left":
+				c.Align = Left
+			case opt == "right":
+				c.Align = Right
+			case opt == "center":
+				c.Align = Center
+			case strings.HasPrefix(opt, "width="):
+				n, err := strconv.Atoi(strings.TrimPrefix(opt, "width="))
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("table: field %s: invalid option %q", sf.Name, opt)
+				}
+				c.MaxWidth = n
+			}
+		}
+		out = append(out, field{c, idx})
+	}
+	return out, nil
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex without panicking on nil
+// embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isNumber reports whether a cell holds a plain number, which is aligned
+// right by default.
+func isNumber(v reflect.Value, s string) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		_, err := strconv.ParseFloat(s, 64)
+		return err == nil
+	}
+	return false
+}
+
+// Write renders the table to w in t.Mode.
+func (t *Table) Write(w io.Writer) error {
+	return t.write(w, t.Mode, t.MaxWidth)
+}
+
+// String renders the table in t.Mode.
+func (t *Table) String() string {
+	var b strings.Builder
+	t.Write(&b)
+	return b.String()
+}
+
+// Format implements fmt.Formatter. %v and %s use t.Mode, %m forces
+// Markdown and %c forces CSV. A precision, as in %.12v, truncates cells to
+// that many columns.
+func (t *Table) Format(f fmt.State, verb rune) {
+	mode := t.Mode
+	switch verb {
+	case 'v', 's':
+	case 'm':
+		mode = Markdown
+	case 'c':
+		mode = CSV
+	default:
+		fmt.Fprintf(f, "%%!%c(*table.Table)", verb)
+		return
+	}
+	max := t.MaxWidth
+	if p, ok := f.Precision(); ok {
+		max = p
+	}
+	t.write(f, mode, max)
+}
+
+func (t *Table) write(w io.Writer, mode Mode, max int) error {
+	if mode == CSV {
+		cw := csv.NewWriter(w)
+		header := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			header[i] = c.Header
+		}
+		cw.Write(header)
+		cw.WriteAll(t.Rows)
+		return cw.Error()
+	}
+
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = t.cell(i, c.Header, mode, max)
+	}
+	rows := make([][]string, len(t.Rows))
+	for r, row := range t.Rows {
+		rows[r] = make([]string, len(t.Columns))
+		for i := range t.Columns {
+			if i < len(row) {
+				rows[r][i] = t.cell(i, row[i], mode, max)
+			}
+		}
+	}
+	widths := make([]int, len(t.Columns))
+	for i, h := range header {
+		widths[i] = StringWidth(h)
+		if mode == Markdown && widths[i] < 3 {
+			widths[i] = 3 // room for "---"
+		}
+	}
+	for _, row := range rows {
+		for i, s := range row {
+			if n := StringWidth(s); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b strings.Builder
+	t.line(&b, header, widths, mode)
+	seps := make([]string, len(widths))
+	for i, n := range widths {
+		seps[i] = t.separator(i, n, mode)
+	}
+	t.line(&b, seps, widths, mode)
+	for _, row := range rows {
+		t.line(&b, row, widths, mode)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// cell prepares a value for a text layout: truncated and, for Markdown,
+// with pipes escaped and newlines flattened.
+func (t *Table) cell(i int, s string, mode Mode, max int) string {
+	s = strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(s)
+	if c := t.Columns[i]; c.MaxWidth > 0 {
+		max = c.MaxWidth
+	}
+	s = truncate(s, max)
+	if mode == Markdown {
+		s = strings.ReplaceAll(s, "|", `\|`)
+	}
+	return s
+}
+
+func (t *Table) separator(i, width int, mode Mode) string {
+	if mode != Markdown {
+		return strings.Repeat("-", width)
+	}
+	switch t.Columns[i].Align {
+	case Right:
+		return strings.Repeat("-", width-1) + ":"
+	case Center:
+		return ":" + strings.Repeat("-", width-2) + ":"
+	}
+	return strings.Repeat("-", width)
+}
+
+func (t *Table) line(b *strings.Builder, cells []string, widths []int, mode Mode) {
+	var l strings.Builder
+	if mode == Markdown {
+		l.WriteString("| ")
+	}
+	for i, s := range cells {
+		if i > 0 {
+			if mode == Markdown {
+				l.WriteString(" | ")
+			} else {
+				l.WriteString("  ")
+			}
+		}
+		l.WriteString(pad(s, widths[i], t.Columns[i].Align))
+	}
+	if mode == Markdown {
+		l.WriteString(" |")
+	}
+	// Plain lines carry no trailing spaces after a short last cell.
+	b.WriteString(strings.TrimRight(l.String(), " "))
+	b.WriteByte('\n')
+}
+
+// pad aligns s within width columns.
+func pad(s string, width int, a Align) string {
+	gap := width - StringWidth(s)
+	if gap <= 0 {
+		return s
+	}
+	switch a {
+	case Right:
+		return strings.Repeat(" ", gap) + s
+	case Center:
+		left := gap / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", gap-left)
+	}
+	return s + strings.Repeat(" ", gap)
+}
@@ -0,0 +1,147 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+// Format mirrors the formatter lab: %v prints the name only.
+func (p Person) Format(f fmt.State, c rune) {
+	if f.Flag('+') {
+		fmt.Fprintf(f, "%s (%d years old)", p.Name, p.Age)
+	} else {
+		fmt.Fprintf(f, "%s", p.Name)
+	}
+}
+
+type Celsius float64
+
+func (c Celsius) String() string { return fmt.Sprintf("%.1f°C", float64(c)) }
+
+type meta struct {
+	ID int `table:"#"`
+}
+
+type City struct {
+	meta
+	Name    string  `table:"City"`
+	Country string  `table:",center"`
+	Temp    Celsius `table:",right"`
+	Pop     float64 `table:"Population (M)"`
+	secret  string
+	Skip    bool   `table:"-"`
+	Notes   string `table:",width=12"`
+}
+
+var cities = []City{
+	{meta{1}, "Tokyo", "日本", 16.5, 37.4, "x", false, "capital | largest metro"},
+	{meta{2}, "Zürich", "Schweiz", 9.3, 0.42, "", true, ""},
+	{meta{3}, "Café", "France", 12.25, 2.1, "", false, "e\u0301 combining"},
+}
+
+func render(t *testing.T, format string, v any) string {
+	t.Helper()
+	tb, err := New(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf(format, tb)
+}
+
+func TestPlain(t *testing.T) {
+	got := render(t, "%v", cities)
+	expected := strings.Join([]string{
+		"#  City    Country    Temp  Population (M)  Notes",
+		"-  ------  -------  ------  --------------  ------------",
+		"1  Tokyo    日本    16.5°C            37.4  capital | l…",
+		"2  Zürich  Schweiz   9.3°C            0.42",
+		"3  Café    France   12.2°C             2.1  e\u0301 combining",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("plain table:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	got := render(t, "%.6m", cities)
+	expected := strings.Join([]string{
+		"|   # | City   | Count… |   Temp | Popul… | Notes         |",
+		"| --: | ------ | :----: | -----: | -----: | ------------- |",
+		"|   1 | Tokyo  |  日本  | 16.5°C |   37.4 | capital \\| l… |",
+		"|   2 | Zürich | Schwe… |  9.3°C |   0.42 |               |",
+		"|   3 | Café   | France | 12.2°C |    2.1 | e\u0301 combining   |",
+		"",
+	}, "\n")
+	if got != expected {
+		t.Errorf("markdown table:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	got := render(t, "%c", []*Person{{"Ada, Countess", 36}, nil, {"Linus", 54}})
+	expected := "Name,Age\n\"Ada, Countess\",36\n,\nLinus,54\n"
+	if got != expected {
+		t.Errorf("CSV table:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestFormatterFields(t *testing.T) {
+	// Person's own Format decides the cell text of a Person-typed field.
+	type Team struct {
+		Lead Person
+		Size int
+	}
+	got := render(t, "%s", []Team{{Person{"Test User", 30}, 4}})
+	expected := "Lead       Size\n---------  ----\nTest User     4\n"
+	if got != expected {
+		t.Errorf("table:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, v := range []any{42, []int{1}, Person{}, map[string]Person{}} {
+		if _, err := New(v); !errors.Is(err, ErrNotStructSlice) {
+			t.Errorf("New(%T) returned %v. Expected ErrNotStructSlice", v, err)
+		}
+	}
+	type Bad struct {
+		Notes string `table:",width=wide"`
+	}
+	if _, err := New([]Bad{}); err == nil || !strings.Contains(err.Error(), "width=wide") {
+		t.Errorf("New with an invalid width returned %v. Expected an error naming the option", err)
+	}
+	tb, _ := New(cities)
+	if got := fmt.Sprintf("%d", tb); got != "%!d(*table.Table)" {
+		t.Errorf("%%d returned %q", got)
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	var tests = []struct {
+		s        string
+		expected int
+	}{
+		{"abc", 3},
+		{"日本語", 6},
+		{"é", 1},
+		{"👍 ok", 5},
+		{"ｶﾀｶﾅ", 4}, // halfwidth katakana
+		{"Ｇｏ", 4},   // fullwidth Latin
+	}
+	for _, test := range tests {
+		if got := StringWidth(test.s); got != test.expected {
+			t.Errorf("StringWidth(%q) returned %d. Expected %d", test.s, got, test.expected)
+		}
+	}
+	if got := truncate("日本語テキスト", 7); got != "日本語…" {
+		t.Errorf("truncate returned %q. Expected %q", got, "日本語…")
+	}
+}
@@ -1,4 +1,5 @@
-// control_structures.go
+// switch_case_construct_2.go
+//golden:unordered (?m)(^color \w+ -> #[0-9A-F]+\n)+
 package main
 
 import "fmt"
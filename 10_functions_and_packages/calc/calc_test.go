@@ -0,0 +1,299 @@
+package calc
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEval(t *testing.T) {
+	var tests = []struct {
+		expr     string
+		expected float64
+	}{
+		{"3 + 5", 8},
+		{"4 * 6", 24},
+		{"10 / 2", 5},
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 - 4 - 3", 3},
+		{"2 ^ 3 ^ 2", 512},
+		{"2 ** 10", 1024},
+		{"-2 ^ 2", -4},
+		{"2 * -3", -6},
+		{"--3", 3},
+		{"7 % 3", 1},
+		{"1.5e2 + .5", 150.5},
+		{"sqrt(16)", 4},
+		{"pow(2, 0.5) ^ 2", 2.0000000000000004},
+		{"min(3, 1, 2) + max(3, 1, 2)", 4},
+		{"abs(-2.5)", 2.5},
+		{"pi", math.Pi},
+		{"x = 4", 4},
+	}
+
+	for _, test := range tests {
+		got, err := Eval(test.expr)
+		if err != nil {
+			t.Errorf("Eval(%q) returned an error: %v", test.expr, err)
+		} else if got != test.expected {
+			t.Errorf("Eval(%q) returned %v. Expected %v", test.expr, got, test.expected)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	var tests = []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"10 / 0", 4, "cannot divide by zero"},
+		{"1 % (2 - 2)", 3, "cannot divide by zero"},
+		{"2 +", 4, "expected a number"},
+		{"(1 + 2", 7, "expected \")\""},
+		{"1 2", 3, "unexpected \"2\""},
+		{"3 $ 4", 3, "unexpected character"},
+		{"y + 1", 1, "undefined variable \"y\""},
+		{"foo(1)", 1, "unknown function"},
+		{"sqrt(1, 2)", 1, "expects 1 argument"},
+		{"max()", 1, "expects at least 1"},
+		{"1 + sqrt(-4)", 5, "negative"},
+		{"(-8) ^ 0.5", 6, "not a real number"},
+		{"1.2.3", 4, "malformed number"},
+		{"sqrt = 2", 1, "cannot assign"},
+	}
+
+	for _, test := range tests {
+		_, err := Eval(test.expr)
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("Eval(%q) returned %v. Expected an *Error", test.expr, err)
+			continue
+		}
+		if e.Pos != test.pos || !strings.Contains(e.Msg, test.msg) {
+			t.Errorf("Eval(%q) returned %q at column %d. Expected %q at column %d",
+				test.expr, e.Msg, e.Pos, test.msg, test.pos)
+		}
+	}
+}
+
+func TestEnv(t *testing.T) {
+	env := NewEnv()
+	for _, line := range []string{"w = 3", "h = 4", "area = w * h"} {
+		if _, err := env.Eval(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, ok := env.Get("area"); !ok || v != 12 {
+		t.Errorf("area is %v, %v. Expected 12", v, ok)
+	}
+}
+
+func TestREPL(t *testing.T) {
+	input := strings.Join([]string{
+		"r = 2",
+		"r * 3",
+		"ans + 1",
+		"1 / 0",
+		"!1",
+		"!9",
+		":nope",
+		":quit",
+		"never evaluated",
+	}, "\n")
+	var out strings.Builder
+	repl := NewREPL()
+	if err := repl.Run(strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"> 2",
+		"> 6",
+		"> 7",
+		">     ^",
+		"error: column 3: cannot divide by zero",
+		">   r = 2",
+		"2",
+		"> error: no history entry 9",
+		"> error: unknown command :nope (try :help)",
+		"> ",
+	}, "\n")
+	if out.String() != expected {
+		t.Errorf("REPL output:\n%s\nExpected:\n%s", out.String(), expected)
+	}
+	if len(repl.History) != 5 {
+		t.Errorf("History has %d entries. Expected 5: %q", len(repl.History), repl.History)
+	}
+}
+
+func TestModes(t *testing.T) {
+	var tests = []struct {
+		mode     Mode
+		prec     uint
+		expr     string
+		expected string
+	}{
+		{Float, 0, "0.1 + 0.2", "0.30000000000000004"},
+		{Rat, 0, "0.1 + 0.2", "3/10"},
+		{Rat, 0, "1 / 3 + 1 / 6", "1/2"},
+		{Rat, 0, "(2/3) ^ -2", "9/4"},
+		{Rat, 0, "sqrt(9/16)", "3/4"},
+		{Rat, 0, "7.5 % 2", "3/2"},
+		{Rat, 0, "max(1/3, 0.3)", "1/3"},
+		{Int, 0, "2 ^ 100", "1267650600228229401496703205376"},
+		{Int, 0, "7 / 2", "3"},
+		{Int, 0, "-7 % 3", "-1"},
+		{Int, 0, "sqrt(17)", "4"},
+		{BigFloat, 0, "1 / 3", "0.333333333333333333333333333333333333333333333333333333333333333333333333333335"},
+		{Float, 0, "2 ^ 64 + 1", "1.8446744073709552e+19"},
+		{BigFloat, 128, "2 ^ 64 + 1", "1.8446744073709551617e+19"},
+		{BigFloat, 0, "2 ^ -3 + sqrt(0.25)", "0.625"},
+		{BigFloat, 0, "(10^1000000)^600 % 3", "0"},
+		{BigFloat, 0, "(10^1000000)^-700", "0"},
+		{BigFloat, 0, "7.5 % -2", "1.5"},
+	}
+
+	for _, test := range tests {
+		env := NewEnv()
+		env.SetMode(test.mode, test.prec)
+		got, err := env.EvalNumber(test.expr)
+		if err != nil {
+			t.Errorf("%s mode: EvalNumber(%q) returned an error: %v", test.mode, test.expr, err)
+		} else if got.String() != test.expected {
+			t.Errorf("%s mode: EvalNumber(%q) returned %s. Expected %s", test.mode, test.expr, got.String(), test.expected)
+		}
+	}
+}
+
+func TestModeErrors(t *testing.T) {
+	var tests = []struct {
+		mode Mode
+		expr string
+		pos  int
+		msg  string
+	}{
+		{Rat, "1 / (1/2 - 0.5)", 3, "cannot divide by zero"},
+		{Rat, "1 % 0", 3, "cannot divide by zero"},
+		{Rat, "0 ^ -1", 3, "cannot divide by zero"},
+		{Rat, "2 ^ 0.5", 3, "not exact"},
+		{Rat, "sqrt(2)", 1, "not rational"},
+		{Rat, "pi", 1, "undefined variable"},
+		{Int, "10 / 0", 4, "cannot divide by zero"},
+		{Int, "1.5 + 1", 1, "not an integer"},
+		{Int, "2 ^ -1", 3, "negative exponents"},
+		{BigFloat, "1 / 0", 3, "cannot divide by zero"},
+		{BigFloat, "2 ^ 0.5", 3, "non-integer exponents"},
+		{BigFloat, "10^2000000000 - 10^2000000000", 3, "exponent is too large"},
+		{BigFloat, "0 * 10^2000000000", 7, "exponent is too large"},
+		{BigFloat, "10^2000000000 % 3", 3, "exponent is too large"},
+		{BigFloat, "(10^1000000)^700", 13, "too large"},
+		{BigFloat, "(10^1000000)^600 * (10^1000000)^600", 18, "too large"},
+		{BigFloat, "(10^1000000)^600 / 1e-100000000", 18, "too large"},
+		{BigFloat, "(10^1000000)^600 % 1e-100000000", 18, "too large"},
+		{Int, "3^30000000", 2, "exponent is too large"},
+		{Rat, "3^-30000000", 2, "exponent is too large"},
+	}
+
+	for _, test := range tests {
+		env := NewEnv()
+		env.SetMode(test.mode, 0)
+		_, err := env.EvalNumber(test.expr)
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("%s mode: EvalNumber(%q) returned %v. Expected an *Error", test.mode, test.expr, err)
+			continue
+		}
+		if e.Pos != test.pos || !strings.Contains(e.Msg, test.msg) {
+			t.Errorf("%s mode: EvalNumber(%q) returned %q at column %d. Expected %q at column %d",
+				test.mode, test.expr, e.Msg, e.Pos, test.msg, test.pos)
+		}
+	}
+}
+
+func TestSetMode(t *testing.T) {
+	env := NewEnv()
+	if _, err := env.Eval("x = 2.5"); err != nil {
+		t.Fatal(err)
+	}
+	env.SetMode(Rat, 0)
+	if v, _ := env.Lookup("x"); v.String() != "5/2" {
+		t.Errorf("x in rat mode is %s. Expected 5/2", v.String())
+	}
+	if _, ok := env.Lookup("pi"); ok {
+		t.Errorf("pi is defined in rat mode")
+	}
+	env.SetMode(Int, 0)
+	if v, _ := env.Lookup("x"); v.String() != "2" {
+		t.Errorf("x in int mode is %s. Expected 2", v.String())
+	}
+	env.SetMode(BigFloat, 128)
+	if m, prec := env.Mode(); m != BigFloat || prec != 128 {
+		t.Errorf("Mode() returned %s, %d. Expected bigfloat, 128", m, prec)
+	}
+	if v, _ := env.Lookup("pi"); !strings.HasPrefix(v.String(), "3.14159265358979323846264338327950288") {
+		t.Errorf("pi in bigfloat mode is %s", v.String())
+	}
+}
+
+func TestREPLMode(t *testing.T) {
+	input := strings.Join([]string{
+		":mode rat",
+		"1 / 3",
+		"ans * 3",
+		":mode bigfloat 64",
+		":mode int 64",
+		":mode hex",
+		":mode",
+	}, "\n")
+	var out strings.Builder
+	if err := NewREPL().Run(strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"> mode rat",
+		"> 1/3 (≈ 0.3333333333333333)",
+		"> 1",
+		"> mode bigfloat, 64 bits",
+		"> error: precision only applies to bigfloat mode",
+		"> error: unknown mode \"hex\" (want float, int, rat or bigfloat)",
+		"> mode bigfloat, 64 bits",
+		"> ",
+		"",
+	}, "\n")
+	if out.String() != expected {
+		t.Errorf("REPL output:\n%s\nExpected:\n%s", out.String(), expected)
+	}
+}
+
+// TestBigFloatString prints numbers with huge exponents, whose exact
+// shortest decimal would take minutes to find.
+func TestBigFloatString(t *testing.T) {
+	var tests = []struct {
+		expr     string
+		expected string
+	}{
+		{"0.5^1000000", "1.0100340591980302247e-301030"},
+		{"(1/3)^1048576", "1.268268118114556538e-500298"},
+		{"-(2^1000000)", "-9.9006562292958982507e+301029"},
+		{"2^-3", "0.125"},
+	}
+	start := time.Now()
+	for _, test := range tests {
+		env := NewEnv()
+		env.SetMode(BigFloat, 64)
+		got, err := env.EvalNumber(test.expr)
+		if err != nil {
+			t.Errorf("EvalNumber(%q) returned an error: %v", test.expr, err)
+		} else if got.String() != test.expected {
+			t.Errorf("EvalNumber(%q) returned %s. Expected %s", test.expr, got.String(), test.expected)
+		}
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("printing took %v. Expected well under a second", d)
+	}
+}
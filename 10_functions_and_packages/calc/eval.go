@@ -0,0 +1,288 @@
+// Package calc is an arithmetic expression calculator: a tokenizer, a
+// precedence-climbing parser and an evaluator with variables and functions.
+// It grows the lab's add, multiply and divide functions into a language:
+//
+//	x = 10
+//	sqrt(x^2 + 5) / max(1, 2, 3)
+//
+// Errors carry the 1-based column at which they occurred.
+//
+// Numbers are float64 by default. Env.SetMode switches to math/big
+// arithmetic: Int for unbounded integers, Rat for exact fractions and
+// BigFloat for floating point with a chosen precision.
+package calc
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Env holds variables and the number mode. The zero value is not usable;
+// use NewEnv.
+type Env struct {
+	vars map[string]Number
+	mode Mode
+	prec uint
+	a    arith
+}
+
+// NewEnv returns a float64 environment with the constants pi and e defined.
+func NewEnv() *Env {
+	env := &Env{vars: map[string]Number{}, a: floatArith{}}
+	for k, v := range env.a.constants() {
+		env.vars[k] = v
+	}
+	return env
+}
+
+// Mode returns the current number mode and, for BigFloat, its precision in
+// bits.
+func (env *Env) Mode() (Mode, uint) {
+	return env.mode, env.prec
+}
+
+// SetMode switches the number mode. prec is the big.Float precision in bits
+// and is only used by BigFloat; 0 selects DefaultPrec. Existing variables are
+// converted: exactly where possible, truncated toward zero for Int. The
+// constants pi and e exist only in the Float and BigFloat modes.
+func (env *Env) SetMode(m Mode, prec uint) {
+	if m == BigFloat && prec == 0 {
+		prec = DefaultPrec
+	}
+	if m != BigFloat {
+		prec = 0
+	}
+	old := env.a.constants()
+	env.mode, env.prec, env.a = m, prec, newArith(m, prec)
+	for k, v := range env.vars {
+		if c, ok := old[k]; ok && c.String() == v.String() {
+			delete(env.vars, k)
+			continue
+		}
+		env.vars[k] = env.a.convert(v)
+	}
+	for k, v := range env.a.constants() {
+		if _, ok := env.vars[k]; !ok {
+			env.vars[k] = v
+		}
+	}
+}
+
+// Get returns the value of a variable as a float64.
+func (env *Env) Get(name string) (float64, bool) {
+	v, ok := env.vars[name]
+	if !ok {
+		return 0, false
+	}
+	return v.Float64(), true
+}
+
+// Lookup returns the value of a variable in the current mode.
+func (env *Env) Lookup(name string) (Number, bool) {
+	v, ok := env.vars[name]
+	return v, ok
+}
+
+// Set assigns a variable, converting v to the current mode.
+func (env *Env) Set(name string, v float64) {
+	env.vars[name] = env.a.convert(floatNum(v))
+}
+
+// Store assigns a variable, converting n to the current mode.
+func (env *Env) Store(name string, n Number) {
+	env.vars[name] = env.a.convert(n)
+}
+
+// Names returns the defined variable names in sorted order.
+func (env *Env) Names() []string {
+	names := make([]string, 0, len(env.vars))
+	for k := range env.vars {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Eval parses and evaluates src in env and returns the result as a float64.
+// Assignments store their value in env and also return it.
+func (env *Env) Eval(src string) (float64, error) {
+	v, err := env.EvalNumber(src)
+	if err != nil {
+		return 0, err
+	}
+	return v.Float64(), nil
+}
+
+// EvalNumber is like Eval but returns the result in the current mode, so
+// that Rat results stay exact and Int and BigFloat results keep every digit.
+func (env *Env) EvalNumber(src string) (Number, error) {
+	n, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return env.evalNode(n)
+}
+
+// Eval evaluates src in a fresh environment.
+func Eval(src string) (float64, error) {
+	return NewEnv().Eval(src)
+}
+
+func (env *Env) evalNode(n Node) (Number, error) {
+	switch n := n.(type) {
+	case *Num:
+		v, err := env.a.parse(n.Lit)
+		if err != nil {
+			return nil, errorf(n.At, "%s", err)
+		}
+		return v, nil
+	case *Var:
+		v, ok := env.vars[n.Name]
+		if !ok {
+			return nil, errorf(n.At, "undefined variable %q", n.Name)
+		}
+		return v, nil
+	case *Unary:
+		x, err := env.evalNode(n.X)
+		if err != nil {
+			return nil, err
+		}
+		if n.Op == "-" {
+			return env.a.neg(x), nil
+		}
+		return x, nil
+	case *Binary:
+		l, err := env.evalNode(n.L)
+		if err != nil {
+			return nil, err
+		}
+		r, err := env.evalNode(n.R)
+		if err != nil {
+			return nil, err
+		}
+		return env.binary(n, l, r)
+	case *Call:
+		f, ok := functions[n.Name]
+		if !ok {
+			return nil, errorf(n.At, "unknown function %q", n.Name)
+		}
+		if len(n.Args) < f.minArgs || (f.maxArgs >= 0 && len(n.Args) > f.maxArgs) {
+			return nil, errorf(n.At, "%s: %s", n.Name, f.arity())
+		}
+		args := make([]Number, len(n.Args))
+		for i, a := range n.Args {
+			v, err := env.evalNode(a)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = v
+		}
+		v, err := f.fn(env.a, args)
+		if err != nil {
+			return nil, errorf(n.At, "%s: %s", n.Name, err)
+		}
+		return v, nil
+	case *Assign:
+		if _, ok := functions[n.Name]; ok {
+			return nil, errorf(n.At, "cannot assign to function %q", n.Name)
+		}
+		v, err := env.evalNode(n.X)
+		if err != nil {
+			return nil, err
+		}
+		env.vars[n.Name] = v
+		return v, nil
+	}
+	return nil, errorf(n.Pos(), "unsupported expression")
+}
+
+func (env *Env) binary(n *Binary, l, r Number) (Number, error) {
+	var v Number
+	var err error
+	switch n.Op {
+	case "+":
+		v, err = env.a.add(l, r)
+	case "-":
+		v, err = env.a.sub(l, r)
+	case "*":
+		v, err = env.a.mul(l, r)
+	case "/":
+		// Same rule and message as the lab's divide function, in every mode.
+		v, err = env.a.quo(l, r)
+	case "%":
+		v, err = env.a.rem(l, r)
+	case "^":
+		v, err = env.a.pow(l, r)
+	default:
+		return nil, errorf(n.At, "unknown operator %q", n.Op)
+	}
+	if err != nil {
+		return nil, errorf(n.At, "%s", err)
+	}
+	return v, nil
+}
+
+type function struct {
+	minArgs, maxArgs int // maxArgs < 0 means variadic
+	fn               func(a arith, args []Number) (Number, error)
+}
+
+func (f function) arity() string {
+	switch {
+	case f.maxArgs < 0:
+		return "expects at least " + strconv.Itoa(f.minArgs) + " argument(s)"
+	case f.minArgs == 1 && f.maxArgs == 1:
+		return "expects 1 argument"
+	}
+	return "expects " + strconv.Itoa(f.minArgs) + " arguments"
+}
+
+var functions = map[string]function{
+	"sqrt": {1, 1, func(a arith, args []Number) (Number, error) {
+		return a.sqrt(args[0])
+	}},
+	"pow": {2, 2, func(a arith, args []Number) (Number, error) {
+		return a.pow(args[0], args[1])
+	}},
+	"min": {1, -1, func(a arith, args []Number) (Number, error) {
+		return extreme(a, args, -1), nil
+	}},
+	"max": {1, -1, func(a arith, args []Number) (Number, error) {
+		return extreme(a, args, 1), nil
+	}},
+	"abs": {1, 1, func(a arith, args []Number) (Number, error) {
+		return a.abs(args[0]), nil
+	}},
+}
+
+// extreme returns the smallest (sign -1) or largest (sign 1) argument. As
+// with math.Min and math.Max, a float64 NaN wins.
+func extreme(a arith, args []Number, sign int) Number {
+	m := args[0]
+	for _, v := range args[1:] {
+		if f, ok := v.(floatNum); ok && math.IsNaN(float64(f)) {
+			return v
+		}
+		if a.cmp(v, m) == sign {
+			m = v
+		}
+	}
+	return m
+}
+
+// Functions returns the names of the built-in functions in sorted order.
+func Functions() []string {
+	names := make([]string, 0, len(functions))
+	for k := range functions {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Format renders a result without a trailing ".0" or exponent noise for
+// ordinary values.
+func Format(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
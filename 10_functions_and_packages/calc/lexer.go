@@ -0,0 +1,124 @@
+package calc
+
+import (
+	"fmt"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp     // + - * / % ^
+	tokLParen // (
+	tokRParen // )
+	tokComma  // ,
+	tokAssign // =
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based column of the first rune
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// Error is a syntax or evaluation error at a 1-based column of the input.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+func errorf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// tokenize splits src into tokens, ending with a tokEOF.
+func tokenize(src string) ([]token, error) {
+	runes := []rune(src)
+	var toks []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case isDigit(r) || (r == '.' && i+1 < len(runes) && isDigit(runes[i+1])):
+			i = scanNumber(runes, i)
+			if i < len(runes) && (unicode.IsLetter(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				return nil, errorf(i+1, "malformed number %q", string(runes[start:i+1]))
+			}
+			toks = append(toks, token{tokNumber, string(runes[start:i]), start + 1})
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			toks = append(toks, token{tokIdent, string(runes[start:i]), start + 1})
+			continue
+		case r == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			// Accept ** as an alias for ^.
+			toks = append(toks, token{tokOp, "^", start + 1})
+			i += 2
+			continue
+		case r == '+' || r == '-' || r == '*' || r == '/' || r == '%' || r == '^':
+			toks = append(toks, token{tokOp, string(r), start + 1})
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", start + 1})
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", start + 1})
+		case r == ',':
+			toks = append(toks, token{tokComma, ",", start + 1})
+		case r == '=':
+			toks = append(toks, token{tokAssign, "=", start + 1})
+		default:
+			return nil, errorf(start+1, "unexpected character %q", r)
+		}
+		i++
+	}
+	return append(toks, token{tokEOF, "", len(runes) + 1}), nil
+}
+
+// scanNumber returns the index just past a decimal number with an optional
+// fraction and exponent starting at i.
+func scanNumber(runes []rune, i int) int {
+	for i < len(runes) && isDigit(runes[i]) {
+		i++
+	}
+	if i < len(runes) && runes[i] == '.' {
+		i++
+		for i < len(runes) && isDigit(runes[i]) {
+			i++
+		}
+	}
+	if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+		j := i + 1
+		if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+			j++
+		}
+		if j < len(runes) && isDigit(runes[j]) {
+			for j < len(runes) && isDigit(runes[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
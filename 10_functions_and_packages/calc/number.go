@@ -0,0 +1,579 @@
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Mode selects how numbers are represented and computed.
+type Mode int
+
+const (
+	// Float uses float64, like the lab's divide function.
+	Float Mode = iota
+	// Int uses big.Int: unbounded integers with truncated division.
+	Int
+	// Rat uses big.Rat: exact fractions, so 0.1 + 0.2 is exactly 3/10.
+	Rat
+	// BigFloat uses big.Float with a configurable precision in bits.
+	BigFloat
+)
+
+// DefaultPrec is the big.Float precision used when none is given, about 77
+// decimal digits.
+const DefaultPrec = 256
+
+var modeNames = map[Mode]string{Float: "float", Int: "int", Rat: "rat", BigFloat: "bigfloat"}
+
+func (m Mode) String() string {
+	if s, ok := modeNames[m]; ok {
+		return s
+	}
+	return "Mode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// ParseMode parses a mode name as printed by Mode.String.
+func ParseMode(s string) (Mode, error) {
+	for m, name := range modeNames {
+		if strings.EqualFold(s, name) {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown mode %q (want float, int, rat or bigfloat)", s)
+}
+
+// Number is a calculator value. Its dynamic type depends on the Mode it was
+// computed in.
+type Number interface {
+	String() string
+	Float64() float64
+}
+
+type floatNum float64
+
+func (x floatNum) String() string   { return Format(float64(x)) }
+func (x floatNum) Float64() float64 { return float64(x) }
+
+type intNum struct{ *big.Int }
+
+func (x intNum) Float64() float64 {
+	f, _ := new(big.Float).SetInt(x.Int).Float64()
+	return f
+}
+
+type ratNum struct{ *big.Rat }
+
+// String prints integers without a denominator and fractions as "n/d".
+func (x ratNum) String() string {
+	if x.IsInt() {
+		return x.Num().String()
+	}
+	return x.RatString()
+}
+
+func (x ratNum) Float64() float64 {
+	f, _ := x.Rat.Float64()
+	return f
+}
+
+type bigFloatNum struct{ *big.Float }
+
+// maxTextExp bounds the binary exponents that String prints with the exact
+// shortest decimal: finding it takes time that grows steeply with the
+// exponent, up to minutes for 0.5^1000000.
+const maxTextExp = 1 << 14
+
+// String prints the shortest decimal that rounds to x, or for very large or
+// small numbers as many digits as its precision carries.
+func (x bigFloatNum) String() string {
+	if exp := x.MantExp(nil); exp > -maxTextExp && exp < maxTextExp {
+		return x.Text('g', -1)
+	}
+	return sciText(x.Float)
+}
+
+// sciText formats x in scientific notation without converting its whole
+// binary expansion to decimal: it scales x by the power of ten of its
+// decimal exponent, computed at a higher precision, and prints the result.
+func sciText(x *big.Float) string {
+	prec := x.Prec() + 64
+	d := int64(math.Floor(float64(x.MantExp(nil)-1) * math.Log10(2)))
+	p := new(big.Float).SetPrec(prec).SetInt64(1)
+	sq := new(big.Float).SetPrec(prec).SetInt64(10)
+	for n := d; n != 0; n /= 2 {
+		if n%2 != 0 {
+			p.Mul(p, sq)
+		}
+		sq.Mul(sq, sq)
+	}
+	m := new(big.Float).SetPrec(prec).Abs(x)
+	if d >= 0 {
+		m.Quo(m, p)
+	} else {
+		m.Mul(m, p)
+	}
+	// The estimate of d may be off by one.
+	one, ten := big.NewFloat(1), big.NewFloat(10)
+	for m.Cmp(ten) >= 0 {
+		m.Quo(m, ten)
+		d++
+	}
+	for m.Cmp(one) < 0 {
+		m.Mul(m, ten)
+		d--
+	}
+	digits := int(float64(x.Prec())*math.Log10(2)) + 1
+	mant := m.Text('f', digits-1)
+	if strings.HasPrefix(mant, "10") {
+		// Rounding carried into a new digit.
+		mant = "1"
+		d++
+	}
+	if strings.Contains(mant, ".") {
+		mant = strings.TrimRight(strings.TrimRight(mant, "0"), ".")
+	}
+	if x.Sign() < 0 {
+		mant = "-" + mant
+	}
+	return fmt.Sprintf("%se%+d", mant, d)
+}
+
+func (x bigFloatNum) Float64() float64 {
+	f, _ := x.Float.Float64()
+	return f
+}
+
+var (
+	errDivByZero = errors.New("cannot divide by zero")
+	errNegSqrt   = errors.New("square root of a negative number")
+	errOverflow  = errors.New("result is too large")
+	errExponent  = fmt.Errorf("exponent is too large (the limit is %d)", maxExponent)
+)
+
+// maxExponent bounds the exponent of pow in the exact modes, where the
+// result grows with it: 3^30000000 already takes seconds to compute.
+const maxExponent = 1 << 20
+
+// checkExponent reports whether e is within ±maxExponent.
+func checkExponent(e *big.Int) error {
+	if e.CmpAbs(big.NewInt(maxExponent)) > 0 {
+		return errExponent
+	}
+	return nil
+}
+
+// arith implements the operations of one Mode. Operands are always Numbers
+// of that mode's type.
+type arith interface {
+	parse(lit string) (Number, error)
+	convert(x Number) Number
+	neg(x Number) Number
+	add(x, y Number) (Number, error)
+	sub(x, y Number) (Number, error)
+	mul(x, y Number) (Number, error)
+	quo(x, y Number) (Number, error)
+	rem(x, y Number) (Number, error)
+	pow(x, y Number) (Number, error)
+	sqrt(x Number) (Number, error)
+	abs(x Number) Number
+	cmp(x, y Number) int
+	// constants returns the predefined variables of the mode.
+	constants() map[string]Number
+}
+
+func newArith(m Mode, prec uint) arith {
+	switch m {
+	case Int:
+		return intArith{}
+	case Rat:
+		return ratArith{}
+	case BigFloat:
+		if prec == 0 {
+			prec = DefaultPrec
+		}
+		return bigFloatArith{prec}
+	}
+	return floatArith{}
+}
+
+// toRat converts any Number exactly to a big.Rat; infinities and NaN yield nil.
+func toRat(x Number) *big.Rat {
+	switch x := x.(type) {
+	case floatNum:
+		if math.IsInf(float64(x), 0) || math.IsNaN(float64(x)) {
+			return nil
+		}
+		return new(big.Rat).SetFloat64(float64(x))
+	case intNum:
+		return new(big.Rat).SetInt(x.Int)
+	case ratNum:
+		return new(big.Rat).Set(x.Rat)
+	case bigFloatNum:
+		if x.IsInf() {
+			return nil
+		}
+		r, _ := x.Rat(nil)
+		return r
+	}
+	return nil
+}
+
+// float64 mode
+
+type floatArith struct{}
+
+func (floatArith) parse(lit string) (Number, error) {
+	v, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", lit)
+	}
+	return floatNum(v), nil
+}
+
+func (floatArith) convert(x Number) Number { return floatNum(x.Float64()) }
+func (floatArith) neg(x Number) Number     { return -x.(floatNum) }
+func (floatArith) abs(x Number) Number     { return floatNum(math.Abs(x.Float64())) }
+func (floatArith) cmp(x, y Number) int     { return cmpFloat(x.Float64(), y.Float64()) }
+func (floatArith) constants() map[string]Number {
+	return map[string]Number{"pi": floatNum(math.Pi), "e": floatNum(math.E)}
+}
+
+func (floatArith) add(x, y Number) (Number, error) { return x.(floatNum) + y.(floatNum), nil }
+func (floatArith) sub(x, y Number) (Number, error) { return x.(floatNum) - y.(floatNum), nil }
+func (floatArith) mul(x, y Number) (Number, error) { return x.(floatNum) * y.(floatNum), nil }
+
+func (floatArith) quo(x, y Number) (Number, error) {
+	if y.(floatNum) == 0 {
+		return nil, errDivByZero
+	}
+	return x.(floatNum) / y.(floatNum), nil
+}
+
+func (floatArith) rem(x, y Number) (Number, error) {
+	if y.(floatNum) == 0 {
+		return nil, errDivByZero
+	}
+	return floatNum(math.Mod(x.Float64(), y.Float64())), nil
+}
+
+func (floatArith) pow(x, y Number) (Number, error) {
+	v := math.Pow(x.Float64(), y.Float64())
+	if math.IsNaN(v) {
+		return nil, fmt.Errorf("%s ^ %s is not a real number", x, y)
+	}
+	return floatNum(v), nil
+}
+
+func (floatArith) sqrt(x Number) (Number, error) {
+	if x.Float64() < 0 {
+		return nil, errNegSqrt
+	}
+	return floatNum(math.Sqrt(x.Float64())), nil
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// big.Int mode
+
+type intArith struct{}
+
+func bi(x Number) *big.Int { return x.(intNum).Int }
+
+func (intArith) parse(lit string) (Number, error) {
+	v, ok := new(big.Int).SetString(lit, 10)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an integer", lit)
+	}
+	return intNum{v}, nil
+}
+
+// convert truncates fractions toward zero.
+func (intArith) convert(x Number) Number {
+	r := toRat(x)
+	if r == nil {
+		return intNum{new(big.Int)}
+	}
+	return intNum{new(big.Int).Quo(r.Num(), r.Denom())}
+}
+
+func (intArith) neg(x Number) Number { return intNum{new(big.Int).Neg(bi(x))} }
+func (intArith) abs(x Number) Number { return intNum{new(big.Int).Abs(bi(x))} }
+func (intArith) cmp(x, y Number) int { return bi(x).Cmp(bi(y)) }
+func (intArith) constants() map[string]Number {
+	return nil
+}
+
+func (intArith) add(x, y Number) (Number, error) {
+	return intNum{new(big.Int).Add(bi(x), bi(y))}, nil
+}
+
+func (intArith) sub(x, y Number) (Number, error) {
+	return intNum{new(big.Int).Sub(bi(x), bi(y))}, nil
+}
+
+func (intArith) mul(x, y Number) (Number, error) {
+	return intNum{new(big.Int).Mul(bi(x), bi(y))}, nil
+}
+
+// quo truncates toward zero, like Go's integer division.
+func (intArith) quo(x, y Number) (Number, error) {
+	if bi(y).Sign() == 0 {
+		return nil, errDivByZero
+	}
+	return intNum{new(big.Int).Quo(bi(x), bi(y))}, nil
+}
+
+func (intArith) rem(x, y Number) (Number, error) {
+	if bi(y).Sign() == 0 {
+		return nil, errDivByZero
+	}
+	return intNum{new(big.Int).Rem(bi(x), bi(y))}, nil
+}
+
+func (intArith) pow(x, y Number) (Number, error) {
+	if bi(y).Sign() < 0 {
+		return nil, errors.New("negative exponents are not supported in int mode")
+	}
+	if err := checkExponent(bi(y)); err != nil {
+		return nil, err
+	}
+	return intNum{new(big.Int).Exp(bi(x), bi(y), nil)}, nil
+}
+
+// sqrt returns the integer square root, rounded down.
+func (intArith) sqrt(x Number) (Number, error) {
+	if bi(x).Sign() < 0 {
+		return nil, errNegSqrt
+	}
+	return intNum{new(big.Int).Sqrt(bi(x))}, nil
+}
+
+// big.Rat mode
+
+type ratArith struct{}
+
+func br(x Number) *big.Rat { return x.(ratNum).Rat }
+
+func (ratArith) parse(lit string) (Number, error) {
+	v, ok := new(big.Rat).SetString(lit)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", lit)
+	}
+	return ratNum{v}, nil
+}
+
+func (ratArith) convert(x Number) Number {
+	if r := toRat(x); r != nil {
+		return ratNum{r}
+	}
+	return ratNum{new(big.Rat)}
+}
+
+func (ratArith) neg(x Number) Number { return ratNum{new(big.Rat).Neg(br(x))} }
+func (ratArith) abs(x Number) Number { return ratNum{new(big.Rat).Abs(br(x))} }
+func (ratArith) cmp(x, y Number) int { return br(x).Cmp(br(y)) }
+func (ratArith) constants() map[string]Number {
+	return nil
+}
+
+func (ratArith) add(x, y Number) (Number, error) {
+	return ratNum{new(big.Rat).Add(br(x), br(y))}, nil
+}
+
+func (ratArith) sub(x, y Number) (Number, error) {
+	return ratNum{new(big.Rat).Sub(br(x), br(y))}, nil
+}
+
+func (ratArith) mul(x, y Number) (Number, error) {
+	return ratNum{new(big.Rat).Mul(br(x), br(y))}, nil
+}
+
+func (ratArith) quo(x, y Number) (Number, error) {
+	if br(y).Sign() == 0 {
+		return nil, errDivByZero
+	}
+	return ratNum{new(big.Rat).Quo(br(x), br(y))}, nil
+}
+
+// rem returns x - y*trunc(x/y), matching math.Mod.
+func (ratArith) rem(x, y Number) (Number, error) {
+	if br(y).Sign() == 0 {
+		return nil, errDivByZero
+	}
+	q := new(big.Rat).Quo(br(x), br(y))
+	t := new(big.Int).Quo(q.Num(), q.Denom())
+	return ratNum{new(big.Rat).Sub(br(x), new(big.Rat).Mul(br(y), new(big.Rat).SetInt(t)))}, nil
+}
+
+// pow is exact for integer exponents; others have no exact rational result.
+func (ratArith) pow(x, y Number) (Number, error) {
+	if !br(y).IsInt() {
+		return nil, errors.New("non-integer exponents are not exact in rat mode")
+	}
+	e := br(y).Num()
+	if br(x).Sign() == 0 && e.Sign() < 0 {
+		return nil, errDivByZero
+	}
+	if err := checkExponent(e); err != nil {
+		return nil, err
+	}
+	abs := new(big.Int).Abs(e)
+	r := new(big.Rat).SetFrac(
+		new(big.Int).Exp(br(x).Num(), abs, nil),
+		new(big.Int).Exp(br(x).Denom(), abs, nil),
+	)
+	if e.Sign() < 0 {
+		r.Inv(r)
+	}
+	return ratNum{r}, nil
+}
+
+// sqrt is exact for squares of rationals only.
+func (ratArith) sqrt(x Number) (Number, error) {
+	r := br(x)
+	if r.Sign() < 0 {
+		return nil, errNegSqrt
+	}
+	n, d := new(big.Int).Sqrt(r.Num()), new(big.Int).Sqrt(r.Denom())
+	if new(big.Int).Mul(n, n).Cmp(r.Num()) != 0 || new(big.Int).Mul(d, d).Cmp(r.Denom()) != 0 {
+		return nil, fmt.Errorf("square root of %s is not rational", r.RatString())
+	}
+	return ratNum{new(big.Rat).SetFrac(n, d)}, nil
+}
+
+// big.Float mode
+//
+// A big.Float overflows to ±Inf, and operations on infinities panic, so
+// every result that could overflow is checked and rejected with errOverflow.
+// No bigFloatNum is ever infinite.
+
+type bigFloatArith struct {
+	prec uint
+}
+
+func bf(x Number) *big.Float { return x.(bigFloatNum).Float }
+
+// finite wraps x, or returns errOverflow if it is infinite.
+func finite(x *big.Float) (Number, error) {
+	if x.IsInf() {
+		return nil, errOverflow
+	}
+	return bigFloatNum{x}, nil
+}
+
+func (a bigFloatArith) new() *big.Float { return new(big.Float).SetPrec(a.prec) }
+
+func (a bigFloatArith) parse(lit string) (Number, error) {
+	v, _, err := big.ParseFloat(lit, 10, a.prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", lit)
+	}
+	return finite(v)
+}
+
+func (a bigFloatArith) convert(x Number) Number {
+	if r := toRat(x); r != nil {
+		return bigFloatNum{a.new().SetRat(r)}
+	}
+	return bigFloatNum{a.new().SetFloat64(0)}
+}
+
+func (a bigFloatArith) neg(x Number) Number { return bigFloatNum{a.new().Neg(bf(x))} }
+func (a bigFloatArith) abs(x Number) Number { return bigFloatNum{a.new().Abs(bf(x))} }
+func (a bigFloatArith) cmp(x, y Number) int { return bf(x).Cmp(bf(y)) }
+
+func (a bigFloatArith) add(x, y Number) (Number, error) { return finite(a.new().Add(bf(x), bf(y))) }
+func (a bigFloatArith) sub(x, y Number) (Number, error) { return finite(a.new().Sub(bf(x), bf(y))) }
+func (a bigFloatArith) mul(x, y Number) (Number, error) { return finite(a.new().Mul(bf(x), bf(y))) }
+
+// Digits of pi and e, enough for about 330 bits of precision.
+const (
+	piDigits = "3.1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679"
+	eDigits  = "2.7182818284590452353602874713526624977572470936999595749669676277240766303535475945713821785251664274"
+)
+
+// bigPi and bigE hold the digits above at the precision they carry; each
+// mode rounds them to its own.
+var bigPi, bigE = mustParseFloat(piDigits), mustParseFloat(eDigits)
+
+func mustParseFloat(digits string) *big.Float {
+	v, _, err := big.ParseFloat(digits, 10, 340, big.ToNearestEven)
+	if err != nil {
+		panic("calc: bad constant " + digits + ": " + err.Error())
+	}
+	return v
+}
+
+func (a bigFloatArith) constants() map[string]Number {
+	return map[string]Number{"pi": bigFloatNum{a.new().Set(bigPi)}, "e": bigFloatNum{a.new().Set(bigE)}}
+}
+
+func (a bigFloatArith) quo(x, y Number) (Number, error) {
+	if bf(y).Sign() == 0 {
+		return nil, errDivByZero
+	}
+	return finite(a.new().Quo(bf(x), bf(y)))
+}
+
+// rem returns x - y*trunc(x/y), matching math.Mod.
+func (a bigFloatArith) rem(x, y Number) (Number, error) {
+	if bf(y).Sign() == 0 {
+		return nil, errDivByZero
+	}
+	q := a.new().Quo(bf(x), bf(y))
+	if q.IsInf() {
+		return nil, errOverflow
+	}
+	// A quotient too large for its fraction bits is an integer already;
+	// converting it to a big.Int could take billions of bits.
+	if !q.IsInt() {
+		t, _ := q.Int(nil)
+		q.SetInt(t)
+	}
+	return finite(a.new().Sub(bf(x), a.new().Mul(bf(y), q)))
+}
+
+// pow supports integer exponents at full precision.
+func (a bigFloatArith) pow(x, y Number) (Number, error) {
+	if !bf(y).IsInt() {
+		return nil, errors.New("non-integer exponents are not supported in bigfloat mode")
+	}
+	e, _ := bf(y).Int(nil)
+	if bf(x).Sign() == 0 && e.Sign() < 0 {
+		return nil, errDivByZero
+	}
+	if err := checkExponent(e); err != nil {
+		return nil, err
+	}
+	result := a.new().SetInt64(1)
+	base := a.new().Set(bf(x))
+	for n := new(big.Int).Abs(e); n.Sign() > 0; n.Rsh(n, 1) {
+		if n.Bit(0) == 1 {
+			result.Mul(result, base)
+		}
+		base.Mul(base, base)
+	}
+	if e.Sign() < 0 {
+		result.Quo(a.new().SetInt64(1), result)
+	}
+	return finite(result)
+}
+
+func (a bigFloatArith) sqrt(x Number) (Number, error) {
+	if bf(x).Sign() < 0 {
+		return nil, errNegSqrt
+	}
+	return bigFloatNum{a.new().Sqrt(bf(x))}, nil
+}
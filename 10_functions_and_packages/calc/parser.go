@@ -0,0 +1,187 @@
+package calc
+
+// Node is a node of the expression syntax tree.
+type Node interface {
+	Pos() int
+}
+
+// Num is a numeric literal.
+type Num struct {
+	At  int
+	Lit string
+}
+
+// Var is a variable reference.
+type Var struct {
+	At   int
+	Name string
+}
+
+// Unary is a prefix + or - applied to X.
+type Unary struct {
+	At int
+	Op string
+	X  Node
+}
+
+// Binary is an infix operation.
+type Binary struct {
+	At   int // column of the operator
+	Op   string
+	L, R Node
+}
+
+// Call is a function call.
+type Call struct {
+	At   int
+	Name string
+	Args []Node
+}
+
+// Assign stores the value of X in a variable.
+type Assign struct {
+	At   int
+	Name string
+	X    Node
+}
+
+func (n *Num) Pos() int    { return n.At }
+func (n *Var) Pos() int    { return n.At }
+func (n *Unary) Pos() int  { return n.At }
+func (n *Binary) Pos() int { return n.At }
+func (n *Call) Pos() int   { return n.At }
+func (n *Assign) Pos() int { return n.At }
+
+// binary operator precedence; ^ is right-associative.
+var precedence = map[string]int{
+	"+": 1, "-": 1,
+	"*": 2, "/": 2, "%": 2,
+	"^": 4,
+}
+
+// unaryPrec sits between * and ^ so that -2^2 is -(2^2) and 2*-3 parses.
+const unaryPrec = 3
+
+// Parse parses a statement: either an expression or an assignment
+// "name = expression".
+func Parse(src string) (Node, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	var n Node
+	if p.peek().kind == tokIdent && p.toks[1].kind == tokAssign {
+		name := p.next()
+		p.next()
+		x, err := p.expr(1)
+		if err != nil {
+			return nil, err
+		}
+		n = &Assign{At: name.pos, Name: name.text, X: x}
+	} else if n, err = p.expr(1); err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorf(t.pos, "unexpected %s", t)
+	}
+	return n, nil
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// expr parses a sequence of operands joined by operators with precedence of
+// at least minPrec (precedence climbing).
+func (p *parser) expr(minPrec int) (Node, error) {
+	lhs, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		prec, ok := precedence[op.text]
+		if op.kind != tokOp || !ok || prec < minPrec {
+			return lhs, nil
+		}
+		p.next()
+		next := prec + 1
+		if op.text == "^" {
+			next = prec
+		}
+		rhs, err := p.expr(next)
+		if err != nil {
+			return nil, err
+		}
+		lhs = &Binary{At: op.pos, Op: op.text, L: lhs, R: rhs}
+	}
+}
+
+func (p *parser) unary() (Node, error) {
+	if t := p.peek(); t.kind == tokOp && (t.text == "-" || t.text == "+") {
+		p.next()
+		x, err := p.expr(unaryPrec)
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{At: t.pos, Op: t.text, X: x}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &Num{At: t.pos, Lit: t.text}, nil
+	case tokIdent:
+		if p.peek().kind != tokLParen {
+			return &Var{At: t.pos, Name: t.text}, nil
+		}
+		p.next()
+		call := &Call{At: t.pos, Name: t.text}
+		if p.peek().kind == tokRParen {
+			p.next()
+			return call, nil
+		}
+		for {
+			arg, err := p.expr(1)
+			if err != nil {
+				return nil, err
+			}
+			call.Args = append(call.Args, arg)
+			switch sep := p.next(); sep.kind {
+			case tokComma:
+				continue
+			case tokRParen:
+				return call, nil
+			default:
+				return nil, errorf(sep.pos, "expected \",\" or \")\", found %s", sep)
+			}
+		}
+	case tokLParen:
+		x, err := p.expr(1)
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokRParen {
+			return nil, errorf(c.pos, "expected \")\", found %s", c)
+		}
+		return x, nil
+	}
+	return nil, errorf(t.pos, "expected a number, variable or \"(\", found %s", t)
+}
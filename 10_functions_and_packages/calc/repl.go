@@ -0,0 +1,181 @@
+package calc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// REPL is an interactive read-eval-print loop over an Env. Each successful
+// result is also stored in the variable "ans".
+type REPL struct {
+	Env     *Env
+	Prompt  string
+	History []string // previously entered lines, oldest first
+}
+
+// NewREPL returns a REPL with a fresh environment.
+func NewREPL() *REPL {
+	return &REPL{Env: NewEnv(), Prompt: "> "}
+}
+
+const replHelp = `Enter an expression such as 2 * (3 + 4) or an assignment such as x = 5.
+Operators: + - * / %% ^   Functions: %s
+Commands:
+  :vars        list variables
+  :mode        show the number mode
+  :mode M [P]  switch to float, int, rat or bigfloat (P bits of precision)
+  :history     list previous lines
+  !!           repeat the last line
+  !N           repeat line N from :history
+  :help        show this help
+  :quit        exit (or end of input)
+`
+
+// Run reads lines from in until end of input or :quit, writing prompts,
+// results and errors to out.
+func (r *REPL) Run(in io.Reader, out io.Writer) error {
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, r.Prompt)
+		if !sc.Scan() {
+			fmt.Fprintln(out)
+			return sc.Err()
+		}
+		if quit := r.Line(sc.Text(), out); quit {
+			return nil
+		}
+	}
+}
+
+// Line handles a single line of input and reports whether it asked to quit.
+func (r *REPL) Line(input string, out io.Writer) (quit bool) {
+	line := strings.TrimSpace(input)
+	if line == "" {
+		return false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		recalled, err := r.recall(line)
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+			return false
+		}
+		// Echo the recalled line where typed input would appear so that
+		// error carets line up with it.
+		line, input = recalled, recalled
+		fmt.Fprintln(out, r.indent(0)+line)
+	}
+
+	switch line {
+	case ":quit", ":q", ":exit":
+		return true
+	case ":help", ":h":
+		fmt.Fprintf(out, replHelp, strings.Join(Functions(), " "))
+		return false
+	case ":history":
+		for i, h := range r.History {
+			fmt.Fprintf(out, "%4d  %s\n", i+1, h)
+		}
+		return false
+	case ":vars":
+		for _, name := range r.Env.Names() {
+			v, _ := r.Env.Lookup(name)
+			fmt.Fprintf(out, "%s = %s\n", name, v.String())
+		}
+		return false
+	}
+	if f := strings.Fields(line); f[0] == ":mode" {
+		if err := r.mode(f[1:], out); err != nil {
+			fmt.Fprintln(out, "error:", err)
+		}
+		return false
+	}
+	if strings.HasPrefix(line, ":") {
+		fmt.Fprintf(out, "error: unknown command %s (try :help)\n", line)
+		return false
+	}
+
+	r.History = append(r.History, line)
+	v, err := r.Env.EvalNumber(input)
+	if err != nil {
+		r.printError(out, err)
+		return false
+	}
+	r.Env.Store("ans", v)
+	fmt.Fprintln(out, result(v))
+	return false
+}
+
+// mode shows or switches the number mode: ":mode", ":mode rat",
+// ":mode bigfloat 512".
+func (r *REPL) mode(args []string, out io.Writer) error {
+	if len(args) > 2 {
+		return errors.New("usage: :mode [float|int|rat|bigfloat [precision]]")
+	}
+	if len(args) > 0 {
+		m, err := ParseMode(args[0])
+		if err != nil {
+			return err
+		}
+		var prec uint64
+		if len(args) == 2 {
+			if m != BigFloat {
+				return fmt.Errorf("precision only applies to bigfloat mode")
+			}
+			prec, err = strconv.ParseUint(args[1], 10, 32)
+			if err != nil || prec == 0 {
+				return fmt.Errorf("invalid precision %q", args[1])
+			}
+		}
+		r.Env.SetMode(m, uint(prec))
+	}
+	m, prec := r.Env.Mode()
+	if m == BigFloat {
+		fmt.Fprintf(out, "mode %s, %d bits\n", m, prec)
+	} else {
+		fmt.Fprintf(out, "mode %s\n", m)
+	}
+	return nil
+}
+
+// result formats a value for display; fractions also show their decimal
+// approximation.
+func result(v Number) string {
+	if r, ok := v.(ratNum); ok && !r.IsInt() {
+		return v.String() + " (≈ " + Format(v.Float64()) + ")"
+	}
+	return v.String()
+}
+
+// recall resolves !! and !N against the history.
+func (r *REPL) recall(line string) (string, error) {
+	if len(r.History) == 0 {
+		return "", errors.New("history is empty")
+	}
+	if line == "!!" {
+		return r.History[len(r.History)-1], nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 || n > len(r.History) {
+		return "", fmt.Errorf("no history entry %s", line[1:])
+	}
+	return r.History[n-1], nil
+}
+
+// printError points at the offending column under the echoed input.
+func (r *REPL) printError(out io.Writer, err error) {
+	var e *Error
+	if errors.As(err, &e) && e.Pos > 0 {
+		fmt.Fprintln(out, r.indent(e.Pos-1)+"^")
+	}
+	fmt.Fprintln(out, "error:", err)
+}
+
+// indent returns spaces covering the prompt plus n columns.
+func (r *REPL) indent(n int) string {
+	return strings.Repeat(" ", len([]rune(r.Prompt))+n)
+}
@@ -0,0 +1,91 @@
+// Command repl is an interactive calculator built on the calc package.
+//
+//	$ go run ./10_functions_and_packages/calc/repl
+//	> r = 3
+//	3
+//	> pi * r^2
+//	28.274333882308138
+//
+// Previous lines are kept in a history file (-history) between sessions and
+// can be listed with :history and re-run with !! or !N. -mode and -prec pick
+// the number mode at start-up; :mode switches it later:
+//
+//	$ go run ./10_functions_and_packages/calc/repl -mode rat
+//	> 0.1 + 0.2
+//	3/10 (≈ 0.3)
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-labs/10_functions_and_packages/calc"
+)
+
+// maxHistory bounds the number of lines kept in the history file.
+const maxHistory = 500
+
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".golabs_calc_history")
+}
+
+func main() {
+	history := flag.String("history", defaultHistoryFile(), "file to load and save history (empty to disable)")
+	mode := flag.String("mode", "float", "number mode: float, int, rat or bigfloat")
+	prec := flag.Uint("prec", calc.DefaultPrec, "bigfloat precision in bits")
+	flag.Parse()
+
+	m, err := calc.ParseMode(*mode)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "calc:", err)
+		os.Exit(2)
+	}
+	r := calc.NewREPL()
+	r.Env.SetMode(m, *prec)
+	if *history != "" {
+		r.History = loadHistory(*history)
+	}
+	fmt.Println("calc: type :help for help, :quit to exit")
+	loaded := len(r.History)
+
+	if err := r.Run(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "calc:", err)
+		os.Exit(1)
+	}
+	if *history != "" && len(r.History) > loaded {
+		if err := saveHistory(*history, r.History); err != nil {
+			fmt.Fprintln(os.Stderr, "calc: saving history:", err)
+		}
+	}
+}
+
+func loadHistory(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func saveHistory(path string, lines []string) error {
+	if len(lines) > maxHistory {
+		lines = lines[len(lines)-maxHistory:]
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+}
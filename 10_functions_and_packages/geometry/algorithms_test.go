@@ -0,0 +1,254 @@
+package geometry
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSegmentIntersection(t *testing.T) {
+	var tests = []struct {
+		name       string
+		s, u       Segment
+		intersects bool
+		at         Point
+	}{
+		{"crossing", Segment{Point{0, 0}, Point{2, 2}}, Segment{Point{0, 2}, Point{2, 0}}, true, Point{1, 1}},
+		{"touching endpoint", Segment{Point{0, 0}, Point{1, 1}}, Segment{Point{1, 1}, Point{2, 0}}, true, Point{1, 1}},
+		{"T junction", Segment{Point{0, 0}, Point{2, 0}}, Segment{Point{1, 0}, Point{1, 3}}, true, Point{1, 0}},
+		{"collinear overlap", Segment{Point{0, 0}, Point{2, 0}}, Segment{Point{1, 0}, Point{3, 0}}, true, Point{1, 0}},
+		{"collinear disjoint", Segment{Point{0, 0}, Point{1, 0}}, Segment{Point{2, 0}, Point{3, 0}}, false, Point{}},
+		{"parallel", Segment{Point{0, 0}, Point{2, 0}}, Segment{Point{0, 1}, Point{2, 1}}, false, Point{}},
+		{"apart", Segment{Point{0, 0}, Point{1, 1}}, Segment{Point{3, 0}, Point{2, 1}}, false, Point{}},
+	}
+
+	for _, test := range tests {
+		if got := test.s.Intersects(test.u); got != test.intersects {
+			t.Errorf("%s: Intersects returned %v. Expected %v", test.name, got, test.intersects)
+		}
+		p, ok := test.s.Intersection(test.u)
+		if ok != test.intersects || (ok && p != test.at) {
+			t.Errorf("%s: Intersection returned %v, %v. Expected %v, %v", test.name, p, ok, test.at, test.intersects)
+		}
+	}
+}
+
+// lShape is a concave polygon:
+//
+//	+--+
+//	|  |
+//	|  +--+
+//	|     |
+//	+-----+
+var lShape = Polygon{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}
+
+func TestPolygonContains(t *testing.T) {
+	var tests = []struct {
+		p        Point
+		expected bool
+	}{
+		{Point{0.5, 0.5}, true},
+		{Point{0.5, 1.5}, true},
+		{Point{1.5, 1.5}, false}, // in the notch
+		{Point{2, 0.5}, true},    // on an edge
+		{Point{0, 0}, true},      // on a vertex
+		{Point{-1, 1}, false},
+		{Point{3, 1}, false},
+	}
+
+	for _, test := range tests {
+		if got := lShape.Contains(test.p); got != test.expected {
+			t.Errorf("Contains(%v) returned %v. Expected %v", test.p, got, test.expected)
+		}
+	}
+}
+
+func TestPolygonIntersects(t *testing.T) {
+	square := func(x, y, size float64) Polygon {
+		return Polygon(Rectangle{Origin: Point{x, y}, Width: size, Height: size}.Vertices())
+	}
+	var tests = []struct {
+		name     string
+		q        Polygon
+		expected bool
+	}{
+		{"overlapping corner", square(-0.5, -0.5, 1), true},
+		{"inside", square(0.25, 0.25, 0.5), true},
+		{"containing", square(-1, -1, 5), true},
+		{"in the notch", square(1.25, 1.25, 0.5), false},
+		{"far away", square(5, 5, 1), false},
+	}
+
+	for _, test := range tests {
+		if got := lShape.Intersects(test.q); got != test.expected {
+			t.Errorf("%s: Intersects returned %v. Expected %v", test.name, got, test.expected)
+		}
+	}
+	if !lShape.IntersectsSegment(Segment{Point{-1, 0.5}, Point{3, 0.5}}) {
+		t.Error("Expected a segment across the L to intersect it")
+	}
+}
+
+func TestClipConvex(t *testing.T) {
+	a := Polygon(Rectangle{Width: 2, Height: 2}.Vertices())
+	b := Polygon(Rectangle{Origin: Point{1, 1}, Width: 2, Height: 2}.Vertices())
+	if got := a.ClipConvex(b).Area(); !almostEqual(got, 1) {
+		t.Errorf("ClipConvex area returned %v. Expected 1", got)
+	}
+	if got := lShape.ClipConvex(a.Reversed()).Area(); !almostEqual(got, 3) {
+		t.Errorf("ClipConvex of the L returned area %v. Expected 3", got)
+	}
+	far := Polygon(Rectangle{Origin: Point{5, 5}, Width: 1, Height: 1}.Vertices())
+	if got := a.ClipConvex(far); len(got) != 0 {
+		t.Errorf("ClipConvex of disjoint squares returned %v. Expected empty", got)
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	points := []Point{{0, 0}, {1, 1}, {2, 2}, {2, 0}, {0, 2}, {1, 0}, {0.5, 1.5}, {2, 0}}
+	expected := Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	if got := ConvexHull(points); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ConvexHull returned %v. Expected %v", got, expected)
+	}
+	if got := ConvexHull([]Point{{1, 1}, {1, 1}}); len(got) != 1 {
+		t.Errorf("ConvexHull of duplicate points returned %v", got)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	var cloud []Point
+	for i := 0; i < 200; i++ {
+		cloud = append(cloud, Point{r.Float64() * 10, r.Float64() * 10})
+	}
+	hull := ConvexHull(cloud)
+	if !hull.IsConvex() || hull.SignedArea() <= 0 {
+		t.Errorf("ConvexHull of random points is not a counter-clockwise convex polygon: %v", hull)
+	}
+	for _, p := range cloud {
+		if !hull.Contains(p) {
+			t.Errorf("hull does not contain %v", p)
+		}
+	}
+	if lShape.IsConvex() {
+		t.Error("Expected the L shape not to be convex")
+	}
+}
+
+func TestRectangleOps(t *testing.T) {
+	a := Rectangle{Width: 2, Height: 2}
+	b := Rectangle{Origin: Point{1, 1}, Width: 2, Height: 2}
+	if got, ok := a.Intersect(b); !ok || got != (Rectangle{Origin: Point{1, 1}, Width: 1, Height: 1}) {
+		t.Errorf("Intersect returned %+v, %v", got, ok)
+	}
+	if got := a.Union(b); got != (Rectangle{Width: 3, Height: 3}) {
+		t.Errorf("Union returned %+v", got)
+	}
+	if got := a.ScaledAboutCenter(2); got != (Rectangle{Origin: Point{-1, -1}, Width: 4, Height: 4}) {
+		t.Errorf("ScaledAboutCenter returned %+v", got)
+	}
+	if _, ok := a.Intersect(Rectangle{Origin: Point{3, 3}, Width: 1, Height: 1}); ok {
+		t.Error("Expected disjoint rectangles not to intersect")
+	}
+}
+
+func TestIndex(t *testing.T) {
+	ix := NewIndex(1)
+	ix.Insert(1, Rectangle{Width: 1, Height: 1})
+	ix.Insert(2, Rectangle{Origin: Point{0.5, 0.5}, Width: 1, Height: 1})
+	ix.Insert(3, Circle{Center: Point{10, 10}, Radius: 1})
+	ix.Insert(4, lShape)
+
+	if got := ix.Query(Rectangle{Origin: Point{0.9, 0.9}, Width: 0.05, Height: 0.05}); !reflect.DeepEqual(got, []int{1, 2, 4}) {
+		t.Errorf("Query returned %v. Expected [1 2 4]", got)
+	}
+	if got := ix.QueryPoint(Point{10, 10}); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("QueryPoint returned %v. Expected [3]", got)
+	}
+	if got := ix.Nearby(Rectangle{Origin: Point{7, 7}, Width: 1, Height: 1}, 5); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("Nearby returned %v. Expected [3]", got)
+	}
+	if got := ix.Overlaps(); !reflect.DeepEqual(got, [][2]int{{1, 2}, {1, 4}, {2, 4}}) {
+		t.Errorf("Overlaps returned %v", got)
+	}
+
+	if !ix.Remove(2) || ix.Remove(2) {
+		t.Error("Expected Remove(2) to succeed once")
+	}
+	if got := ix.Overlaps(); !reflect.DeepEqual(got, [][2]int{{1, 4}}) {
+		t.Errorf("Overlaps after Remove returned %v", got)
+	}
+	ix.Insert(1, Rectangle{Origin: Point{20, 20}, Width: 1, Height: 1})
+	if ix.Len() != 3 || len(ix.Overlaps()) != 0 {
+		t.Errorf("after moving shape 1: Len %d, Overlaps %v", ix.Len(), ix.Overlaps())
+	}
+}
+
+func TestIndexBounds(t *testing.T) {
+	ix := NewIndex(1)
+	ix.Insert(1, Rectangle{Width: 1, Height: 1})
+	tests := []struct {
+		s        Shape
+		expected error
+	}{
+		{Rectangle{Width: math.Inf(1), Height: 1}, ErrNotFinite},
+		{Circle{Center: Point{math.NaN(), 0}, Radius: 1}, ErrNotFinite},
+		{Rectangle{Width: 1e9, Height: 1e9}, ErrTooLarge},
+		{Rectangle{Width: 255, Height: 255}, nil},
+	}
+	for _, test := range tests {
+		if err := ix.Insert(2, test.s); err != test.expected {
+			t.Errorf("Insert(%v) returned %v. Expected %v", test.s, err, test.expected)
+		}
+	}
+	if ix.Len() != 2 {
+		t.Errorf("Len returned %d. Expected 2", ix.Len())
+	}
+
+	// Queries far larger than the occupied cells scan the shapes instead.
+	queries := []Rectangle{
+		{Origin: Point{-5e8, -5e8}, Width: 1e9, Height: 1e9},
+		{Origin: Point{-1e300, -1e300}, Width: math.Inf(1), Height: math.Inf(1)},
+	}
+	for _, q := range queries {
+		if got := ix.Query(q); !reflect.DeepEqual(got, []int{1, 2}) {
+			t.Errorf("Query(%v) returned %v. Expected [1 2]", q, got)
+		}
+	}
+	if got := ix.Query(Rectangle{Origin: Point{300, 300}, Width: 1e9, Height: 1e9}); got != nil {
+		t.Errorf("Query beyond every shape returned %v", got)
+	}
+}
+
+// TestIndexQueryManyCells queries more than MaxCells cells of an index that
+// occupies even more, which must still find every shape.
+func TestIndexQueryManyCells(t *testing.T) {
+	ix := NewIndex(1)
+	for i := 0; i < 300; i++ {
+		for j := 0; j < 300; j++ {
+			ix.Insert(i*300+j, Rectangle{Origin: Point{float64(i), float64(j)}, Width: 0.5, Height: 0.5})
+		}
+	}
+	q := Rectangle{Origin: Point{0.75, 0.75}, Width: 299, Height: 299}
+	expected := 0
+	for id := 0; id < ix.Len(); id++ {
+		if s, _ := ix.Shape(id); s.Bounds().Intersects(q) {
+			expected++
+		}
+	}
+	if got := ix.Query(q); len(got) != expected {
+		t.Errorf("Query(%v) returned %d shapes. Expected %d", q, len(got), expected)
+	}
+}
+
+func BenchmarkIndexQuery(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	ix := NewIndex(2)
+	for i := 0; i < 10000; i++ {
+		ix.Insert(i, Rectangle{Origin: Point{r.Float64() * 1000, r.Float64() * 1000}, Width: 1, Height: 1})
+	}
+	q := Rectangle{Origin: Point{500, 500}, Width: 10, Height: 10}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ix.Query(q)
+	}
+}
@@ -0,0 +1,85 @@
+// Package geometry generalizes the Rectangle type from the functions and
+// packages lab and CalcArea from the testing lab into a Shape interface with
+// circle, triangle, polygon and rectangle implementations.
+//
+// Shapes are plain values; constructors validate their arguments the same
+// way CalcArea does, rejecting non-positive dimensions with an error.
+//
+// The package also provides computational geometry on top of these types:
+// segment and polygon intersection, point-in-polygon tests, convex hulls,
+// convex clipping and a grid-based spatial Index over bounding boxes.
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Validation errors. ErrRectangleSize has the same message as CalcArea's.
+var (
+	ErrRectangleSize  = errors.New("width and height must be positive")
+	ErrRadius         = errors.New("radius must be positive")
+	ErrTooFewVertices = errors.New("a polygon needs at least 3 vertices")
+	ErrDegenerate     = errors.New("vertices must enclose a positive area")
+	ErrNotFinite      = errors.New("coordinates must be finite")
+)
+
+// Shape is implemented by every shape in this package.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+	// Bounds returns the smallest axis-aligned rectangle containing the shape.
+	Bounds() Rectangle
+}
+
+// Point is a location in the plane.
+type Point struct {
+	X, Y float64
+}
+
+// Add returns p translated by q.
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns the vector from q to p.
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Dist returns the Euclidean distance between p and q.
+func (p Point) Dist(q Point) float64 {
+	return math.Hypot(p.X-q.X, p.Y-q.Y)
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%g, %g)", p.X, p.Y)
+}
+
+func (p Point) finite() bool {
+	return !math.IsInf(p.X, 0) && !math.IsNaN(p.X) && !math.IsInf(p.Y, 0) && !math.IsNaN(p.Y)
+}
+
+// Validate reports whether s has valid dimensions. Shapes built with the
+// New* constructors are always valid; literals such as Rectangle{Width: -1}
+// are not checked until they are validated.
+func Validate(s Shape) error {
+	if v, ok := s.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// Area returns the area of s after validating it, mirroring CalcArea.
+func Area(s Shape) (float64, error) {
+	if err := Validate(s); err != nil {
+		return 0, err
+	}
+	return s.Area(), nil
+}
+
+// CalcArea is the float64 counterpart of the testing lab's CalcArea.
+func CalcArea(w, h float64) (float64, error) {
+	return Area(Rectangle{Width: w, Height: h})
+}
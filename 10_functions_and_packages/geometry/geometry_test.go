@@ -0,0 +1,117 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+const epsilon = 1e-9
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < epsilon
+}
+
+// shapeTests is shared by the area, perimeter and bounds tests so that every
+// shape is checked against the same contract.
+var shapeTests = []struct {
+	name      string
+	shape     Shape
+	area      float64
+	perimeter float64
+	bounds    Rectangle
+}{
+	{"rectangle", Rectangle{Width: 3, Height: 4}, 12, 14, Rectangle{Width: 3, Height: 4}},
+	{"offset rectangle", Rectangle{Origin: Point{-1, 2}, Width: 2, Height: 5}, 10, 14, Rectangle{Origin: Point{-1, 2}, Width: 2, Height: 5}},
+	{"circle", Circle{Center: Point{1, 1}, Radius: 2}, 4 * math.Pi, 4 * math.Pi, Rectangle{Origin: Point{-1, -1}, Width: 4, Height: 4}},
+	{"right triangle", Triangle{Point{0, 0}, Point{3, 0}, Point{0, 4}}, 6, 12, Rectangle{Width: 3, Height: 4}},
+	{"square polygon", Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, 4, 8, Rectangle{Width: 2, Height: 2}},
+	{"clockwise L", Polygon{{0, 0}, {0, 2}, {1, 2}, {1, 1}, {2, 1}, {2, 0}}, 3, 8, Rectangle{Width: 2, Height: 2}},
+}
+
+func TestArea(t *testing.T) {
+	for _, test := range shapeTests {
+		if got := test.shape.Area(); !almostEqual(got, test.area) {
+			t.Errorf("%s: Area() returned %v. Expected %v", test.name, got, test.area)
+		}
+	}
+}
+
+func TestPerimeter(t *testing.T) {
+	for _, test := range shapeTests {
+		if got := test.shape.Perimeter(); !almostEqual(got, test.perimeter) {
+			t.Errorf("%s: Perimeter() returned %v. Expected %v", test.name, got, test.perimeter)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	for _, test := range shapeTests {
+		if got := test.shape.Bounds(); got != test.bounds {
+			t.Errorf("%s: Bounds() returned %+v. Expected %+v", test.name, got, test.bounds)
+		}
+	}
+}
+
+func TestValidShapes(t *testing.T) {
+	for _, test := range shapeTests {
+		if err := Validate(test.shape); err != nil {
+			t.Errorf("%s: Validate() returned %v", test.name, err)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		shape    Shape
+		expected error
+	}{
+		{"zero width", Rectangle{Width: 0, Height: 4}, ErrRectangleSize},
+		{"negative height", Rectangle{Width: 3, Height: -4}, ErrRectangleSize},
+		{"NaN width", Rectangle{Width: math.NaN(), Height: 4}, ErrRectangleSize},
+		{"infinite width", Rectangle{Width: math.Inf(1), Height: 4}, ErrNotFinite},
+		{"zero radius", Circle{Radius: 0}, ErrRadius},
+		{"collinear triangle", Triangle{Point{0, 0}, Point{1, 1}, Point{2, 2}}, ErrDegenerate},
+		{"two vertices", Polygon{{0, 0}, {1, 1}}, ErrTooFewVertices},
+		{"NaN vertex", Polygon{{0, 0}, {1, 0}, {math.NaN(), 1}}, ErrNotFinite},
+	}
+
+	for _, test := range tests {
+		if _, err := Area(test.shape); err != test.expected {
+			t.Errorf("%s: Area() returned %v. Expected %v", test.name, err, test.expected)
+		}
+	}
+}
+
+func TestCalcArea(t *testing.T) {
+	if area, err := CalcArea(3, 5); err != nil || area != 15 {
+		t.Errorf("CalcArea(3, 5) returned %v, %v. Expected 15", area, err)
+	}
+	_, err := CalcArea(-3, 6)
+	if err == nil || err.Error() != "width and height must be positive" {
+		t.Errorf("CalcArea(-3, 6) returned %v. Expected the CalcArea error message", err)
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	if _, err := NewRectangle(Point{}, 1, 0); err != ErrRectangleSize {
+		t.Errorf("NewRectangle returned %v. Expected ErrRectangleSize", err)
+	}
+	if _, err := NewCircle(Point{}, -1); err != ErrRadius {
+		t.Errorf("NewCircle returned %v. Expected ErrRadius", err)
+	}
+	if _, err := NewTriangle(Point{0, 0}, Point{1, 0}, Point{0, 1}); err != nil {
+		t.Errorf("NewTriangle returned %v", err)
+	}
+	if _, err := NewPolygon(Point{0, 0}, Point{1, 0}); err != ErrTooFewVertices {
+		t.Errorf("NewPolygon returned %v. Expected ErrTooFewVertices", err)
+	}
+}
+
+func TestScale(t *testing.T) {
+	r := Rectangle{Origin: Point{1, 1}, Width: 3, Height: 4}
+	r.Scale(2)
+	if r.Width != 6 || r.Height != 8 || r.Origin != (Point{1, 1}) || r.Area() != 48 {
+		t.Errorf("Scale(2) returned %+v", r)
+	}
+}
@@ -0,0 +1,67 @@
+package geometry
+
+import "sort"
+
+// ConvexHull returns the convex hull of points as a counter-clockwise polygon
+// starting at the lowest-leftmost point, using Andrew's monotone chain
+// algorithm. Collinear points on the hull boundary are omitted. Fewer than
+// three distinct points yield the distinct points themselves.
+func ConvexHull(points []Point) Polygon {
+	pts := append([]Point(nil), points...)
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i].X != pts[j].X {
+			return pts[i].X < pts[j].X
+		}
+		return pts[i].Y < pts[j].Y
+	})
+	uniq := pts[:0]
+	for i, p := range pts {
+		if i == 0 || p != pts[i-1] {
+			uniq = append(uniq, p)
+		}
+	}
+	pts = uniq
+	if len(pts) < 3 {
+		return Polygon(pts)
+	}
+
+	hull := make(Polygon, 0, 2*len(pts))
+	// Lower hull, left to right.
+	for _, p := range pts {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// Upper hull, right to left.
+	lower := len(hull) + 1
+	for i := len(pts) - 2; i >= 0; i-- {
+		p := pts[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// The last point repeats the first.
+	return hull[:len(hull)-1]
+}
+
+// IsConvex reports whether p is a convex polygon in either orientation.
+func (p Polygon) IsConvex() bool {
+	if len(p) < 3 {
+		return false
+	}
+	sign := 0
+	for i := range p {
+		c := cross(p[i], p[(i+1)%len(p)], p[(i+2)%len(p)])
+		switch {
+		case c > 0 && sign < 0, c < 0 && sign > 0:
+			return false
+		case c > 0:
+			sign = 1
+		case c < 0:
+			sign = -1
+		}
+	}
+	return sign != 0
+}
@@ -0,0 +1,216 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Center returns the midpoint of the rectangle.
+func (r Rectangle) Center() Point {
+	return Point{r.Origin.X + r.Width/2, r.Origin.Y + r.Height/2}
+}
+
+// ScaledAboutCenter returns a copy of r scaled by factor with Scale and then
+// moved so that it keeps the same center.
+func (r Rectangle) ScaledAboutCenter(factor float64) Rectangle {
+	c := r.Center()
+	r.Scale(factor)
+	r.Origin = Point{c.X - r.Width/2, c.Y - r.Height/2}
+	return r
+}
+
+type cell struct {
+	x, y int
+}
+
+// MaxCells is the largest number of grid cells a shape inserted into an
+// Index may cover. Larger shapes call for a larger cell size.
+const MaxCells = 1 << 16
+
+// ErrTooLarge is returned by Insert for a shape covering more than MaxCells
+// cells.
+var ErrTooLarge = errors.New("shape covers too many index cells; use a larger cell size")
+
+// Index is a uniform-grid spatial index over the bounding boxes of shapes. It
+// answers "which shapes may overlap this rectangle" without comparing against
+// every shape; callers refine the candidates with exact tests if needed.
+type Index struct {
+	size   float64
+	grid   map[cell][]int
+	shapes map[int]Shape
+	bounds map[int]Rectangle
+}
+
+// NewIndex returns an empty index with square cells of the given size. A
+// size close to the typical shape width works best.
+func NewIndex(cellSize float64) *Index {
+	if !(cellSize > 0) {
+		panic(fmt.Sprintf("geometry: invalid cell size %v", cellSize))
+	}
+	return &Index{
+		size:   cellSize,
+		grid:   map[cell][]int{},
+		shapes: map[int]Shape{},
+		bounds: map[int]Rectangle{},
+	}
+}
+
+// Len returns the number of shapes in the index.
+func (ix *Index) Len() int {
+	return len(ix.shapes)
+}
+
+// span returns the range of grid cells that r touches and how many cells
+// that is. The count is a float64 so that huge or infinite rectangles do not
+// overflow; their cell coordinates are only meaningful when count is small.
+func (ix *Index) span(r Rectangle) (lo, hi cell, count float64) {
+	max := r.Max()
+	x0, y0 := math.Floor(r.Origin.X/ix.size), math.Floor(r.Origin.Y/ix.size)
+	x1, y1 := math.Floor(max.X/ix.size), math.Floor(max.Y/ix.size)
+	count = (x1 - x0 + 1) * (y1 - y0 + 1)
+	if math.IsNaN(count) {
+		count = math.Inf(1)
+	}
+	if count > MaxCells {
+		return cell{}, cell{}, count
+	}
+	return cell{int(x0), int(y0)}, cell{int(x1), int(y1)}, count
+}
+
+// cells calls fn for every grid cell from lo to hi.
+func cells(lo, hi cell, fn func(cell)) {
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			fn(cell{x, y})
+		}
+	}
+}
+
+// Insert adds s under id, replacing any shape already stored under id. It
+// returns ErrNotFinite for a shape whose bounds are not finite and
+// ErrTooLarge for one covering more than MaxCells cells, leaving the index
+// unchanged.
+func (ix *Index) Insert(id int, s Shape) error {
+	b := s.Bounds()
+	max := b.Max()
+	for _, v := range []float64{b.Origin.X, b.Origin.Y, max.X, max.Y} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ErrNotFinite
+		}
+	}
+	lo, hi, count := ix.span(b)
+	if count > MaxCells {
+		return ErrTooLarge
+	}
+	ix.Remove(id)
+	ix.shapes[id] = s
+	ix.bounds[id] = b
+	cells(lo, hi, func(c cell) {
+		ix.grid[c] = append(ix.grid[c], id)
+	})
+	return nil
+}
+
+// Remove deletes the shape stored under id and reports whether it existed.
+func (ix *Index) Remove(id int) bool {
+	b, ok := ix.bounds[id]
+	if !ok {
+		return false
+	}
+	lo, hi, _ := ix.span(b)
+	cells(lo, hi, func(c cell) {
+		ids := ix.grid[c]
+		for i, v := range ids {
+			if v == id {
+				ids = append(ids[:i], ids[i+1:]...)
+				break
+			}
+		}
+		if len(ids) == 0 {
+			delete(ix.grid, c)
+		} else {
+			ix.grid[c] = ids
+		}
+	})
+	delete(ix.shapes, id)
+	delete(ix.bounds, id)
+	return true
+}
+
+// Shape returns the shape stored under id.
+func (ix *Index) Shape(id int) (Shape, bool) {
+	s, ok := ix.shapes[id]
+	return s, ok
+}
+
+// Query returns the ids, in ascending order, of shapes whose bounding boxes
+// intersect r. A rectangle covering more than MaxCells cells, or more cells
+// than the index occupies, is answered by checking every shape instead of
+// every cell.
+func (ix *Index) Query(r Rectangle) []int {
+	var ids []int
+	lo, hi, count := ix.span(r)
+	if count > MaxCells || count > float64(len(ix.grid)) {
+		for id, b := range ix.bounds {
+			if b.Intersects(r) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Ints(ids)
+		return ids
+	}
+	seen := map[int]bool{}
+	cells(lo, hi, func(c cell) {
+		for _, id := range ix.grid[c] {
+			if !seen[id] {
+				seen[id] = true
+				if ix.bounds[id].Intersects(r) {
+					ids = append(ids, id)
+				}
+			}
+		}
+	})
+	sort.Ints(ids)
+	return ids
+}
+
+// QueryPoint returns the ids of shapes whose bounding boxes contain p.
+func (ix *Index) QueryPoint(p Point) []int {
+	return ix.Query(Rectangle{Origin: p})
+}
+
+// Nearby returns the ids of shapes whose bounding boxes intersect r scaled by
+// factor about its center, e.g. factor 2 searches an area twice as wide.
+func (ix *Index) Nearby(r Rectangle, factor float64) []int {
+	return ix.Query(r.ScaledAboutCenter(factor))
+}
+
+// Overlaps returns every pair of ids, with the smaller id first and pairs in
+// ascending order, whose bounding boxes intersect.
+func (ix *Index) Overlaps() [][2]int {
+	seen := map[[2]int]bool{}
+	var pairs [][2]int
+	for _, ids := range ix.grid {
+		for i, a := range ids {
+			for _, b := range ids[i+1:] {
+				pair := [2]int{a, b}
+				if a > b {
+					pair = [2]int{b, a}
+				}
+				if !seen[pair] && ix.bounds[a].Intersects(ix.bounds[b]) {
+					seen[pair] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	return pairs
+}
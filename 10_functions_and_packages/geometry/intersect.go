@@ -0,0 +1,200 @@
+package geometry
+
+import "math"
+
+// Segment is the line segment between A and B.
+type Segment struct {
+	A, B Point
+}
+
+// Length returns the length of the segment.
+func (s Segment) Length() float64 {
+	return s.A.Dist(s.B)
+}
+
+// Bounds returns the bounding box of the segment, which may have zero width
+// or height.
+func (s Segment) Bounds() Rectangle {
+	return Polygon{s.A, s.B}.Bounds()
+}
+
+// cross returns the z component of the cross product (b-a) x (c-a): positive
+// if a, b, c turn counter-clockwise, negative if clockwise, zero if collinear.
+func cross(a, b, c Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// onSegment reports whether p, known to be collinear with s, lies within it.
+func onSegment(s Segment, p Point) bool {
+	return math.Min(s.A.X, s.B.X) <= p.X && p.X <= math.Max(s.A.X, s.B.X) &&
+		math.Min(s.A.Y, s.B.Y) <= p.Y && p.Y <= math.Max(s.A.Y, s.B.Y)
+}
+
+// Intersects reports whether s and t share at least one point, including
+// touching endpoints and collinear overlaps.
+func (s Segment) Intersects(t Segment) bool {
+	d1 := cross(t.A, t.B, s.A)
+	d2 := cross(t.A, t.B, s.B)
+	d3 := cross(s.A, s.B, t.A)
+	d4 := cross(s.A, s.B, t.B)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(t, s.A)) || (d2 == 0 && onSegment(t, s.B)) ||
+		(d3 == 0 && onSegment(s, t.A)) || (d4 == 0 && onSegment(s, t.B))
+}
+
+// Intersection returns the point where s and t cross. For collinear
+// overlapping segments it returns one endpoint of the overlap. ok is false
+// if the segments do not meet.
+func (s Segment) Intersection(t Segment) (p Point, ok bool) {
+	if !s.Intersects(t) {
+		return Point{}, false
+	}
+	r := s.B.Sub(s.A)
+	q := t.B.Sub(t.A)
+	denom := r.X*q.Y - r.Y*q.X
+	if denom == 0 {
+		// Collinear: return the first endpoint lying on the other segment.
+		for _, c := range []Point{t.A, t.B, s.A, s.B} {
+			if onSegment(s, c) && onSegment(t, c) {
+				return c, true
+			}
+		}
+		return Point{}, false
+	}
+	d := t.A.Sub(s.A)
+	u := (d.X*q.Y - d.Y*q.X) / denom
+	return Point{s.A.X + u*r.X, s.A.Y + u*r.Y}, true
+}
+
+// Edges returns the polygon's edges, closing the last vertex to the first.
+func (p Polygon) Edges() []Segment {
+	edges := make([]Segment, len(p))
+	for i, a := range p {
+		edges[i] = Segment{a, p[(i+1)%len(p)]}
+	}
+	return edges
+}
+
+// Contains reports whether pt lies inside p or on its boundary, using the
+// even-odd ray casting rule.
+func (p Polygon) Contains(pt Point) bool {
+	inside := false
+	for _, e := range p.Edges() {
+		if cross(e.A, e.B, pt) == 0 && onSegment(e, pt) {
+			return true
+		}
+		if (e.A.Y > pt.Y) != (e.B.Y > pt.Y) {
+			x := e.A.X + (pt.Y-e.A.Y)*(e.B.X-e.A.X)/(e.B.Y-e.A.Y)
+			if pt.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// IntersectsSegment reports whether s crosses or lies inside p.
+func (p Polygon) IntersectsSegment(s Segment) bool {
+	if p.Contains(s.A) {
+		return true
+	}
+	for _, e := range p.Edges() {
+		if e.Intersects(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersects reports whether the polygons overlap or touch: either an edge of
+// one crosses an edge of the other, or one lies entirely inside the other.
+func (p Polygon) Intersects(q Polygon) bool {
+	if len(p) == 0 || len(q) == 0 || !p.Bounds().Intersects(q.Bounds()) {
+		return false
+	}
+	for _, e := range p.Edges() {
+		for _, f := range q.Edges() {
+			if e.Intersects(f) {
+				return true
+			}
+		}
+	}
+	return p.Contains(q[0]) || q.Contains(p[0])
+}
+
+// ClipConvex returns the part of p inside the convex polygon clip, using the
+// Sutherland-Hodgman algorithm. The result is empty if they do not overlap.
+func (p Polygon) ClipConvex(clip Polygon) Polygon {
+	if clip.SignedArea() < 0 {
+		clip = clip.Reversed()
+	}
+	out := append(Polygon(nil), p...)
+	for _, edge := range clip.Edges() {
+		in := out
+		out = nil
+		for i, cur := range in {
+			prev := in[(i+len(in)-1)%len(in)]
+			curIn := cross(edge.A, edge.B, cur) >= 0
+			prevIn := cross(edge.A, edge.B, prev) >= 0
+			if curIn != prevIn {
+				out = append(out, lineIntersection(prev, cur, edge.A, edge.B))
+			}
+			if curIn {
+				out = append(out, cur)
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+	}
+	return out
+}
+
+// lineIntersection returns the intersection of the infinite lines through
+// a-b and c-d, which must not be parallel.
+func lineIntersection(a, b, c, d Point) Point {
+	r := b.Sub(a)
+	q := d.Sub(c)
+	t := ((c.X-a.X)*q.Y - (c.Y-a.Y)*q.X) / (r.X*q.Y - r.Y*q.X)
+	return Point{a.X + t*r.X, a.Y + t*r.Y}
+}
+
+// Reversed returns the vertices in the opposite order.
+func (p Polygon) Reversed() Polygon {
+	r := make(Polygon, len(p))
+	for i, v := range p {
+		r[len(p)-1-i] = v
+	}
+	return r
+}
+
+// Contains reports whether pt lies inside r or on its boundary.
+func (r Rectangle) Contains(pt Point) bool {
+	max := r.Max()
+	return r.Origin.X <= pt.X && pt.X <= max.X && r.Origin.Y <= pt.Y && pt.Y <= max.Y
+}
+
+// Intersects reports whether r and o overlap or touch.
+func (r Rectangle) Intersects(o Rectangle) bool {
+	rMax, oMax := r.Max(), o.Max()
+	return r.Origin.X <= oMax.X && o.Origin.X <= rMax.X &&
+		r.Origin.Y <= oMax.Y && o.Origin.Y <= rMax.Y
+}
+
+// Intersect returns the overlap of r and o. ok is false if they do not meet.
+func (r Rectangle) Intersect(o Rectangle) (overlap Rectangle, ok bool) {
+	if !r.Intersects(o) {
+		return Rectangle{}, false
+	}
+	rMax, oMax := r.Max(), o.Max()
+	min := Point{math.Max(r.Origin.X, o.Origin.X), math.Max(r.Origin.Y, o.Origin.Y)}
+	max := Point{math.Min(rMax.X, oMax.X), math.Min(rMax.Y, oMax.Y)}
+	return Rectangle{Origin: min, Width: max.X - min.X, Height: max.Y - min.Y}, true
+}
+
+// Union returns the smallest rectangle containing both r and o.
+func (r Rectangle) Union(o Rectangle) Rectangle {
+	return Polygon{r.Origin, r.Max(), o.Origin, o.Max()}.Bounds()
+}
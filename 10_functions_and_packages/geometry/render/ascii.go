@@ -0,0 +1,141 @@
+package render
+
+import (
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"go-labs/10_functions_and_packages/geometry"
+)
+
+// ASCIIOptions configure ASCII output.
+type ASCIIOptions struct {
+	Cols   int  // canvas width in characters; default 60
+	Border bool // draw a frame around the canvas
+}
+
+// fillChars are used, in turn, for items without a Style.Char.
+var fillChars = []rune("#*o+x%@=")
+
+// Canvas is a grid of runes that shapes are rasterized onto.
+type Canvas struct {
+	cols, rows int
+	cells      [][]rune
+}
+
+// NewCanvas returns a blank canvas.
+func NewCanvas(cols, rows int) *Canvas {
+	c := &Canvas{cols: cols, rows: rows, cells: make([][]rune, rows)}
+	for i := range c.cells {
+		c.cells[i] = []rune(strings.Repeat(" ", cols))
+	}
+	return c
+}
+
+// Set writes r at column x, row y; positions off the canvas are ignored.
+func (c *Canvas) Set(x, y int, r rune) {
+	if x >= 0 && x < c.cols && y >= 0 && y < c.rows {
+		c.cells[y][x] = r
+	}
+}
+
+// Text writes s starting at column x, row y.
+func (c *Canvas) Text(x, y int, s string) {
+	for i, r := range []rune(s) {
+		c.Set(x+i, y, r)
+	}
+}
+
+// String returns the canvas with trailing spaces removed from each line.
+func (c *Canvas) String() string {
+	var b strings.Builder
+	for _, row := range c.cells {
+		b.WriteString(strings.TrimRight(string(row), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// ASCII rasterizes the scene onto a canvas and writes it to w. A character
+// cell is filled when its center lies inside a shape. Cells are assumed to be
+// twice as tall as they are wide, as in most terminal fonts.
+func ASCII(w io.Writer, scene Scene, opts ASCIIOptions) error {
+	if opts.Cols <= 0 {
+		opts.Cols = 60
+	}
+	canvas := Rasterize(scene, opts.Cols)
+	out := canvas.String()
+	if opts.Border {
+		out = frame(out, canvas.cols)
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// Rasterize draws the scene onto a canvas cols characters wide.
+func Rasterize(scene Scene, cols int) *Canvas {
+	b := scene.Bounds()
+	if b.Width <= 0 || b.Height <= 0 {
+		return NewCanvas(cols, 0)
+	}
+	sx := float64(cols) / b.Width // columns per unit
+	sy := sx / 2                  // rows per unit
+	rows := int(math.Ceil(b.Height * sy))
+	canvas := NewCanvas(cols, rows)
+	max := b.Max()
+
+	toWorld := func(col, row int) geometry.Point {
+		return geometry.Point{
+			X: b.Origin.X + (float64(col)+0.5)/sx,
+			Y: max.Y - (float64(row)+0.5)/sy,
+		}
+	}
+	toCell := func(p geometry.Point) (int, int) {
+		return int((p.X - b.Origin.X) * sx), int((max.Y - p.Y) * sy)
+	}
+
+	for i, it := range scene {
+		ch := it.Style.Char
+		if ch == 0 {
+			ch = fillChars[i%len(fillChars)]
+		}
+		// Only visit the cells covered by the item's bounding box.
+		ib := it.Shape.Bounds()
+		c0, r0 := toCell(geometry.Point{X: ib.Origin.X, Y: ib.Max().Y})
+		c1, r1 := toCell(geometry.Point{X: ib.Max().X, Y: ib.Origin.Y})
+		for row := r0; row <= r1 && row < rows; row++ {
+			for col := c0; col <= c1 && col < cols; col++ {
+				if contains(it.Shape, toWorld(col, row)) {
+					canvas.Set(col, row, ch)
+				}
+			}
+		}
+	}
+	for _, it := range scene {
+		if it.Label == "" {
+			continue
+		}
+		col, row := toCell(it.Shape.Bounds().Center())
+		col -= utf8.RuneCountInString(it.Label) / 2
+		if row >= rows {
+			row = rows - 1
+		}
+		canvas.Text(col, row, it.Label)
+	}
+	return canvas
+}
+
+func frame(s string, cols int) string {
+	var b strings.Builder
+	edge := "+" + strings.Repeat("-", cols) + "+\n"
+	b.WriteString(edge)
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		if s == "" {
+			break
+		}
+		b.WriteString("|" + line + strings.Repeat(" ", cols-utf8.RuneCountInString(line)) + "|\n")
+	}
+	b.WriteString(edge)
+	return b.String()
+}
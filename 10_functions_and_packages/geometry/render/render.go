@@ -0,0 +1,70 @@
+// Package render draws scenes of geometry shapes as SVG documents or as
+// ASCII art for terminal output.
+//
+// Scenes use the geometry package's coordinates, with Y pointing up; both
+// renderers flip the Y axis so that the picture has the expected orientation.
+package render
+
+import (
+	"math"
+
+	"go-labs/10_functions_and_packages/geometry"
+)
+
+// Style controls how an item is drawn. Empty fields fall back to defaults.
+type Style struct {
+	Fill        string  // SVG fill color, e.g. "#9cf" or "none"
+	Stroke      string  // SVG stroke color
+	StrokeWidth float64 // SVG stroke width in pixels
+	Char        rune    // ASCII fill character
+}
+
+// Item is a shape with its style and an optional label drawn at its center.
+type Item struct {
+	Shape geometry.Shape
+	Style Style
+	Label string
+}
+
+// Scene is an ordered list of items; later items are drawn on top.
+type Scene []Item
+
+// Add appends a shape to the scene and returns the scene for chaining.
+func (s Scene) Add(shape geometry.Shape, style Style, label string) Scene {
+	return append(s, Item{Shape: shape, Style: style, Label: label})
+}
+
+// Bounds returns the union of the bounding boxes of all items.
+func (s Scene) Bounds() geometry.Rectangle {
+	if len(s) == 0 {
+		return geometry.Rectangle{}
+	}
+	b := s[0].Shape.Bounds()
+	for _, it := range s[1:] {
+		b = b.Union(it.Shape.Bounds())
+	}
+	return b
+}
+
+// contains reports whether p lies inside shape. Shapes without a containment
+// test of their own are approximated by their bounding box.
+func contains(shape geometry.Shape, p geometry.Point) bool {
+	switch s := shape.(type) {
+	case geometry.Rectangle:
+		return s.Contains(p)
+	case *geometry.Rectangle:
+		return s.Contains(p)
+	case geometry.Circle:
+		return s.Center.Dist(p) <= s.Radius
+	case geometry.Triangle:
+		return geometry.Polygon(s.Vertices()).Contains(p)
+	case geometry.Polygon:
+		return s.Contains(p)
+	}
+	return shape.Bounds().Contains(p)
+}
+
+// round keeps rendered coordinates stable across platforms.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-labs/10_functions_and_packages/geometry"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// scene reproduces the lab's rectangle before and after Scale(2), plus one of
+// each other shape.
+func scene() Scene {
+	rect := geometry.Rectangle{Width: 3, Height: 4}
+	scaled := rect
+	scaled.Scale(2)
+	scaled.Origin = geometry.Point{X: 4}
+
+	return Scene{}.
+		Add(rect, Style{}, "3x4").
+		Add(scaled, Style{Fill: "none", Stroke: "#c00", Char: '.'}, "scaled").
+		Add(geometry.Circle{Center: geometry.Point{X: 14, Y: 2}, Radius: 2}, Style{Fill: "#ffd966"}, "circle").
+		Add(geometry.Triangle{A: geometry.Point{X: 0, Y: 5}, B: geometry.Point{X: 3, Y: 5}, C: geometry.Point{X: 1.5, Y: 8}}, Style{Fill: "#b6d7a8"}, "").
+		Add(geometry.Polygon{{X: 12, Y: 5}, {X: 16, Y: 5}, {X: 16, Y: 8}, {X: 14, Y: 6.5}, {X: 12, Y: 8}}, Style{Fill: "#d5a6bd"}, "<poly>")
+}
+
+// golden compares got with testdata/name, rewriting the file with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match the rendered output (run go test -update if the change is intended)\ngot:\n%s\nwant:\n%s",
+			path, got, want)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SVG(&buf, scene(), SVGOptions{Scale: 20, Title: "Shapes & labels"}); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "scene.svg", buf.Bytes())
+}
+
+func TestASCII(t *testing.T) {
+	var tests = []struct {
+		name string
+		opts ASCIIOptions
+	}{
+		{"scene.txt", ASCIIOptions{Cols: 64}},
+		{"scene_border.txt", ASCIIOptions{Cols: 32, Border: true}},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := ASCII(&buf, scene(), test.opts); err != nil {
+			t.Fatal(err)
+		}
+		golden(t, test.name, buf.Bytes())
+	}
+}
+
+func TestEmptyScene(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ASCII(&buf, nil, ASCIIOptions{}); err != nil || buf.Len() != 0 {
+		t.Errorf("ASCII of an empty scene returned %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"go-labs/10_functions_and_packages/geometry"
+)
+
+// SVGOptions configure SVG output.
+type SVGOptions struct {
+	Scale  float64 // pixels per unit; default 40
+	Margin float64 // pixels around the scene; default 10
+	Title  string  // optional document title
+}
+
+var defaultStyle = Style{Fill: "#cfe2ff", Stroke: "#1f4e79", StrokeWidth: 1.5}
+
+// SVG writes the scene as a standalone SVG document.
+func SVG(w io.Writer, scene Scene, opts SVGOptions) error {
+	if opts.Scale <= 0 {
+		opts.Scale = 40
+	}
+	if opts.Margin <= 0 {
+		opts.Margin = 10
+	}
+	b := scene.Bounds()
+	max := b.Max()
+	width := b.Width*opts.Scale + 2*opts.Margin
+	height := b.Height*opts.Scale + 2*opts.Margin
+
+	// project maps scene coordinates to SVG pixels, flipping the Y axis.
+	project := func(p geometry.Point) (float64, float64) {
+		return round((p.X-b.Origin.X)*opts.Scale + opts.Margin),
+			round((max.Y-p.Y)*opts.Scale + opts.Margin)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		num(round(width)), num(round(height)), num(round(width)), num(round(height)))
+	if opts.Title != "" {
+		fmt.Fprintf(bw, "  <title>%s</title>\n", html.EscapeString(opts.Title))
+	}
+	for _, it := range scene {
+		st := it.Style
+		if st.Fill == "" {
+			st.Fill = defaultStyle.Fill
+		}
+		if st.Stroke == "" {
+			st.Stroke = defaultStyle.Stroke
+		}
+		if st.StrokeWidth <= 0 {
+			st.StrokeWidth = defaultStyle.StrokeWidth
+		}
+		attrs := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="%s"`,
+			html.EscapeString(st.Fill), html.EscapeString(st.Stroke), num(st.StrokeWidth))
+
+		switch s := it.Shape.(type) {
+		case geometry.Circle:
+			cx, cy := project(s.Center)
+			fmt.Fprintf(bw, `  <circle cx="%s" cy="%s" r="%s" %s/>`+"\n",
+				num(cx), num(cy), num(round(s.Radius*opts.Scale)), attrs)
+		case geometry.Rectangle:
+			x, y := project(geometry.Point{X: s.Origin.X, Y: s.Max().Y})
+			fmt.Fprintf(bw, `  <rect x="%s" y="%s" width="%s" height="%s" %s/>`+"\n",
+				num(x), num(y), num(round(s.Width*opts.Scale)), num(round(s.Height*opts.Scale)), attrs)
+		default:
+			fmt.Fprintf(bw, `  <polygon points="%s" %s/>`+"\n", points(vertices(s), project), attrs)
+		}
+		if it.Label != "" {
+			cx, cy := project(it.Shape.Bounds().Center())
+			fmt.Fprintf(bw, `  <text x="%s" y="%s" text-anchor="middle" dominant-baseline="middle" font-family="sans-serif" font-size="12">%s</text>`+"\n",
+				num(cx), num(cy), html.EscapeString(it.Label))
+		}
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// vertices returns the outline of polygonal shapes, falling back to the
+// corners of the bounding box.
+func vertices(shape geometry.Shape) []geometry.Point {
+	if v, ok := shape.(interface{ Vertices() []geometry.Point }); ok {
+		return v.Vertices()
+	}
+	if p, ok := shape.(geometry.Polygon); ok {
+		return p
+	}
+	return shape.Bounds().Vertices()
+}
+
+func points(vs []geometry.Point, project func(geometry.Point) (float64, float64)) string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		x, y := project(v)
+		parts[i] = num(x) + "," + num(y)
+	}
+	return strings.Join(parts, " ")
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
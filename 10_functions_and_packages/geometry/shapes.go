@@ -0,0 +1,208 @@
+package geometry
+
+import "math"
+
+// Rectangle is an axis-aligned rectangle whose lower-left corner is at Origin.
+// Width and Height match the fields of the lab's Rectangle.
+type Rectangle struct {
+	Origin Point
+	Width  float64
+	Height float64
+}
+
+// NewRectangle returns a validated rectangle.
+func NewRectangle(origin Point, width, height float64) (Rectangle, error) {
+	r := Rectangle{Origin: origin, Width: width, Height: height}
+	return r, r.Validate()
+}
+
+// Validate rejects non-positive or non-finite dimensions.
+func (r Rectangle) Validate() error {
+	if !r.Origin.finite() || math.IsInf(r.Width, 0) || math.IsInf(r.Height, 0) {
+		return ErrNotFinite
+	}
+	if !(r.Width > 0) || !(r.Height > 0) {
+		return ErrRectangleSize
+	}
+	return nil
+}
+
+// Area returns Width * Height.
+func (r Rectangle) Area() float64 {
+	return r.Width * r.Height
+}
+
+// Perimeter returns the length of the rectangle's boundary.
+func (r Rectangle) Perimeter() float64 {
+	return 2 * (r.Width + r.Height)
+}
+
+// Bounds returns the rectangle itself.
+func (r Rectangle) Bounds() Rectangle {
+	return r
+}
+
+// Scale multiplies the width and height by factor, keeping Origin fixed.
+func (r *Rectangle) Scale(factor float64) {
+	r.Width *= factor
+	r.Height *= factor
+}
+
+// Min returns the lower-left corner.
+func (r Rectangle) Min() Point {
+	return r.Origin
+}
+
+// Max returns the upper-right corner.
+func (r Rectangle) Max() Point {
+	return Point{r.Origin.X + r.Width, r.Origin.Y + r.Height}
+}
+
+// Vertices returns the corners counter-clockwise from Origin.
+func (r Rectangle) Vertices() []Point {
+	max := r.Max()
+	return []Point{r.Origin, {max.X, r.Origin.Y}, max, {r.Origin.X, max.Y}}
+}
+
+// Circle is a circle with the given center and radius.
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+// NewCircle returns a validated circle.
+func NewCircle(center Point, radius float64) (Circle, error) {
+	c := Circle{Center: center, Radius: radius}
+	return c, c.Validate()
+}
+
+// Validate rejects non-positive or non-finite radii.
+func (c Circle) Validate() error {
+	if !c.Center.finite() || math.IsInf(c.Radius, 0) {
+		return ErrNotFinite
+	}
+	if !(c.Radius > 0) {
+		return ErrRadius
+	}
+	return nil
+}
+
+// Area returns πr².
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+// Perimeter returns the circumference 2πr.
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+// Bounds returns the square enclosing the circle.
+func (c Circle) Bounds() Rectangle {
+	return Rectangle{
+		Origin: Point{c.Center.X - c.Radius, c.Center.Y - c.Radius},
+		Width:  2 * c.Radius,
+		Height: 2 * c.Radius,
+	}
+}
+
+// Triangle is a triangle with vertices A, B and C.
+type Triangle struct {
+	A, B, C Point
+}
+
+// NewTriangle returns a validated triangle.
+func NewTriangle(a, b, c Point) (Triangle, error) {
+	t := Triangle{a, b, c}
+	return t, t.Validate()
+}
+
+// Validate rejects collinear vertices.
+func (t Triangle) Validate() error {
+	return Polygon(t.Vertices()).Validate()
+}
+
+// Vertices returns A, B and C.
+func (t Triangle) Vertices() []Point {
+	return []Point{t.A, t.B, t.C}
+}
+
+// Area returns the area using the shoelace formula.
+func (t Triangle) Area() float64 {
+	return Polygon(t.Vertices()).Area()
+}
+
+// Perimeter returns the sum of the side lengths.
+func (t Triangle) Perimeter() float64 {
+	return Polygon(t.Vertices()).Perimeter()
+}
+
+// Bounds returns the bounding box of the vertices.
+func (t Triangle) Bounds() Rectangle {
+	return Polygon(t.Vertices()).Bounds()
+}
+
+// Polygon is a simple polygon given by its vertices in order. The last vertex
+// connects back to the first.
+type Polygon []Point
+
+// NewPolygon returns a validated polygon.
+func NewPolygon(vertices ...Point) (Polygon, error) {
+	p := Polygon(vertices)
+	return p, p.Validate()
+}
+
+// Validate rejects polygons with fewer than three vertices or zero area.
+func (p Polygon) Validate() error {
+	if len(p) < 3 {
+		return ErrTooFewVertices
+	}
+	for _, v := range p {
+		if !v.finite() {
+			return ErrNotFinite
+		}
+	}
+	if !(p.Area() > 0) {
+		return ErrDegenerate
+	}
+	return nil
+}
+
+// Area returns the area using the shoelace formula. It is positive whatever
+// the orientation of the vertices.
+func (p Polygon) Area() float64 {
+	return math.Abs(p.SignedArea())
+}
+
+// SignedArea returns the shoelace area, positive for counter-clockwise
+// vertices and negative for clockwise ones.
+func (p Polygon) SignedArea() float64 {
+	sum := 0.0
+	for i, a := range p {
+		b := p[(i+1)%len(p)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return sum / 2
+}
+
+// Perimeter returns the sum of the edge lengths.
+func (p Polygon) Perimeter() float64 {
+	sum := 0.0
+	for i, a := range p {
+		sum += a.Dist(p[(i+1)%len(p)])
+	}
+	return sum
+}
+
+// Bounds returns the bounding box of the vertices.
+func (p Polygon) Bounds() Rectangle {
+	if len(p) == 0 {
+		return Rectangle{}
+	}
+	min, max := p[0], p[0]
+	for _, v := range p[1:] {
+		min.X, min.Y = math.Min(min.X, v.X), math.Min(min.Y, v.Y)
+		max.X, max.Y = math.Max(max.X, v.X), math.Max(max.Y, v.Y)
+	}
+	return Rectangle{Origin: min, Width: max.X - min.X, Height: max.Y - min.Y}
+}
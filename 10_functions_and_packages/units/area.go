@@ -0,0 +1,61 @@
+package units
+
+import (
+	"fmt"
+
+	"go-labs/10_functions_and_packages/geometry"
+)
+
+// CalcArea is the typed counterpart of geometry.CalcArea: it multiplies two
+// lengths, which may be in different units, and returns the area in the
+// square of w's unit. Non-positive sides fail with the lab's message.
+func CalcArea(w, h Quantity) (Quantity, error) {
+	if w.Unit.Dim != Length || h.Unit.Dim != Length {
+		return Quantity{}, fmt.Errorf("%w: CalcArea needs two lengths, got %s and %s",
+			ErrIncompatible, w.Unit.Dim, h.Unit.Dim)
+	}
+	hv, err := h.Float(w.Unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	a, err := geometry.CalcArea(w.Value, hv)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return New(a, square(w.Unit)), nil
+}
+
+// RectangleArea returns the area of r, whose coordinates are measured in
+// the length unit u.
+func RectangleArea(r geometry.Rectangle, u Unit) (Quantity, error) {
+	return ShapeArea(r, u)
+}
+
+// ShapeArea returns the validated area of any shape whose coordinates are
+// measured in the length unit u.
+func ShapeArea(s geometry.Shape, u Unit) (Quantity, error) {
+	if u.Dim != Length {
+		return Quantity{}, fmt.Errorf("%w: shape coordinates must be a length, not %s", ErrIncompatible, u.Dim)
+	}
+	a, err := geometry.Area(s)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return New(a, square(u)), nil
+}
+
+// Perimeter returns the perimeter of a shape measured in the length unit u.
+func Perimeter(s geometry.Shape, u Unit) (Quantity, error) {
+	if u.Dim != Length {
+		return Quantity{}, fmt.Errorf("%w: shape coordinates must be a length, not %s", ErrIncompatible, u.Dim)
+	}
+	if err := geometry.Validate(s); err != nil {
+		return Quantity{}, err
+	}
+	return New(s.Perimeter(), u), nil
+}
+
+// square returns the area unit of the length unit u, such as ft² for ft.
+func square(u Unit) Unit {
+	return derive(Area, u.Factor*u.Factor, product(u.Symbol, u.Symbol))
+}
@@ -0,0 +1,377 @@
+// Package units attaches units to the bare float64s of the doubling and area
+// labs. A Quantity is a value with a Unit; units have a Dimension such as
+// length, mass, time, area or temperature, and arithmetic refuses to mix
+// incompatible dimensions:
+//
+//	w := units.New(12, units.Foot)
+//	h := units.New(3, units.Metre)
+//	area, _ := units.CalcArea(w, h)       // 118.11… ft²
+//	m2, _ := area.In(units.SquareMetre)    // 10.9728 m²
+//
+// Conversions go through SI base units: metre, kilogram, second and kelvin.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrIncompatible is returned when an operation mixes dimensions, such as
+// adding a length to a mass or converting seconds to feet.
+var ErrIncompatible = errors.New("incompatible dimensions")
+
+// ErrOffsetUnit is returned when a temperature on an offset scale (°C, °F)
+// is multiplied or divided; only kelvin is a ratio scale.
+var ErrOffsetUnit = errors.New("cannot multiply or divide a temperature on an offset scale")
+
+// Dimension is a product of powers of the base dimensions. Area is
+// Dimension{Length: 2}; speed is Dimension{Length: 1, Time: -1}.
+type Dimension struct {
+	Length, Mass, Time, Temperature int
+}
+
+// Base and common derived dimensions.
+var (
+	Dimensionless = Dimension{}
+	Length        = Dimension{Length: 1}
+	Mass          = Dimension{Mass: 1}
+	Time          = Dimension{Time: 1}
+	Temperature   = Dimension{Temperature: 1}
+	Area          = Dimension{Length: 2}
+	Volume        = Dimension{Length: 3}
+	Speed         = Dimension{Length: 1, Time: -1}
+)
+
+var dimensionNames = map[Dimension]string{
+	Dimensionless: "dimensionless",
+	Length:        "length",
+	Mass:          "mass",
+	Time:          "time",
+	Temperature:   "temperature",
+	Area:          "area",
+	Volume:        "volume",
+	Speed:         "speed",
+}
+
+func (d Dimension) String() string {
+	if s, ok := dimensionNames[d]; ok {
+		return s
+	}
+	var parts []string
+	for _, p := range []struct {
+		name string
+		exp  int
+	}{{"length", d.Length}, {"mass", d.Mass}, {"time", d.Time}, {"temperature", d.Temperature}} {
+		switch p.exp {
+		case 0:
+		case 1:
+			parts = append(parts, p.name)
+		default:
+			parts = append(parts, p.name+"^"+strconv.Itoa(p.exp))
+		}
+	}
+	return strings.Join(parts, "·")
+}
+
+func (d Dimension) mul(o Dimension) Dimension {
+	return Dimension{d.Length + o.Length, d.Mass + o.Mass, d.Time + o.Time, d.Temperature + o.Temperature}
+}
+
+func (d Dimension) inv() Dimension {
+	return Dimension{-d.Length, -d.Mass, -d.Time, -d.Temperature}
+}
+
+// Unit is a unit of measure. A value v in the unit is v*Factor + Offset in
+// SI base units; Offset is non-zero only for °C and °F.
+type Unit struct {
+	Name   string
+	Symbol string
+	Dim    Dimension
+	Factor float64
+	Offset float64
+}
+
+func (u Unit) String() string { return u.Symbol }
+
+// Predefined units. Imperial units use their exact international
+// definitions.
+var (
+	Metre      = register(Unit{"metre", "m", Length, 1, 0})
+	Kilometre  = register(Unit{"kilometre", "km", Length, 1000, 0})
+	Centimetre = register(Unit{"centimetre", "cm", Length, 0.01, 0})
+	Millimetre = register(Unit{"millimetre", "mm", Length, 0.001, 0})
+	Inch       = register(Unit{"inch", "in", Length, 0.0254, 0})
+	Foot       = register(Unit{"foot", "ft", Length, 0.3048, 0})
+	Yard       = register(Unit{"yard", "yd", Length, 0.9144, 0})
+	Mile       = register(Unit{"mile", "mi", Length, 1609.344, 0})
+
+	Kilogram = register(Unit{"kilogram", "kg", Mass, 1, 0})
+	Gram     = register(Unit{"gram", "g", Mass, 0.001, 0})
+	Tonne    = register(Unit{"tonne", "t", Mass, 1000, 0})
+	Ounce    = register(Unit{"ounce", "oz", Mass, 0.028349523125, 0})
+	Pound    = register(Unit{"pound", "lb", Mass, 0.45359237, 0})
+	Stone    = register(Unit{"stone", "st", Mass, 6.35029318, 0})
+
+	Second = register(Unit{"second", "s", Time, 1, 0})
+	Minute = register(Unit{"minute", "min", Time, 60, 0})
+	Hour   = register(Unit{"hour", "h", Time, 3600, 0})
+	Day    = register(Unit{"day", "d", Time, 86400, 0})
+
+	SquareMetre      = register(Unit{"square metre", "m²", Area, 1, 0})
+	SquareCentimetre = register(Unit{"square centimetre", "cm²", Area, 1e-4, 0})
+	SquareKilometre  = register(Unit{"square kilometre", "km²", Area, 1e6, 0})
+	Hectare          = register(Unit{"hectare", "ha", Area, 1e4, 0})
+	SquareInch       = register(Unit{"square inch", "in²", Area, 0.00064516, 0})
+	SquareFoot       = register(Unit{"square foot", "ft²", Area, 0.09290304, 0})
+	SquareYard       = register(Unit{"square yard", "yd²", Area, 0.83612736, 0})
+	Acre             = register(Unit{"acre", "ac", Area, 4046.8564224, 0})
+	SquareMile       = register(Unit{"square mile", "mi²", Area, 2589988.110336, 0})
+
+	Kelvin     = register(Unit{"kelvin", "K", Temperature, 1, 0})
+	Celsius    = register(Unit{"degree Celsius", "°C", Temperature, 1, 273.15})
+	Fahrenheit = register(Unit{"degree Fahrenheit", "°F", Temperature, 5.0 / 9, 273.15 - 32*5.0/9})
+
+	// One is the unit of plain numbers.
+	One = Unit{"one", "", Dimensionless, 1, 0}
+)
+
+var registry = map[string]Unit{}
+
+// register adds u to the registry used by Lookup and returns it.
+func register(u Unit) Unit {
+	registry[u.Symbol] = u
+	registry[u.Name] = u
+	return u
+}
+
+// aliases are extra spellings accepted by Lookup.
+var aliases = map[string]string{
+	"meter": "metre", "meters": "metre", "metres": "metre",
+	"kilometer": "kilometre", "centimeter": "centimetre", "millimeter": "millimetre",
+	"inches": "inch", "feet": "foot", "yards": "yard", "miles": "mile",
+	"grams": "gram", "kilograms": "kilogram", "tonnes": "tonne", "pounds": "pound", "lbs": "pound",
+	"ounces": "ounce", "seconds": "second", "sec": "second", "minutes": "minute", "hours": "hour", "hr": "hour",
+	"days": "day", "m2": "m²", "cm2": "cm²", "km2": "km²", "in2": "in²", "ft2": "ft²", "yd2": "yd²", "mi2": "mi²",
+	"sqft": "ft²", "acres": "acre", "hectares": "hectare",
+	"C": "°C", "F": "°F", "degC": "°C", "degF": "°F", "celsius": "degree Celsius", "fahrenheit": "degree Fahrenheit",
+}
+
+// Lookup finds a predefined unit by symbol, name or common alias, such as
+// "ft", "foot", "feet" or "sqft".
+func Lookup(s string) (Unit, bool) {
+	if u, ok := registry[s]; ok {
+		return u, true
+	}
+	if a, ok := aliases[s]; ok {
+		return registry[a], true
+	}
+	if a, ok := aliases[strings.ToLower(s)]; ok {
+		return registry[a], true
+	}
+	u, ok := registry[strings.ToLower(s)]
+	return u, ok
+}
+
+// Units returns the predefined units of dimension d, smallest first.
+func Units(d Dimension) []Unit {
+	seen := map[string]bool{}
+	var us []Unit
+	for _, u := range registry {
+		if u.Dim == d && !seen[u.Symbol] {
+			seen[u.Symbol] = true
+			us = append(us, u)
+		}
+	}
+	sort.Slice(us, func(i, j int) bool {
+		if us[i].Factor != us[j].Factor {
+			return us[i].Factor < us[j].Factor
+		}
+		return us[i].Symbol < us[j].Symbol
+	})
+	return us
+}
+
+// Quantity is a value measured in a unit.
+type Quantity struct {
+	Value float64
+	Unit  Unit
+}
+
+// New returns the quantity v u.
+func New(v float64, u Unit) Quantity {
+	return Quantity{Value: v, Unit: u}
+}
+
+// Parse parses a quantity such as "3.5 ft", "20°C" or "12 m2".
+func Parse(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !strings.ContainsRune("0123456789.+-eE", r)
+	})
+	// An "e" followed by a non-digit starts a unit, not an exponent.
+	for i > 0 && (s[i-1] == 'e' || s[i-1] == 'E') {
+		i--
+	}
+	if i < 0 {
+		i = len(s)
+	}
+	v, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return Quantity{}, fmt.Errorf("units: invalid quantity %q", s)
+	}
+	sym := strings.TrimSpace(s[i:])
+	if sym == "" {
+		return New(v, One), nil
+	}
+	u, ok := Lookup(sym)
+	if !ok {
+		return Quantity{}, fmt.Errorf("units: unknown unit %q", sym)
+	}
+	return New(v, u), nil
+}
+
+// SI returns the value in SI base units.
+func (q Quantity) SI() float64 {
+	return q.Value*q.Unit.Factor + q.Unit.Offset
+}
+
+// Dim returns the dimension of the quantity.
+func (q Quantity) Dim() Dimension {
+	return q.Unit.Dim
+}
+
+// In converts q to unit u.
+func (q Quantity) In(u Unit) (Quantity, error) {
+	if q.Unit.Dim != u.Dim {
+		return Quantity{}, fmt.Errorf("%w: cannot convert %s (%s) to %s (%s)",
+			ErrIncompatible, q.Unit, q.Unit.Dim, u, u.Dim)
+	}
+	return New((q.SI()-u.Offset)/u.Factor, u), nil
+}
+
+// Float returns the value of q in u, or an error if the dimensions differ.
+func (q Quantity) Float(u Unit) (float64, error) {
+	c, err := q.In(u)
+	return c.Value, err
+}
+
+// interval returns the value of o in q's unit, treating temperatures as
+// differences so that 20 °C + 10 °C is 30 °C.
+func (q Quantity) interval(op string, o Quantity) (float64, error) {
+	if q.Unit.Dim != o.Unit.Dim {
+		return 0, fmt.Errorf("%w: cannot %s %s and %s", ErrIncompatible, op, q.Unit.Dim, o.Unit.Dim)
+	}
+	return o.Value * o.Unit.Factor / q.Unit.Factor, nil
+}
+
+// Add returns q + o in q's unit. Both must have the same dimension.
+func (q Quantity) Add(o Quantity) (Quantity, error) {
+	v, err := q.interval("add", o)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return New(q.Value+v, q.Unit), nil
+}
+
+// Sub returns q - o in q's unit. Both must have the same dimension.
+func (q Quantity) Sub(o Quantity) (Quantity, error) {
+	v, err := q.interval("subtract", o)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return New(q.Value-v, q.Unit), nil
+}
+
+// Scale multiplies q by a plain number; Scale(2) is the typed version of the
+// doubling lab.
+func (q Quantity) Scale(k float64) Quantity {
+	return New(q.Value*k, q.Unit)
+}
+
+// Mul returns q * o. The result has the combined dimension, so metres times
+// metres is an area; a matching predefined unit is used when there is one.
+func (q Quantity) Mul(o Quantity) (Quantity, error) {
+	if q.Unit.Offset != 0 || o.Unit.Offset != 0 {
+		return Quantity{}, ErrOffsetUnit
+	}
+	u := derive(q.Unit.Dim.mul(o.Unit.Dim), q.Unit.Factor*o.Unit.Factor, product(q.Unit.Symbol, o.Unit.Symbol))
+	return New(q.Value*o.Value, u), nil
+}
+
+// Div returns q / o, for example a length divided by a time is a speed.
+func (q Quantity) Div(o Quantity) (Quantity, error) {
+	if q.Unit.Offset != 0 || o.Unit.Offset != 0 {
+		return Quantity{}, ErrOffsetUnit
+	}
+	if o.Value == 0 {
+		return Quantity{}, errors.New("cannot divide by zero")
+	}
+	sym := q.Unit.Symbol + "/" + o.Unit.Symbol
+	if q.Unit.Symbol == "" {
+		sym = "1/" + o.Unit.Symbol
+	}
+	u := derive(q.Unit.Dim.mul(o.Unit.Dim.inv()), q.Unit.Factor/o.Unit.Factor, sym)
+	return New(q.Value/o.Value, u), nil
+}
+
+// Cmp compares q and o, which must have the same dimension, returning -1, 0
+// or +1.
+func (q Quantity) Cmp(o Quantity) (int, error) {
+	if q.Unit.Dim != o.Unit.Dim {
+		return 0, fmt.Errorf("%w: cannot compare %s and %s", ErrIncompatible, q.Unit.Dim, o.Unit.Dim)
+	}
+	a, b := q.SI(), o.SI()
+	switch {
+	case a < b:
+		return -1, nil
+	case a > b:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (q Quantity) String() string {
+	v := strconv.FormatFloat(q.Value, 'g', -1, 64)
+	switch {
+	case q.Unit.Symbol == "":
+		return v
+	case strings.HasPrefix(q.Unit.Symbol, "°"):
+		return v + q.Unit.Symbol
+	}
+	return v + " " + q.Unit.Symbol
+}
+
+// derive returns the predefined unit with dimension d and the given factor,
+// or a new unit named sym.
+func derive(d Dimension, factor float64, sym string) Unit {
+	if d == Dimensionless && factor == 1 {
+		return One
+	}
+	for _, u := range Units(d) {
+		if u.Offset == 0 && math.Abs(u.Factor-factor) <= 1e-12*factor {
+			return u
+		}
+	}
+	return Unit{Name: sym, Symbol: sym, Dim: d, Factor: factor}
+}
+
+// product names the product of two unit symbols, writing m·m as m².
+func product(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	case a == b:
+		return a + "²"
+	case a+"²" == b:
+		return a + "³"
+	case a == b+"²":
+		return b + "³"
+	}
+	return a + "·" + b
+}
@@ -0,0 +1,162 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"go-labs/10_functions_and_packages/geometry"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestConvert(t *testing.T) {
+	var tests = []struct {
+		from     Quantity
+		to       Unit
+		expected float64
+	}{
+		{New(1, Mile), Kilometre, 1.609344},
+		{New(12, Inch), Foot, 1},
+		{New(100, Centimetre), Metre, 1},
+		{New(1, Pound), Gram, 453.59237},
+		{New(14, Pound), Stone, 1},
+		{New(90, Minute), Hour, 1.5},
+		{New(1, Acre), SquareFoot, 43560},
+		{New(1, Hectare), SquareMetre, 10000},
+		{New(100, Celsius), Fahrenheit, 212},
+		{New(-40, Fahrenheit), Celsius, -40},
+		{New(0, Celsius), Kelvin, 273.15},
+		{New(32, Fahrenheit), Kelvin, 273.15},
+	}
+
+	for _, test := range tests {
+		got, err := test.from.In(test.to)
+		if err != nil {
+			t.Errorf("%v.In(%v) returned an error: %v", test.from, test.to, err)
+		} else if !near(got.Value, test.expected) {
+			t.Errorf("%v.In(%v) returned %v. Expected %v", test.from, test.to, got.Value, test.expected)
+		}
+	}
+}
+
+func TestIncompatible(t *testing.T) {
+	if _, err := New(1, Second).In(Foot); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("converting seconds to feet returned %v. Expected ErrIncompatible", err)
+	}
+	if _, err := New(1, Metre).Add(New(1, Kilogram)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("adding metres and kilograms returned %v. Expected ErrIncompatible", err)
+	}
+	if _, err := New(1, Hour).Cmp(New(1, Kelvin)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("comparing hours and kelvin returned %v. Expected ErrIncompatible", err)
+	}
+	if _, err := New(20, Celsius).Mul(New(2, Metre)); !errors.Is(err, ErrOffsetUnit) {
+		t.Errorf("multiplying °C returned %v. Expected ErrOffsetUnit", err)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	sum, err := New(1, Metre).Add(New(50, Centimetre))
+	if err != nil || sum.String() != "1.5 m" {
+		t.Errorf("1 m + 50 cm returned %v, %v. Expected 1.5 m", sum, err)
+	}
+	diff, err := New(30, Celsius).Sub(New(18, Fahrenheit))
+	if err != nil || !near(diff.Value, 20) || diff.Unit != Celsius {
+		t.Errorf("30°C - 18°F returned %v, %v. Expected 20°C", diff, err)
+	}
+	if d := New(2.5, Kilogram).Scale(2); d.String() != "5 kg" {
+		t.Errorf("doubling 2.5 kg returned %v. Expected 5 kg", d)
+	}
+
+	var tests = []struct {
+		got      func() (Quantity, error)
+		dim      Dimension
+		expected string
+	}{
+		{func() (Quantity, error) { return New(3, Metre).Mul(New(4, Metre)) }, Area, "12 m²"},
+		{func() (Quantity, error) { return New(3, Foot).Mul(New(2, Foot)) }, Area, "6 ft²"},
+		{func() (Quantity, error) { return New(2, Metre).Mul(New(3, SquareMetre)) }, Volume, "6 m³"},
+		{func() (Quantity, error) { return New(100, Kilometre).Div(New(2, Hour)) }, Speed, "50 km/h"},
+		{func() (Quantity, error) { return New(6, SquareMetre).Div(New(2, Metre)) }, Length, "3 m"},
+		{func() (Quantity, error) { return New(6, Metre).Div(New(3, Metre)) }, Dimensionless, "2"},
+		{func() (Quantity, error) { return New(2, Kilogram).Mul(New(3, Second)) }, Dimension{Mass: 1, Time: 1}, "6 kg·s"},
+	}
+	for _, test := range tests {
+		q, err := test.got()
+		if err != nil {
+			t.Errorf("%s: returned an error: %v", test.expected, err)
+		} else if q.String() != test.expected || q.Dim() != test.dim {
+			t.Errorf("returned %v (%v). Expected %s (%v)", q, q.Dim(), test.expected, test.dim)
+		}
+	}
+
+	speed, _ := New(100, Kilometre).Div(New(2, Hour))
+	if v, err := speed.Float(Unit{Symbol: "m/s", Dim: Speed, Factor: 1}); err != nil || !near(v, 50/3.6) {
+		t.Errorf("50 km/h in m/s is %v, %v. Expected %v", v, err, 50/3.6)
+	}
+}
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"3.5 ft", "3.5 ft"},
+		{"20°C", "20°C"},
+		{"12 m2", "12 m²"},
+		{"1e3 metres", "1000 m"},
+		{"2em", ""},
+		{"5 feet", "5 ft"},
+		{"-3.5F", "-3.5°F"},
+		{"42", "42"},
+	}
+	for _, test := range tests {
+		q, err := Parse(test.input)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("Parse(%q) returned %v. Expected an error", test.input, q)
+			}
+			continue
+		}
+		if err != nil || q.String() != test.expected {
+			t.Errorf("Parse(%q) returned %v, %v. Expected %s", test.input, q, err, test.expected)
+		}
+	}
+}
+
+func TestCalcArea(t *testing.T) {
+	area, err := CalcArea(New(3, Metre), New(400, Centimetre))
+	if err != nil || area.String() != "12 m²" {
+		t.Errorf("CalcArea(3 m, 400 cm) returned %v, %v. Expected 12 m²", area, err)
+	}
+	area, err = CalcArea(New(12, Foot), New(3, Metre))
+	if m2, _ := area.Float(SquareMetre); err != nil || area.Unit != SquareFoot || !near(m2, 10.9728) {
+		t.Errorf("CalcArea(12 ft, 3 m) returned %v, %v. Expected 10.9728 m² in ft²", area, err)
+	}
+	if _, err := CalcArea(New(-3, Metre), New(6, Metre)); err == nil || err.Error() != "width and height must be positive" {
+		t.Errorf("CalcArea(-3 m, 6 m) returned %v. Expected the CalcArea error message", err)
+	}
+	if _, err := CalcArea(New(3, Metre), New(6, Second)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("CalcArea(3 m, 6 s) returned %v. Expected ErrIncompatible", err)
+	}
+}
+
+func TestShapeArea(t *testing.T) {
+	r := geometry.Rectangle{Width: 3, Height: 4}
+	area, err := RectangleArea(r, Yard)
+	if ft2, _ := area.Float(SquareFoot); err != nil || area.Unit != SquareYard || !near(ft2, 108) {
+		t.Errorf("RectangleArea(3x4 yd) returned %v, %v. Expected 12 yd² = 108 ft²", area, err)
+	}
+	c := geometry.Circle{Radius: 1}
+	if a, err := ShapeArea(c, Millimetre); err != nil || a.Unit.Symbol != "mm²" || !near(a.Value, math.Pi) {
+		t.Errorf("ShapeArea(unit circle, mm) returned %v, %v. Expected π mm²", a, err)
+	}
+	if p, err := Perimeter(r, Metre); err != nil || p.String() != "14 m" {
+		t.Errorf("Perimeter(3x4 m) returned %v, %v. Expected 14 m", p, err)
+	}
+	if _, err := ShapeArea(r, Kilogram); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("ShapeArea in kg returned %v. Expected ErrIncompatible", err)
+	}
+}
@@ -1,4 +1,6 @@
 // pointers_examples.go
+
+//golden:mask address
 package main
 
 import "fmt"
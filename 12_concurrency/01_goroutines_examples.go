@@ -1,4 +1,8 @@
 // goroutines_examples.go
+
+//golden:seed 2
+//golden:unordered (?m)(^\[(main|simpleGoroutine)\] .*\n)+
+//golden:mask (?m)^Worker \d processed job \d in \d+ms$
 package main
 
 import (
@@ -6,6 +10,8 @@ import (
 	"math/rand/v2"
 	"sync"
 	"time"
+
+	"go-labs/util"
 )
 
 // worker simulates doing some work, then sends a result on the channel.
@@ -121,8 +127,11 @@ func main() {
 
 	timeoutChan := make(chan string)
 
+	// The delay comes from util.Rand, which GOLABS_SEED seeds, so that a
+	// golden test sees the same outcome on every run.
+	rng := util.Rand()
 	go func() {
-		delayMs := rand.IntN(800)
+		delayMs := rng.IntN(800)
 		delay := time.Duration(delayMs) * time.Millisecond // FIXED
 		time.Sleep(delay)
 		timeoutChan <- fmt.Sprintf("Finished slow operation in %v", delay)
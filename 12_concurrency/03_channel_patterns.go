@@ -1,4 +1,7 @@
 // channel_patterns.go
+//golden:unordered (?m)(^merged: .*\n)+
+//golden:mask (?m)^worker \d processed job \d$
+//golden:mask tick at \d{2}:\d{2}:\d{2}\.\d{3}
 package main
 
 import (
@@ -1,4 +1,7 @@
-// standard_library_examples.go
+// standard_library_example_1.go
+
+//golden:mask (?m)^Random .*$
+//golden:mask timestamp
 package main
 
 import (
@@ -1,9 +1,7 @@
 @echo off
-FOR /R %%G in (*.go) DO (
-    pushd %%~dpG
-    IF NOT EXIST "vendor" (
-        go build
-    )
-    popd
-)
-echo Build complete.
+REM Build every lab in parallel and report failures; see cmd\golabs.
+pushd "%~dp0"
+go run .\cmd\golabs build %*
+set STATUS=%ERRORLEVEL%
+popd
+exit /b %STATUS%
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"go-labs/internal/labs"
+)
+
+func runBuild(root string, args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	jobs := fs.Int("j", runtime.NumCPU(), "number of labs to build at once")
+	out := fs.String("o", "", "keep binaries in this directory instead of discarding them")
+	tags := fs.String("tags", "", "build tags passed to go build")
+	verbose := fs.Bool("v", false, "also list labs that built")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs build [-j N] [-o DIR] [-tags TAGS] [-v] [DIR...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	all, err := labs.Discover(root)
+	if err != nil {
+		return err
+	}
+	selected, err := selectLabs(root, all, fs.Args())
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		if *out, err = filepath.Abs(*out); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	start := time.Now()
+	fmt.Printf("building %d labs, %d at a time\n", len(selected), *jobs)
+	opts := labs.BuildOptions{Jobs: *jobs, OutDir: *out, Tags: *tags}
+	results := labs.Build(ctx, root, selected, opts, func(r labs.Result) {
+		if *verbose && r.OK() {
+			fmt.Printf("ok    %s (%s)\n", r.Lab.Path(), r.Duration.Round(time.Millisecond))
+		}
+	})
+
+	failed := 0
+	for _, r := range results {
+		if r.OK() {
+			continue
+		}
+		failed++
+		fmt.Printf("\nFAIL  %s\n", r.Lab.Path())
+		msg := r.Output
+		if msg == "" {
+			msg = r.Err.Error()
+		}
+		for _, line := range strings.Split(msg, "\n") {
+			fmt.Println("      " + line)
+		}
+	}
+	fmt.Printf("\n%d labs: %d ok, %d failed (%s)\n",
+		len(results), len(results)-failed, failed, time.Since(start).Round(100*time.Millisecond))
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+// selectLabs keeps the labs at or below any of the given paths, or
+// all labs if none are given.
+func selectLabs(root string, all []labs.Lab, dirs []string) ([]labs.Lab, error) {
+	if len(dirs) == 0 {
+		return all, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	var prefixes []string
+	for _, d := range dirs {
+		abs := d
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(cwd, d)
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("%s is outside the repository", d)
+		}
+		prefixes = append(prefixes, filepath.ToSlash(rel))
+	}
+	var out []labs.Lab
+	for _, lab := range all {
+		for _, p := range prefixes {
+			if p == "." || lab.Path() == p || strings.HasPrefix(lab.Path(), p+"/") {
+				out = append(out, lab)
+				break
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no labs under %s", strings.Join(dirs, ", "))
+	}
+	return out, nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-labs/internal/catalog"
+	"go-labs/internal/progress"
+)
+
+func runList(root string, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs list [CHAPTER]")
+		fmt.Fprintln(os.Stderr, "\nCHAPTER is a number (9), a directory (09_control_structures) or part of a title.")
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	c, err := catalog.Load(root)
+	if err != nil {
+		return err
+	}
+	entries := c.Entries
+	if fs.NArg() == 1 {
+		if entries = c.Chapter(fs.Arg(0)); len(entries) == 0 {
+			return fmt.Errorf("no chapter matches %q", fs.Arg(0))
+		}
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	chapter := ""
+	for _, e := range entries {
+		if e.Chapter != chapter {
+			if chapter != "" {
+				fmt.Fprintln(tw)
+			}
+			chapter = e.Chapter
+			fmt.Fprintln(tw, chapterHeading(e))
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", e.Path(), e.Title)
+	}
+	return tw.Flush()
+}
+
+func chapterHeading(e catalog.Entry) string {
+	if e.ChapterNum == 0 {
+		return e.ChapterTitle
+	}
+	return fmt.Sprintf("%d. %s", e.ChapterNum, e.ChapterTitle)
+}
+
+func runDescribe(root string, args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs describe NAME")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	e, err := find(root, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(e.Title)
+	fmt.Println(strings.Repeat("=", len([]rune(e.Title))))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "path:\t%s\n", e.Path())
+	fmt.Fprintf(tw, "chapter:\t%s\n", chapterHeading(e))
+	if e.Topic != "" {
+		fmt.Fprintf(tw, "topic:\t%s\n", e.Topic)
+	}
+	fmt.Fprintf(tw, "files:\t%s\n", strings.Join(e.Files, ", "))
+	if e.Notes != "" {
+		fmt.Fprintf(tw, "notes:\t%s\n", e.Notes)
+	}
+	fmt.Fprintf(tw, "run:\tgolabs run %s (or go run %s)\n", e.Path(), e.Target())
+	tw.Flush()
+	if e.Doc != "" {
+		fmt.Println()
+		fmt.Println(e.Doc)
+	}
+	return nil
+}
+
+func runRun(root string, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs run NAME [ARGS...]")
+		fmt.Fprintln(os.Stderr, "\nARGS are passed to the lab; its input and output are those of golabs.")
+	}
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	e, err := find(root, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", append([]string{"run", e.Target()}, fs.Args()[1:]...)...)
+	cmd.Dir = root
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	// An interrupt reaches the lab through the terminal; golabs only waits.
+	signal.Ignore(os.Interrupt)
+	err = cmd.Run()
+	var exit *exec.ExitError
+	if err == nil || errors.As(err, &exit) {
+		if perr := recordRun(e.Path(), err == nil); perr != nil {
+			fmt.Fprintln(os.Stderr, "golabs: recording progress:", perr)
+		}
+	}
+	if exit != nil {
+		os.Exit(exit.ExitCode())
+	}
+	return err
+}
+
+// recordRun adds a run of the lab at the given path to the progress file.
+func recordRun(lab string, ok bool) error {
+	path := progress.DefaultPath()
+	p, err := progress.Load(path)
+	if err != nil {
+		return err
+	}
+	p.RecordRun(lab, ok, time.Now())
+	return p.Save(path)
+}
+
+// find looks up a lab by name, also accepting a path relative to the
+// current directory, such as "." inside a lab.
+func find(root, name string) (catalog.Entry, error) {
+	c, err := catalog.Load(root)
+	if err != nil {
+		return catalog.Entry{}, err
+	}
+	return lookup(c, name)
+}
+
+// lookup is find within the labs of c.
+func lookup(c *catalog.Catalog, name string) (catalog.Entry, error) {
+	var amb *catalog.AmbiguousError
+	if abs, err := filepath.Abs(name); err == nil {
+		if rel, err := filepath.Rel(c.Root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			// Inside a directory of programs, "." names all of them.
+			if _, err := c.Find(rel); err == nil || errors.As(err, &amb) {
+				name = rel
+			}
+		}
+	}
+	e, err := c.Find(name)
+	if errors.As(err, &amb) {
+		return e, fmt.Errorf("%q matches several labs:\n  %s", name, strings.Join(amb.Candidates, "\n  "))
+	}
+	return e, err
+}
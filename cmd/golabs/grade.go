@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-labs/internal/catalog"
+	"go-labs/internal/grade"
+)
+
+func runGrade(root string, args []string) error {
+	fs := flag.NewFlagSet("grade", flag.ExitOnError)
+	verbose := fs.Bool("v", false, "list the cases that pass too")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs grade [-v] [EXERCISE...]")
+		fmt.Fprintln(os.Stderr, "\nChecks practice exercises against their hidden tests; without EXERCISE, checks them all.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	exercises, err := grade.Find(root)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		exercises, err = selectExercises(root, exercises, fs.Args())
+		if err != nil {
+			return err
+		}
+	}
+	if len(exercises) == 0 {
+		return errors.New("no exercises found")
+	}
+
+	failed := false
+	for i, ex := range exercises {
+		if i > 0 {
+			fmt.Println()
+		}
+		r, err := grade.Grade(context.Background(), root, ex)
+		if err != nil {
+			return err
+		}
+		if !printReport(r, *verbose) {
+			failed = true
+		}
+	}
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// selectExercises looks up each name among the exercises, as find does
+// among all labs.
+func selectExercises(root string, exercises []grade.Exercise, names []string) ([]grade.Exercise, error) {
+	c, err := catalog.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	byDir := make(map[string]grade.Exercise)
+	for _, ex := range exercises {
+		byDir[ex.Dir] = ex
+	}
+	var entries []catalog.Entry
+	for _, e := range c.Entries {
+		if _, ok := byDir[e.Dir]; ok {
+			entries = append(entries, e)
+		}
+	}
+	c.Entries = entries
+
+	var selected []grade.Exercise
+	for _, name := range names {
+		e, err := lookup(c, name)
+		if errors.Is(err, catalog.ErrNotFound) {
+			dirs := make([]string, len(entries))
+			for i, e := range entries {
+				dirs[i] = e.Dir
+			}
+			return nil, fmt.Errorf("no exercise matches %q; the exercises are:\n  %s", name, strings.Join(dirs, "\n  "))
+		}
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, byDir[e.Dir])
+	}
+	return selected, nil
+}
+
+// printReport prints the outcome of each test of r, with the hint of those
+// that fail, and reports whether they all pass.
+func printReport(r *grade.Report, verbose bool) bool {
+	ex := r.Exercise
+	if r.Build != "" {
+		fmt.Printf("%s: does not build\n\n%s\n", ex.Dir, indent(r.Build, "  "))
+		return false
+	}
+	passed, total := r.Count()
+	fmt.Printf("%s: %d of %d cases pass\n\n", ex.Dir, passed, total)
+	for _, res := range r.Results {
+		switch {
+		case res.Missing:
+			fmt.Printf("  MISSING  %s: declare func %s in %s\n", res.Func, res.Func, strings.Join(ex.Files, ", "))
+			continue
+		case len(res.Cases) == 0:
+			fmt.Printf("  FAIL     %s: did not run, as an earlier test crashed\n", res.Func)
+			continue
+		case res.Passed():
+			fmt.Printf("  ok       %s\n", res.Func)
+		default:
+			fmt.Printf("  FAIL     %s\n", res.Func)
+		}
+		for _, c := range res.Cases {
+			if c.Passed && !verbose {
+				continue
+			}
+			status := "ok  "
+			if !c.Passed {
+				status = "FAIL"
+			}
+			switch {
+			case c.Name == "" && c.Message == "":
+			case c.Name == "":
+				fmt.Printf("           %s\n", indent(c.Message, "           "))
+			default:
+				fmt.Printf("           %s %s\n", status, c.Name)
+				if c.Message != "" {
+					fmt.Printf("                %s\n", indent(c.Message, "                "))
+				}
+			}
+		}
+		if !res.Passed() && res.Hint != "" {
+			fmt.Printf("           Hint: %s\n", res.Hint)
+		}
+	}
+	return passed == total
+}
+
+// indent prefixes every line of s but the first with prefix.
+func indent(s, prefix string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
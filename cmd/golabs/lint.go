@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"go-labs/internal/lint"
+)
+
+func runLint(root string, args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	checks := fs.String("checks", "", "comma-separated checks to run (default: all)")
+	list := fs.Bool("list", false, "list the checks")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs lint [-checks LIST] [DIR...]")
+		fmt.Fprintln(os.Stderr, "       golabs lint -list")
+		fmt.Fprintln(os.Stderr, "\nWithout DIR, checks every lab and package outside cmd and internal.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *list {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, a := range lint.Analyzers {
+			fmt.Fprintf(w, "%s\t%s\n", a.Name, a.Doc)
+		}
+		return w.Flush()
+	}
+	analyzers := lint.Analyzers
+	if *checks != "" {
+		var err error
+		if analyzers, err = lint.Select(*checks); err != nil {
+			return err
+		}
+	}
+	var dirs []string
+	for _, d := range fs.Args() {
+		abs, err := filepath.Abs(d)
+		if err != nil {
+			return err
+		}
+		dirs = append(dirs, abs)
+	}
+
+	diagnostics, err := lint.Run(root, analyzers, dirs...)
+	if err != nil {
+		return err
+	}
+	for _, d := range diagnostics {
+		fmt.Println(d)
+	}
+	if len(diagnostics) > 0 {
+		if len(diagnostics) == 1 {
+			fmt.Fprintln(os.Stderr, "1 problem")
+		} else {
+			fmt.Fprintf(os.Stderr, "%d problems\n", len(diagnostics))
+		}
+		return errFailed
+	}
+	return nil
+}
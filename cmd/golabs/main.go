@@ -0,0 +1,137 @@
+// Command golabs is the course tool for this repository.
+//
+// Usage:
+//
+//	golabs build [-j N] [-o DIR] [-tags TAGS] [-v] [DIR...]
+//	golabs list [CHAPTER]
+//	golabs describe NAME
+//	golabs run NAME [ARGS...]
+//	golabs quiz [-n N] [-seed S] [-ordered] [-review] [QUIZ]
+//	golabs quiz -check [QUIZ]
+//	golabs progress [-v]
+//	golabs grade [-v] [EXERCISE...]
+//	golabs new [-title TITLE] [-exercise=false] [-n] CHAPTER NAME
+//	golabs lint [-checks LIST] [DIR...]
+//	golabs site [-o DIR]
+//
+// build compiles every lab (every directory with a main package), or only
+// those under the given directories, in parallel. It prints compiler errors
+// grouped by lab and exits with status 1 if any lab fails to build. It
+// replaces build_all.sh and build_all.bat, which now delegate to it.
+//
+// list prints the labs grouped by chapter with their titles, which are taken
+// from doc comments, Markdown notes or directory names. CHAPTER narrows the
+// list to one chapter, by number (9) or name (control).
+//
+// describe shows what is known about a lab, and run runs it with go run,
+// passing ARGS and connecting it to the terminal. NAME is a lab directory or
+// any unambiguous trailing part of one, with or without numeric prefixes:
+// "kvtool", "switch_case_construct" and "06_maps/kvstore/kvtool" all work.
+//
+// quiz lists the Markdown quizzes, or takes one: the questions are asked in
+// random order, each answer is checked as it is given, and the score is
+// printed at the end. QUIZ is a quiz file or part of its path or title,
+// such as "introduction". With -check, quiz validates the format of every
+// quiz, or of QUIZ, printing each problem as file:line: message.
+//
+// run and quiz record progress in ~/.golabs_progress.json, or the file named
+// by $GOLABS_PROGRESS: a lab is completed once it runs successfully, and
+// each answered question is scheduled for review with the SM-2 algorithm,
+// sooner when it was answered wrongly. quiz -review asks the questions that
+// are due, from every quiz or from QUIZ. progress shows, per chapter of
+// docs/topics.md, the labs completed and the questions learned; -v breaks
+// the chapters down by topic.
+//
+// grade checks the practice exercises, or the named ones, against their
+// hidden tests in testdata/grade_test.go. It lists the functions whose
+// cases fail, with what went wrong and a hint, and exits with status 1
+// unless every case passes. -v lists the passing cases too.
+//
+// new creates lab NAME in CHAPTER from templates, numbered after the
+// chapter's existing labs: a main package whose header names its file, a
+// test, README notes, a line in the chapter README, and a practice exercise
+// with hidden tests. -n prints the files instead of writing them.
+//
+// lint checks the labs, or the packages under DIR, for the repository's
+// conventions: files that declare nothing and labs with an empty main,
+// "// name.go" headers naming another file, func main outside package main,
+// errors discarded with _, and library packages without tests. It prints
+// each problem as file:line:col: message (check) and exits with status 1 if
+// there are any. -list describes the checks, and -checks runs only some.
+// A "//lint:ignore CHECK REASON" comment silences a check on its line and
+// the next.
+//
+// site renders the course as a static HTML site in DIR, by default _site in
+// the repository root: the README as the index, a page per Markdown note and
+// quiz, and a page per lab with its notes, highlighted sources and golden
+// output. Pages follow the chapters of docs/topics.md and link to each other
+// with relative URLs, so the site works offline, straight from the disk.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"go-labs/internal/labs"
+)
+
+// errFailed signals that a command has already reported its failures and
+// only the exit status remains.
+var errFailed = errors.New("failed")
+
+type command struct {
+	name    string
+	summary string
+	run     func(root string, args []string) error
+}
+
+var commands = []command{
+	{"build", "build every lab and report failures", runBuild},
+	{"list", "list the labs by chapter", runList},
+	{"describe", "show a lab's chapter, files and documentation", runDescribe},
+	{"run", "run a lab by name", runRun},
+	{"quiz", "take one of the Markdown quizzes", runQuiz},
+	{"progress", "show the labs and questions done per chapter", runProgress},
+	{"grade", "check practice exercises against their tests", runGrade},
+	{"new", "create a lab and its exercise from templates", runNew},
+	{"lint", "check the labs for the repository's conventions", runLint},
+	{"site", "render the course as a static HTML site", runSite},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: golabs <command> [flags] [args]\n\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun \"golabs <command> -h\" for a command's flags.")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	name, args := os.Args[1], os.Args[2:]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		root, err := labs.FindRoot(".")
+		if err == nil {
+			err = c.run(root, args)
+		}
+		switch {
+		case errors.Is(err, errFailed):
+			os.Exit(1)
+		case err != nil:
+			fmt.Fprintln(os.Stderr, "golabs:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if name != "-h" && name != "help" {
+		fmt.Fprintf(os.Stderr, "golabs: unknown command %q\n", name)
+	}
+	usage()
+}
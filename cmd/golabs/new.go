@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-labs/internal/scaffold"
+)
+
+func runNew(root string, args []string) error {
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	title := fs.String("title", "", "title of the lab (default: NAME, capitalized)")
+	exercise := fs.Bool("exercise", true, "also create a practice exercise for golabs grade")
+	dryRun := fs.Bool("n", false, "print the files that would be written, without writing them")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs new [-title TITLE] [-exercise=false] [-n] CHAPTER NAME")
+		fmt.Fprintln(os.Stderr, "\nCHAPTER is a chapter number, directory or part of one, or NN_name for a new chapter.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	plan, err := scaffold.New(root, scaffold.Options{
+		Chapter:  fs.Arg(0),
+		Name:     strings.Join(fs.Args()[1:], " "),
+		Title:    *title,
+		Exercise: *exercise,
+	})
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		for _, f := range plan.Files {
+			fmt.Printf("==> %s <==\n%s\n", f.Path, f.Content)
+		}
+		return nil
+	}
+	if err := plan.Write(root); err != nil {
+		return err
+	}
+	for _, f := range plan.Files {
+		if f.Update {
+			fmt.Println("updated", f.Path)
+		} else {
+			fmt.Println("created", f.Path)
+		}
+	}
+
+	fmt.Println("\nNext:")
+	fmt.Printf("  go run ./cmd/golabs run %s\n", plan.Dir)
+	fmt.Printf("  go test ./internal/golden -run 'TestLabs/%s$' -update   # record its output\n", plan.Dir)
+	if plan.ExerciseDir != "" {
+		fmt.Printf("  go run ./cmd/golabs grade %s\n", plan.ExerciseDir)
+	}
+	return nil
+}
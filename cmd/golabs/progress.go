@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"go-labs/internal/catalog"
+	"go-labs/internal/progress"
+	"go-labs/internal/quiz"
+)
+
+func runProgress(root string, args []string) error {
+	fs := flag.NewFlagSet("progress", flag.ExitOnError)
+	verbose := fs.Bool("v", false, "show each topic of the chapters")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs progress [-v]")
+		fmt.Fprintln(os.Stderr, "\nShows the labs completed and the quiz questions learned per chapter of docs/topics.md.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	p, err := progress.Load(progress.DefaultPath())
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filepath.Join(root, "docs", "topics.md"))
+	if err != nil {
+		return err
+	}
+	chapters, err := progress.ParseTopics(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	items, err := progressItems(root, p)
+	if err != nil {
+		return err
+	}
+	unplaced := progress.Cover(chapters, items)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CHAPTER\tLABS\tQUESTIONS\tDONE")
+	for _, ch := range chapters {
+		fmt.Fprintf(w, "%d. %s\t%s\t%s\t%s\n", ch.Number, ch.Title, count(ch.Labs), count(ch.Questions), percent(ch.Labs, ch.Questions))
+		if !*verbose {
+			continue
+		}
+		for _, t := range ch.Topics {
+			fmt.Fprintf(w, "    %s\t%s\t%s\t%s\n", t.Title, count(t.Labs), count(t.Questions), percent(t.Labs, t.Questions))
+		}
+	}
+	w.Flush()
+
+	if len(unplaced) > 0 {
+		var labs, questions progress.Count
+		for _, it := range unplaced {
+			if it.Question {
+				questions.Total++
+			} else {
+				labs.Total++
+			}
+		}
+		fmt.Printf("\nNot in docs/topics.md: %d labs, %d questions\n", labs.Total, questions.Total)
+	}
+	due := len(p.Due(time.Now()))
+	fmt.Printf("\nQuestions due for review: %d", due)
+	if next, ok := p.NextDue(); ok && due == 0 {
+		fmt.Printf(" (next on %s)", next.Format("Mon 2 Jan 2006"))
+	}
+	fmt.Println()
+	if due > 0 {
+		fmt.Println("Review them with: golabs quiz -review")
+	}
+	return nil
+}
+
+// progressItems lists every lab and quiz question with whether p records
+// it as done: a lab that has run successfully, or a question last answered
+// correctly.
+func progressItems(root string, p *progress.Progress) ([]progress.Item, error) {
+	c, err := catalog.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	var items []progress.Item
+	for _, e := range c.Entries {
+		items = append(items, progress.Item{Path: e.Path(), Done: p.Labs[e.Path()].Done()})
+	}
+	files, err := quiz.Find(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		q, err := quiz.ParseFile(filepath.Join(root, filepath.FromSlash(file)))
+		if err != nil {
+			// Broken quizzes are reported by golabs quiz -check.
+			continue
+		}
+		for _, question := range q.Questions {
+			id := progress.QuestionID(file, question.Text, question.Body)
+			items = append(items, progress.Item{Path: file, Question: true, Done: p.Questions[id].Learned()})
+		}
+	}
+	return items, nil
+}
+
+func count(c progress.Count) string {
+	if c.Total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", c.Done, c.Total)
+}
+
+func percent(labs, questions progress.Count) string {
+	if labs.Total+questions.Total == 0 {
+		return "-"
+	}
+	return strconv.Itoa(progress.Percent(labs, questions)) + "%"
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-labs/internal/progress"
+	"go-labs/internal/quiz"
+)
+
+func runQuiz(root string, args []string) error {
+	fs := flag.NewFlagSet("quiz", flag.ExitOnError)
+	n := fs.Int("n", 0, "ask at most this many questions")
+	seed := fs.Int64("seed", 0, "shuffle with this seed instead of a random one")
+	ordered := fs.Bool("ordered", false, "ask the questions in file order")
+	review := fs.Bool("review", false, "ask only the questions due for review, from every quiz or QUIZ")
+	check := fs.Bool("check", false, "validate the quizzes instead of taking one")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs quiz [-n N] [-seed S] [-ordered] [-review] [QUIZ]")
+		fmt.Fprintln(os.Stderr, "       golabs quiz -check [QUIZ]")
+		fmt.Fprintln(os.Stderr, "\nWithout QUIZ, lists or checks every quiz. QUIZ is a file or part of its path or title.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	files, err := quiz.Find(root)
+	if err != nil {
+		return err
+	}
+	var paths []string
+	switch {
+	case fs.NArg() == 1:
+		path, err := findQuiz(root, files, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if *check {
+			return checkQuizzes("", []string{path})
+		}
+		paths = []string{path}
+	case *check:
+		return checkQuizzes(root, files)
+	case !*review:
+		return listQuizzes(root, files)
+	default:
+		for _, f := range files {
+			paths = append(paths, filepath.Join(root, filepath.FromSlash(f)))
+		}
+	}
+
+	progressFile := progress.DefaultPath()
+	p, err := progress.Load(progressFile)
+	if err != nil {
+		return err
+	}
+	q, ids, err := loadQuestions(root, paths, p, *review)
+	if err != nil {
+		return err
+	}
+	if len(q.Questions) == 0 {
+		if !*review {
+			return fmt.Errorf("%s has no questions", paths[0])
+		}
+		fmt.Println("Nothing to review.")
+		if next, ok := p.NextDue(); ok {
+			fmt.Println("Next review:", next.Format("Mon 2 Jan 2006 15:04"))
+		}
+		return nil
+	}
+
+	var rng *rand.Rand
+	if !*ordered {
+		if *seed == 0 {
+			*seed = time.Now().UnixNano()
+		}
+		rng = rand.New(rand.NewSource(*seed))
+	}
+	s := quiz.NewSession(q, rng, *n)
+	s.OnAnswer = func(i int, correct bool) {
+		p.Answer(ids[i], correct, time.Now())
+	}
+	_, err = s.Run(os.Stdin, os.Stdout)
+	if saveErr := p.Save(progressFile); err == nil {
+		err = saveErr
+	}
+	return err
+}
+
+// loadQuestions reads quiz files into one quiz, returning the progress ID
+// of each question. With dueOnly, it keeps only the questions due for
+// review.
+func loadQuestions(root string, paths []string, p *progress.Progress, dueOnly bool) (*quiz.Quiz, []string, error) {
+	due := make(map[string]bool)
+	for _, id := range p.Due(time.Now()) {
+		due[id] = true
+	}
+	out := &quiz.Quiz{}
+	var ids []string
+	for _, path := range paths {
+		q, err := quiz.ParseFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		rel := quizRel(root, path)
+		for _, question := range q.Questions {
+			id := progress.QuestionID(rel, question.Text, question.Body)
+			if dueOnly && !due[id] {
+				continue
+			}
+			out.Questions = append(out.Questions, question)
+			ids = append(ids, id)
+		}
+		out.Title = q.Title
+	}
+	if dueOnly {
+		out.Title = "Review"
+	}
+	return out, ids, nil
+}
+
+// quizRel returns the path of a quiz file relative to root, which names it
+// in the progress file.
+func quizRel(root, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func listQuizzes(root string, files []string) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, f := range files {
+		q, err := quiz.ParseFile(filepath.Join(root, filepath.FromSlash(f)))
+		var errs quiz.Errors
+		switch {
+		case errors.As(err, &errs):
+			fmt.Fprintf(tw, "%s\t(%d problems; see golabs quiz -check)\n", f, len(errs))
+		case err != nil:
+			fmt.Fprintf(tw, "%s\t(%v)\n", f, err)
+		default:
+			fmt.Fprintf(tw, "%s\t%s\t%d questions\n", f, q.Title, len(q.Questions))
+		}
+	}
+	return tw.Flush()
+}
+
+// checkQuizzes validates quiz files, given relative to dir, and prints
+// their problems as "file:line: message".
+func checkQuizzes(dir string, files []string) error {
+	problems := 0
+	for _, f := range files {
+		data, err := os.Open(filepath.Join(dir, filepath.FromSlash(f)))
+		if err != nil {
+			return err
+		}
+		errs := quiz.Validate(data)
+		data.Close()
+		for _, e := range errs {
+			e.File = f
+			fmt.Println(e)
+		}
+		problems += len(errs)
+	}
+	if problems > 0 {
+		fmt.Printf("%d problems in %d quizzes\n", problems, len(files))
+		return errFailed
+	}
+	fmt.Printf("%d quizzes ok\n", len(files))
+	return nil
+}
+
+// findQuiz resolves name to a quiz file: a path, or part of the path or
+// title of exactly one quiz.
+func findQuiz(root string, files []string, name string) (string, error) {
+	if st, err := os.Stat(name); err == nil && !st.IsDir() {
+		return name, nil
+	}
+	lower := strings.ToLower(filepath.ToSlash(name))
+	var matches []string
+	for _, f := range files {
+		if strings.Contains(strings.ToLower(f), lower) {
+			matches = append(matches, f)
+		} else if q, err := quiz.ParseFile(filepath.Join(root, filepath.FromSlash(f))); err == nil &&
+			strings.Contains(strings.ToLower(q.Title), lower) {
+			matches = append(matches, f)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no quiz matches %q", name)
+	case 1:
+		return filepath.Join(root, filepath.FromSlash(matches[0])), nil
+	}
+	return "", fmt.Errorf("%q matches several quizzes:\n  %s", name, strings.Join(matches, "\n  "))
+}
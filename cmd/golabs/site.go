@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go-labs/internal/site"
+)
+
+func runSite(root string, args []string) error {
+	fs := flag.NewFlagSet("site", flag.ExitOnError)
+	out := fs.String("o", "", "write the site to `DIR` (default: _site in the repository root)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: golabs site [-o DIR]")
+		fmt.Fprintln(os.Stderr, "\nRenders the notes, labs and quizzes as a static HTML site that works offline.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	dir := *out
+	if dir == "" {
+		dir = filepath.Join(root, "_site")
+	}
+
+	s, err := site.Generate(root)
+	if err != nil {
+		return err
+	}
+	if err := s.Write(dir); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d pages to %s\n", len(s.Pages), dir)
+	fmt.Printf("open %s\n", filepath.Join(dir, "index.html"))
+	return nil
+}
@@ -0,0 +1,301 @@
+// Package catalog describes the labs found by package labs. Labs carry no
+// metadata of their own, so the chapter, topic and title of each are derived
+// from its directory path, its leading file comments and its Markdown notes.
+//
+// For 09_control_structures/03_switch_case_construct the chapter is 9,
+// "Control structures", the topic is "Switch case construct", and the title
+// comes from the first of: a doc comment such as "// Command kvtool
+// inspects ...", the first heading of a Markdown file in the directory, a
+// "// control_structures.go" file header, or the directory name.
+//
+// A single-file program, such as 12_concurrency/03_channel_patterns.go, is
+// described from its own file instead: its notes are the Markdown file of
+// the same name, and its title falls back to the file name.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-labs/internal/labs"
+)
+
+// Entry is a lab with its derived metadata.
+type Entry struct {
+	labs.Lab
+	ChapterNum   int    // from the numeric prefix of the chapter directory; 0 if none
+	ChapterTitle string // "Control structures"
+	Topic        string // second path element, humanized; empty for chapter-level labs
+	Title        string
+	Doc          string // leading doc comment of the lab's files, if any
+	Notes        string // Markdown file documenting the lab, relative to the root
+}
+
+// Catalog is the set of labs of a repository.
+type Catalog struct {
+	Root    string
+	Entries []Entry
+}
+
+// ErrNotFound is returned by Find when no lab matches.
+var ErrNotFound = errors.New("catalog: no such lab")
+
+// AmbiguousError is returned by Find when a name matches several labs.
+type AmbiguousError struct {
+	Name       string
+	Candidates []string
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("catalog: %q matches %d labs: %s", e.Name, len(e.Candidates), strings.Join(e.Candidates, ", "))
+}
+
+// Load discovers the labs under root and derives their metadata.
+func Load(root string) (*Catalog, error) {
+	found, err := labs.Discover(root)
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{Root: root}
+	for _, lab := range found {
+		c.Entries = append(c.Entries, describe(root, lab))
+	}
+	sort.SliceStable(c.Entries, func(i, j int) bool {
+		a, b := c.Entries[i], c.Entries[j]
+		if a.ChapterNum != b.ChapterNum {
+			// Unnumbered chapters, such as practice, go last.
+			return a.ChapterNum != 0 && (b.ChapterNum == 0 || a.ChapterNum < b.ChapterNum)
+		}
+		return a.Path() < b.Path()
+	})
+	return c, nil
+}
+
+// Chapter returns the labs of the chapters matching filter, which may be a
+// number ("9" or "09"), a directory name ("09_control_structures") or part
+// of a chapter title ("control").
+func (c *Catalog) Chapter(filter string) []Entry {
+	filter = strings.ToLower(strings.TrimSuffix(filter, "/"))
+	n, numErr := strconv.Atoi(filter)
+	var out []Entry
+	for _, e := range c.Entries {
+		switch {
+		case numErr == nil && e.ChapterNum == n && e.ChapterNum != 0,
+			strings.ToLower(e.Chapter) == filter,
+			strings.Contains(strings.ToLower(e.ChapterTitle), filter):
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Find looks up a lab by its path ("06_maps/kvstore/kvtool"), by a trailing
+// part of it ("kvtool", "03_switch_case_construct") or by such a part
+// without numeric prefixes ("switch_case_construct"). The ".go" of a
+// single-file program may be left out, and its directory matches each of
+// the programs in it. When a name matches a chapter lab and practice
+// exercises, the lab wins; the exercises are found with a longer name, such
+// as "practice/arrays".
+func (c *Catalog) Find(name string) (Entry, error) {
+	name = strings.Trim(filepath.ToSlash(name), "/")
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "./"), ".go")
+	var matches []Entry
+	for _, e := range c.Entries {
+		key := strings.TrimSuffix(e.Path(), ".go")
+		if key == name {
+			return e, nil
+		}
+		if matchesTail(key, name) || e.File != "" && matchesTail(e.Dir, name) {
+			matches = append(matches, e)
+		}
+	}
+	if len(matches) > 1 {
+		var outside []Entry
+		for _, m := range matches {
+			if !strings.HasPrefix(m.Dir, "practice/") {
+				outside = append(outside, m)
+			}
+		}
+		if len(outside) == 1 {
+			return outside[0], nil
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	case 1:
+		return matches[0], nil
+	}
+	amb := &AmbiguousError{Name: name}
+	for _, m := range matches {
+		amb.Candidates = append(amb.Candidates, m.Path())
+	}
+	return Entry{}, amb
+}
+
+// matchesTail reports whether name is a trailing part of p, with or without
+// numeric prefixes.
+func matchesTail(p, name string) bool {
+	return strings.HasSuffix("/"+p, "/"+name) || strings.HasSuffix("/"+stripNumbers(p), "/"+name)
+}
+
+var numberPrefix = regexp.MustCompile(`^(\d+)[_-]`)
+
+// stripNumbers removes "03_" style prefixes from every path element.
+func stripNumbers(dir string) string {
+	parts := strings.Split(dir, "/")
+	for i, p := range parts {
+		parts[i] = numberPrefix.ReplaceAllString(p, "")
+	}
+	return strings.Join(parts, "/")
+}
+
+// humanize turns "03_switch_case_construct" into "Switch case construct".
+func humanize(name string) string {
+	name = numberPrefix.ReplaceAllString(name, "")
+	name = strings.TrimSuffix(name, ".go")
+	name = strings.NewReplacer("_", " ", "-", " ").Replace(name)
+	return capitalize(name)
+}
+
+func describe(root string, lab labs.Lab) Entry {
+	e := Entry{Lab: lab, ChapterTitle: humanize(lab.Chapter)}
+	if m := numberPrefix.FindStringSubmatch(lab.Chapter); m != nil {
+		e.ChapterNum, _ = strconv.Atoi(m[1])
+	}
+	if parts := strings.Split(lab.Dir, "/"); len(parts) > 1 {
+		e.Topic = humanize(parts[1])
+	}
+
+	dir := filepath.Join(root, filepath.FromSlash(lab.Dir))
+	doc, header := leadingComments(dir, lab.Files)
+	e.Doc = doc
+	notes, heading := markdownNotes(dir, lab.File)
+	if notes != "" {
+		e.Notes = path.Join(lab.Dir, notes)
+	}
+	if lab.File != "" && notes != notesFor(lab.File) {
+		// Notes shared by the programs of the directory do not name this one.
+		heading = ""
+	}
+	switch {
+	case doc != "":
+		e.Title = docTitle(doc)
+	case heading != "":
+		e.Title = heading
+	case header != "":
+		e.Title = humanize(header)
+	default:
+		e.Title = humanize(path.Base(lab.Path()))
+	}
+	return e
+}
+
+var fileHeader = regexp.MustCompile(`^[\w.-]+\.go$`)
+
+// leadingComments returns the first doc comment of the files, and the first
+// "// name.go" header comment, which is not documentation.
+func leadingComments(dir string, files []string) (doc, header string) {
+	fset := token.NewFileSet()
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		for _, cg := range f.Comments {
+			if cg.Pos() > f.Package {
+				break
+			}
+			text := strings.TrimSpace(cg.Text())
+			switch {
+			case text == "" || strings.HasPrefix(text, "go:build") || strings.HasPrefix(text, "+build"):
+			case fileHeader.MatchString(text):
+				if header == "" {
+					header = text
+				}
+			case doc == "":
+				doc = text
+			}
+		}
+	}
+	return doc, header
+}
+
+// docTitle turns the first sentence of a doc comment into a title, dropping
+// a leading "Command name" or "Package name".
+func docTitle(doc string) string {
+	s := strings.Join(strings.Fields(doc), " ")
+	if i := strings.Index(s, ". "); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSuffix(s, ".")
+	if f := strings.Fields(s); len(f) > 2 && (f[0] == "Command" || f[0] == "Package") {
+		if f[2] == "is" && len(f) > 3 {
+			f = f[1:] // "Command repl is an interactive calculator"
+		}
+		s = strings.Join(f[2:], " ")
+	}
+	return capitalize(s)
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// notesFor returns the name of the Markdown notes of the program in file.
+func notesFor(file string) string {
+	return strings.TrimSuffix(file, ".go") + ".md"
+}
+
+// markdownNotes returns the Markdown file in dir, preferring README.md, and
+// its first heading. For the program in file, it prefers the notes named
+// after it and skips those named after the other programs of dir.
+func markdownNotes(dir, file string) (name, heading string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", ""
+	}
+	others := make(map[string]bool)
+	for _, e := range entries {
+		if file != "" && !e.IsDir() && strings.HasSuffix(e.Name(), ".go") && e.Name() != file {
+			others[notesFor(e.Name())] = true
+		}
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".md") || others[e.Name()] {
+			continue
+		}
+		if file != "" && e.Name() == notesFor(file) {
+			name = e.Name()
+			break
+		}
+		if name == "" || strings.EqualFold(e.Name(), "README.md") {
+			name = e.Name()
+		}
+	}
+	if name == "" {
+		return "", ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return name, ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "# ") {
+			return name, capitalize(strings.TrimSpace(strings.NewReplacer("`", "", "**", "").Replace(line[2:])))
+		}
+	}
+	return name, ""
+}
@@ -0,0 +1,129 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+
+	"go-labs/internal/labs"
+	"go-labs/internal/testutil"
+)
+
+var tree = map[string]string{
+	"go.mod":                               "module example.com/labs\n\ngo 1.19\n",
+	"02_basics/01_hello/hello.go":          "// hello.go\n\npackage main\n\nfunc main() {}\n",
+	"02_basics/02_loops/README.md":         "Intro\n\n# The `for` loop\n\nText.\n",
+	"02_basics/02_loops/loops.go":          "package main\n\nfunc main() {}\n",
+	"06_maps/maps_examples.go":             "// maps_examples.go\npackage main\n\nfunc main() {}\n",
+	"06_maps/store/tool/main.go":           "//go:build ignore || !ignore\n\n// Command tool inspects store files.\n//\n// Usage:\n//\n//\ttool FILE\npackage main\n\nfunc main() {}\n",
+	"07_conc/01_go.go":                     "package main\n\nfunc main() {}\n",
+	"07_conc/01_go.md":                     "# Goroutines\n",
+	"07_conc/02_chan.go":                   "// channels.go\npackage main\n\nfunc main() {}\n",
+	"07_conc/README.md":                    "# Concurrency\n",
+	"10_calc/repl/main.go":                 "// Command repl is an interactive calculator. It reads lines.\npackage main\n\nfunc main() {}\n",
+	"10_calc/empty_dir/x.go":               "package main\n\nfunc main() {}\n",
+	"practice/02_basics/01_hello/hello.go": "package main\n\nfunc main() {}\n",
+}
+
+func load(t *testing.T) *Catalog {
+	t.Helper()
+	root := t.TempDir()
+	testutil.WriteTree(t, root, tree)
+	c, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestLoad(t *testing.T) {
+	c := load(t)
+	expected := []struct {
+		path, chapterTitle, topic, title, notes string
+		chapterNum                              int
+	}{
+		{"02_basics/01_hello", "Basics", "Hello", "Hello", "", 2},
+		{"02_basics/02_loops", "Basics", "Loops", "The for loop", "02_basics/02_loops/README.md", 2},
+		{"06_maps", "Maps", "", "Maps examples", "", 6},
+		{"06_maps/store/tool", "Maps", "Store", "Inspects store files", "", 6},
+		{"07_conc/01_go.go", "Conc", "", "Goroutines", "07_conc/01_go.md", 7},
+		{"07_conc/02_chan.go", "Conc", "", "Channels", "07_conc/README.md", 7},
+		{"10_calc/empty_dir", "Calc", "Empty dir", "Empty dir", "", 10},
+		{"10_calc/repl", "Calc", "Repl", "An interactive calculator", "", 10},
+		{"practice/02_basics/01_hello", "Practice", "Basics", "Hello", "", 0},
+	}
+	if len(c.Entries) != len(expected) {
+		t.Fatalf("Load returned %d entries. Expected %d", len(c.Entries), len(expected))
+	}
+	for i, x := range expected {
+		e := c.Entries[i]
+		if e.Path() != x.path || e.ChapterNum != x.chapterNum || e.ChapterTitle != x.chapterTitle ||
+			e.Topic != x.topic || e.Title != x.title || e.Notes != x.notes {
+			t.Errorf("entry %d is %s %d %q %q %q %q. Expected %s %d %q %q %q %q", i,
+				e.Path(), e.ChapterNum, e.ChapterTitle, e.Topic, e.Title, e.Notes,
+				x.path, x.chapterNum, x.chapterTitle, x.topic, x.title, x.notes)
+		}
+	}
+	if doc := c.Entries[3].Doc; doc != "Command tool inspects store files.\n\nUsage:\n\n\ttool FILE" {
+		t.Errorf("Doc is %q", doc)
+	}
+}
+
+func TestChapter(t *testing.T) {
+	c := load(t)
+	tests := []struct {
+		filter string
+		count  int
+	}{
+		{"2", 2},
+		{"02", 2},
+		{"02_basics", 2},
+		{"06_maps/", 2},
+		{"calc", 2},
+		{"practice", 1},
+		{"0", 0},
+		{"nothing", 0},
+	}
+	for _, test := range tests {
+		if got := c.Chapter(test.filter); len(got) != test.count {
+			t.Errorf("Chapter(%q) returned %d labs. Expected %d", test.filter, len(got), test.count)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	c := load(t)
+	tests := []struct {
+		name, path string
+	}{
+		{"06_maps/store/tool", "06_maps/store/tool"},
+		{"./06_maps/", "06_maps"},
+		{"tool", "06_maps/store/tool"},
+		{"store/tool", "06_maps/store/tool"},
+		{"loops", "02_basics/02_loops"},
+		{"basics/loops", "02_basics/02_loops"},
+		{"02_basics/01_hello", "02_basics/01_hello"},
+		{"hello", "02_basics/01_hello"},
+		{"practice/basics/hello", "practice/02_basics/01_hello"},
+		{"07_conc/01_go.go", "07_conc/01_go.go"},
+		{"01_go", "07_conc/01_go.go"},
+		{"conc/chan.go", "07_conc/02_chan.go"},
+	}
+	for _, test := range tests {
+		e, err := c.Find(test.name)
+		if err != nil || e.Path() != test.path {
+			t.Errorf("Find(%q) returned %q, %v. Expected %q", test.name, e.Path(), err, test.path)
+		}
+	}
+
+	if _, err := c.Find("nosuch"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find(\"nosuch\") returned %v. Expected ErrNotFound", err)
+	}
+	var amb *AmbiguousError
+	if _, err := c.Find("conc"); !errors.As(err, &amb) || len(amb.Candidates) != 2 {
+		t.Errorf("Find(\"conc\") returned %v. Expected an AmbiguousError with 2 candidates", err)
+	}
+	c.Entries = append(c.Entries, Entry{Lab: labs.Lab{Dir: "10_calc/tool"}})
+	if _, err := c.Find("tool"); !errors.As(err, &amb) || len(amb.Candidates) != 2 {
+		t.Errorf("Find(\"tool\") returned %v. Expected an AmbiguousError with 2 candidates", err)
+	}
+}
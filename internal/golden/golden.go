@@ -0,0 +1,203 @@
+// Package golden runs labs and compares what they print with checked-in
+// golden files, so that a change to a lab's output is noticed.
+//
+// A lab's expected standard output lives in testdata/output.golden inside
+// the lab directory, or in testdata/NAME.golden for the program in NAME.go
+// of a directory of single-file programs. The lab runs there with go run,
+// no arguments and empty standard input. Labs whose output is not repeatable opt in to rules with
+// directive comments in any of their files:
+//
+//	//golden:skip REASON      do not run the lab
+//	//golden:args ARG...      run the lab with these arguments
+//	//golden:seed N           set GOLABS_SEED=N, which util.Rand uses as its seed
+//	//golden:mask PATTERN     replace matches of PATTERN in the output
+//	//golden:unordered REGEXP sort the lines of each match of REGEXP
+//
+// PATTERN is a regular expression, or one of the names in Masks, such as
+// timestamp, whose matches are replaced with "<timestamp>". Unordered suits
+// output printed while ranging over a map.
+package golden
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-labs/internal/labs"
+)
+
+// File is the name of a lab's golden file within its testdata directory,
+// unless the lab is a single-file program.
+const File = "output.golden"
+
+// SeedEnv is the environment variable set by the seed rule.
+const SeedEnv = "GOLABS_SEED"
+
+// Masks are the predefined patterns of the mask rule.
+var Masks = map[string]*regexp.Regexp{
+	// time.Time's String and RFC 3339, with optional fraction, zone and
+	// monotonic clock reading.
+	"timestamp": regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}| [+-]\d{4}( [A-Za-z][A-Za-z0-9+-]*)?)?( m=[+-]\d+\.\d+)?`),
+	// time.Duration's String.
+	"duration": regexp.MustCompile(`\b(\d+h)?(\d+m)?\d+(\.\d+)?(ns|µs|us|ms|s)\b`),
+	// Pointers printed with %p or %v.
+	"address": regexp.MustCompile(`0x[0-9a-f]{6,}`),
+}
+
+// Mask is a normalisation rule.
+type Mask struct {
+	Name string // predefined name, or the pattern itself
+	Re   *regexp.Regexp
+}
+
+// replacement is what a match of m becomes.
+func (m Mask) replacement() string {
+	if _, ok := Masks[m.Name]; ok {
+		return "<" + m.Name + ">"
+	}
+	return "<masked>"
+}
+
+// Rules are the directives of a lab.
+type Rules struct {
+	Skip  string // reason to skip; empty runs the lab
+	Args  []string
+	Seed  string // value of GOLABS_SEED; empty leaves it unset
+	Masks []Mask
+	// Unordered are patterns whose matched lines may come in any order.
+	Unordered []*regexp.Regexp
+}
+
+const prefix = "//golden:"
+
+// ReadRules collects the directives of a lab's files.
+func ReadRules(root string, lab labs.Lab) (Rules, error) {
+	var rules Rules
+	for _, name := range lab.Files {
+		path := filepath.Join(root, filepath.FromSlash(lab.Dir), name)
+		f, err := os.Open(path)
+		if err != nil {
+			return Rules{}, err
+		}
+		err = parse(f, &rules)
+		f.Close()
+		if err != nil {
+			return Rules{}, fmt.Errorf("%s:%w", path, err)
+		}
+	}
+	return rules, nil
+}
+
+// ParseRules reads directives from Go source.
+func ParseRules(r io.Reader) (Rules, error) {
+	var rules Rules
+	err := parse(r, &rules)
+	return rules, err
+}
+
+func parse(r io.Reader, rules *Rules) error {
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		verb, arg, _ := strings.Cut(strings.TrimPrefix(line, prefix), " ")
+		arg = strings.TrimSpace(arg)
+		switch verb {
+		case "skip":
+			rules.Skip = arg
+			if arg == "" {
+				rules.Skip = "skipped"
+			}
+		case "args":
+			rules.Args = append(rules.Args, strings.Fields(arg)...)
+		case "seed":
+			if _, err := strconv.ParseUint(arg, 10, 64); err != nil {
+				return fmt.Errorf("%d: seed %q is not a non-negative integer", n, arg)
+			}
+			rules.Seed = arg
+		case "mask":
+			re, ok := Masks[arg]
+			if !ok {
+				var err error
+				if re, err = regexp.Compile(arg); err != nil || arg == "" {
+					return fmt.Errorf("%d: mask %q is neither a predefined mask nor a regular expression", n, arg)
+				}
+			}
+			rules.Masks = append(rules.Masks, Mask{Name: arg, Re: re})
+		case "unordered":
+			re, err := regexp.Compile(arg)
+			if err != nil || arg == "" {
+				return fmt.Errorf("%d: unordered %q is not a regular expression", n, arg)
+			}
+			rules.Unordered = append(rules.Unordered, re)
+		default:
+			return fmt.Errorf("%d: unknown directive %s%s", n, prefix, verb)
+		}
+	}
+	return sc.Err()
+}
+
+// Normalize sorts the unordered lines of out and applies the masks.
+func (r Rules) Normalize(out []byte) []byte {
+	for _, re := range r.Unordered {
+		out = re.ReplaceAllFunc(out, sortLines)
+	}
+	for _, m := range r.Masks {
+		out = m.Re.ReplaceAllLiteral(out, []byte(m.replacement()))
+	}
+	return out
+}
+
+// sortLines sorts the lines of b, keeping a final newline in place.
+func sortLines(b []byte) []byte {
+	trimmed := bytes.TrimSuffix(b, []byte("\n"))
+	lines := bytes.Split(trimmed, []byte("\n"))
+	sort.Slice(lines, func(i, j int) bool { return bytes.Compare(lines[i], lines[j]) < 0 })
+	out := bytes.Join(lines, []byte("\n"))
+	if len(trimmed) < len(b) {
+		out = append(out, '\n')
+	}
+	return out
+}
+
+// Path returns the golden file of a lab.
+func Path(root string, lab labs.Lab) string {
+	name := File
+	if lab.File != "" {
+		name = lab.Name() + ".golden"
+	}
+	return filepath.Join(root, filepath.FromSlash(lab.Dir), "testdata", name)
+}
+
+// Run runs a lab as the rules say and returns its normalised standard
+// output. A lab that fails, including one that does not build, returns an
+// error holding its standard error.
+func Run(ctx context.Context, root string, lab labs.Lab, rules Rules) ([]byte, error) {
+	target := "."
+	if lab.File != "" {
+		target = lab.File
+	}
+	cmd := exec.CommandContext(ctx, "go", append([]string{"run", target}, rules.Args...)...)
+	cmd.Dir = filepath.Join(root, filepath.FromSlash(lab.Dir))
+	cmd.Env = os.Environ()
+	if rules.Seed != "" {
+		cmd.Env = append(cmd.Env, SeedEnv+"="+rules.Seed)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %v\n%s", lab.Path(), err, strings.TrimSpace(stderr.String()))
+	}
+	return rules.Normalize(stdout.Bytes()), nil
+}
@@ -0,0 +1,158 @@
+package golden
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-labs/internal/labs"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the labs")
+
+func TestParseRules(t *testing.T) {
+	src := `// lab.go
+//golden:seed 42
+//golden:mask timestamp
+//golden:args -n 3  file.txt
+package main
+
+	//golden:mask id=\d+
+func main() {}
+`
+	rules, err := ParseRules(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Seed != "42" || rules.Skip != "" {
+		t.Errorf("Seed, Skip are %q, %q. Expected \"42\", \"\"", rules.Seed, rules.Skip)
+	}
+	if got := strings.Join(rules.Args, ","); got != "-n,3,file.txt" {
+		t.Errorf("Args are %q. Expected \"-n,3,file.txt\"", got)
+	}
+	if len(rules.Masks) != 2 || rules.Masks[0].Name != "timestamp" || rules.Masks[1].Name != `id=\d+` {
+		t.Errorf("Masks are %v. Expected timestamp and id=\\d+", rules.Masks)
+	}
+
+	rules, err = ParseRules(strings.NewReader("//golden:skip\n"))
+	if err != nil || rules.Skip != "skipped" {
+		t.Errorf("ParseRules(skip) returned %q, %v. Expected \"skipped\", nil", rules.Skip, err)
+	}
+
+	bad := []string{
+		"//golden:seed -1\n",
+		"//golden:seed\n",
+		"//golden:mask (\n",
+		"//golden:mask\n",
+		"//golden:unordered [\n",
+		"package main\n//golden:frobnicate\n",
+	}
+	for _, src := range bad {
+		if _, err := ParseRules(strings.NewReader(src)); err == nil {
+			t.Errorf("ParseRules(%q) succeeded. Expected an error", src)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		mask, in, expected string
+	}{
+		{"timestamp", "Current time: 2026-10-19 14:03:27.123456789 +0200 CEST m=+0.000081542\n", "Current time: <timestamp>\n"},
+		{"timestamp", "Tomorrow: 2026-10-20 14:03:27.1 +0000 UTC\n", "Tomorrow: <timestamp>\n"},
+		{"timestamp", "Formatted: 2026-10-19T14:03:27+02:00 and 2026-10-19T12:03:27Z\n", "Formatted: <timestamp> and <timestamp>\n"},
+		{"duration", "took 1.5ms, then 2m3.25s\n", "took <duration>, then <duration>\n"},
+		{"address", "&a: 0xc000012345 p: 0x1158b1742120 small: 0x1f\n", "&a: <address> p: <address> small: 0x1f\n"},
+		{`id=\d+`, "id=17 id=x\n", "<masked> id=x\n"},
+	}
+	for _, test := range tests {
+		rules, err := ParseRules(strings.NewReader("//golden:mask " + test.mask + "\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(rules.Normalize([]byte(test.in))); got != test.expected {
+			t.Errorf("Normalize(%q) with mask %s returned %q. Expected %q", test.in, test.mask, got, test.expected)
+		}
+	}
+}
+
+func TestUnordered(t *testing.T) {
+	rules, err := ParseRules(strings.NewReader(`//golden:unordered (?m)(^  \w+ -> \d+\n)+` + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := "Iterating:\n  pears -> 2\n  apples -> 5\nDone: 2\n  b -> 1\n  a -> 1\n"
+	expected := "Iterating:\n  apples -> 5\n  pears -> 2\nDone: 2\n  a -> 1\n  b -> 1\n"
+	if got := string(rules.Normalize([]byte(in))); got != expected {
+		t.Errorf("Normalize(%q) returned %q. Expected %q", in, got, expected)
+	}
+}
+
+// TestLabs runs every lab of the repository, except the practice
+// exercises, and compares its output with its golden file. Run it with
+// -update to rewrite the golden files, and with -count=1 after changing a
+// package that labs import, as the test cache only notices changes to the
+// labs themselves.
+func TestLabs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("running every lab is slow")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	root, err := labs.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := labs.Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lab := range all {
+		lab := lab
+		t.Run(lab.Path(), func(t *testing.T) {
+			t.Parallel()
+			if strings.HasPrefix(lab.Dir, "practice/") {
+				t.Skip("exercise output changes as it is solved")
+			}
+			rules, err := ReadRules(root, lab)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rules.Skip != "" {
+				t.Skip(rules.Skip)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+			got, err := Run(ctx, root, lab, rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			path := Path(root, lab)
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output does not match %s (run go test -update if the change is intended)\ngot:\n%s\nwant:\n%s",
+					path, got, want)
+			}
+		})
+	}
+}
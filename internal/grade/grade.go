@@ -0,0 +1,359 @@
+// Package grade checks the practice exercises against hidden tests.
+//
+// An exercise is a lab whose directory holds testdata/grade_test.go. The
+// lab's own files declare the functions to implement, as stubs returning
+// zero values that the learner replaces. The test file, kept in testdata so
+// that go test and go vet leave it alone, is in the lab's package and tests
+// those functions: TestF tests the function F, and its doc comment is the
+// hint shown when it fails. Subtests are the cases reported one by one:
+//
+//	// Start from a total of 0 and add each value of a for range loop to it.
+//	func TestSum(t *testing.T) {
+//		t.Run("zeros", func(t *testing.T) { ... })
+//		...
+//	}
+//
+// Grade adds the test file to the lab's package with go test -overlay, so
+// the learner's code is tested in place without the tests being visible in
+// the lab directory. The tests of an exercise must finish within 30 seconds;
+// the case that was running when they ran out of time fails.
+package grade
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-labs/internal/labs"
+)
+
+// File is the name of an exercise's test file within its testdata directory.
+const File = "grade_test.go"
+
+// ErrNotExercise is returned by Load for a lab without a test file.
+var ErrNotExercise = errors.New("not an exercise")
+
+// Exercise is a lab with hidden tests.
+type Exercise struct {
+	labs.Lab
+	Tests []Test // in file order
+}
+
+// Test is a test function of an exercise.
+type Test struct {
+	Name string // such as TestSum
+	Func string // the function it tests, such as Sum
+	Hint string
+}
+
+// Path returns the test file of the exercise in dir.
+func Path(root, dir string) string {
+	return filepath.Join(root, filepath.FromSlash(dir), "testdata", File)
+}
+
+// Find returns the exercises among the labs under root.
+func Find(root string) ([]Exercise, error) {
+	all, err := labs.Discover(root)
+	if err != nil {
+		return nil, err
+	}
+	var exercises []Exercise
+	for _, lab := range all {
+		ex, err := Load(root, lab)
+		if errors.Is(err, ErrNotExercise) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, ex)
+	}
+	return exercises, nil
+}
+
+// Load reads the tests of the exercise in lab.
+func Load(root string, lab labs.Lab) (Exercise, error) {
+	path := Path(root, lab.Dir)
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if errors.Is(err, os.ErrNotExist) {
+		return Exercise{}, fmt.Errorf("%s: %w", lab.Dir, ErrNotExercise)
+	}
+	if err != nil {
+		return Exercise{}, err
+	}
+	ex := Exercise{Lab: lab}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") || fn.Name.Name == "TestMain" {
+			continue
+		}
+		ex.Tests = append(ex.Tests, Test{
+			Name: fn.Name.Name,
+			Func: strings.TrimPrefix(fn.Name.Name, "Test"),
+			Hint: strings.Join(strings.Fields(fn.Doc.Text()), " "),
+		})
+	}
+	if len(ex.Tests) == 0 {
+		return Exercise{}, fmt.Errorf("%s: no Test functions", path)
+	}
+	return ex, nil
+}
+
+// Report is the outcome of grading an exercise.
+type Report struct {
+	Exercise Exercise
+	Results  []Result // one per test, in file order
+	// Build holds the compiler errors when the exercise does not build;
+	// Results are then empty.
+	Build string
+}
+
+// Result is the outcome of a test.
+type Result struct {
+	Test
+	Missing bool   // the lab does not declare Func
+	Cases   []Case // the subtests, or the test itself if it has none
+}
+
+// Case is the outcome of one case of a test.
+type Case struct {
+	Name    string // subtest name, empty for a test without subtests
+	Passed  bool
+	Message string // what the test reported on failure
+}
+
+// Passed reports whether every case of r passed.
+func (r Result) Passed() bool {
+	if r.Missing || len(r.Cases) == 0 {
+		return false
+	}
+	for _, c := range r.Cases {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns how many cases passed, of how many. A missing function
+// counts as one failed case.
+func (r *Report) Count() (passed, total int) {
+	for _, res := range r.Results {
+		if res.Missing {
+			total++
+			continue
+		}
+		for _, c := range res.Cases {
+			total++
+			if c.Passed {
+				passed++
+			}
+		}
+	}
+	return passed, total
+}
+
+// timeout bounds each run of go test, so that a loop that never ends fails
+// its case instead of hanging the grader.
+var timeout = 30 * time.Second
+
+// timeoutHint follows the message of a case that ran out of time.
+const timeoutHint = "look for a loop that never ends or a channel operation that blocks forever"
+
+// Grade runs the tests of ex against the code in its lab directory.
+func Grade(ctx context.Context, root string, ex Exercise) (*Report, error) {
+	report := &Report{Exercise: ex}
+	declared := funcs(root, ex.Lab)
+	missing := false
+	for _, t := range ex.Tests {
+		if !declared[t.Func] {
+			missing = true
+		}
+		report.Results = append(report.Results, Result{Test: t, Missing: !declared[t.Func]})
+	}
+	if missing {
+		// The tests would not compile; report only what to declare.
+		return report, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "golabs-grade-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	overlay, err := json.Marshal(map[string]map[string]string{"Replace": {
+		filepath.Join(root, filepath.FromSlash(ex.Dir), File): Path(root, ex.Dir),
+	}})
+	if err != nil {
+		return nil, err
+	}
+	overlayFile := filepath.Join(tmp, "overlay.json")
+	if err := os.WriteFile(overlayFile, overlay, 0o644); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-overlay", overlayFile, "-json", "-count=1",
+		"-timeout", timeout.String(), "./"+ex.Dir)
+	cmd.Dir = root
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+	var exit *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exit) {
+		return nil, runErr
+	}
+
+	cases, build, err := parseEvents(&stdout)
+	if err != nil {
+		return nil, err
+	}
+	build += stderr.String()
+	if len(cases) == 0 && runErr != nil {
+		report.Build = strings.TrimSpace(build)
+		return report, nil
+	}
+	for i := range report.Results {
+		report.Results[i].Cases = cases[report.Results[i].Name]
+	}
+	return report, nil
+}
+
+// funcs returns the names of the top-level functions declared by the lab.
+func funcs(root string, lab labs.Lab) map[string]bool {
+	names := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, file := range lab.Files {
+		f, err := parser.ParseFile(fset, filepath.Join(root, filepath.FromSlash(lab.Dir), file), nil, parser.SkipObjectResolution)
+		if err != nil {
+			// Let the compiler report it.
+			continue
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				names[fn.Name.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// event is a line of go test -json output.
+type event struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// frameRe matches the lines go test writes around a test's own output.
+var frameRe = regexp.MustCompile(`^\s*(=== (RUN|PAUSE|CONT|NAME)|--- (PASS|FAIL|SKIP):)`)
+
+// logRe matches the file:line prefix of t.Error output.
+var logRe = regexp.MustCompile(`^\s*\S+\.go:\d+: `)
+
+// caseKey returns the case a test belongs to: the test itself, or its
+// subtest at the first level.
+func caseKey(test string) string {
+	parts := strings.SplitN(test, "/", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, "/")
+}
+
+// message joins the lines a case printed, leaving out the goroutine stacks
+// of a panic.
+func message(lines []string) string {
+	for i, line := range lines {
+		if strings.HasPrefix(line, "goroutine ") {
+			lines = lines[:i]
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseEvents reads go test -json output, returning the cases of each
+// top-level test and any output that is not about a test, such as compiler
+// errors.
+func parseEvents(r io.Reader) (map[string][]Case, string, error) {
+	var (
+		build    strings.Builder
+		order    []string
+		outcome  = make(map[string]string)
+		messages = make(map[string][]string)
+		timedOut = make(map[string]string)
+	)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		var e event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil || e.Action == "" {
+			build.WriteString(sc.Text() + "\n")
+			continue
+		}
+		switch {
+		case e.Action == "build-output":
+			build.WriteString(e.Output)
+		case e.Test == "":
+		case e.Action == "run":
+			order = append(order, e.Test)
+		case e.Action == "output":
+			line := strings.TrimRight(e.Output, "\n")
+			if strings.HasPrefix(line, "panic: test timed out") {
+				// The test binary's alarm: the case never finished.
+				timedOut[caseKey(e.Test)] = strings.TrimPrefix(line, "panic: ")
+			}
+			if !frameRe.MatchString(line) && strings.TrimSpace(line) != "" {
+				key := caseKey(e.Test)
+				messages[key] = append(messages[key], logRe.ReplaceAllString(line, ""))
+			}
+		case e.Action == "pass" || e.Action == "fail" || e.Action == "skip":
+			outcome[e.Test] = e.Action
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, "", err
+	}
+
+	hasSub, subFailed := make(map[string]bool), make(map[string]bool)
+	for _, name := range order {
+		if parent, _, ok := strings.Cut(name, "/"); ok {
+			hasSub[parent] = true
+			subFailed[parent] = subFailed[parent] || outcome[name] != "pass"
+		}
+	}
+	cases := make(map[string][]Case)
+	for _, name := range order {
+		if name != caseKey(name) {
+			continue
+		}
+		parent, sub, _ := strings.Cut(name, "/")
+		if sub == "" && hasSub[name] {
+			// A failure of the test itself, outside its subtests.
+			if outcome[name] == "fail" && !subFailed[name] {
+				cases[name] = append(cases[name], Case{Message: message(messages[name])})
+			}
+			continue
+		}
+		c := Case{Name: strings.ReplaceAll(sub, "_", " "), Passed: outcome[name] == "pass"}
+		if msg, ok := timedOut[name]; ok {
+			c.Message = msg + ": " + timeoutHint
+		} else if !c.Passed {
+			c.Message = message(messages[name])
+		}
+		cases[parent] = append(cases[parent], c)
+	}
+	return cases, build.String(), nil
+}
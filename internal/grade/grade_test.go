@@ -0,0 +1,188 @@
+package grade
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-labs/internal/testutil"
+)
+
+const tests = `package main
+
+import "testing"
+
+// Add the two numbers.
+func TestAdd(t *testing.T) {
+	t.Run("small numbers", func(t *testing.T) {
+		if got := Add(1, 2); got != 3 {
+			t.Errorf("Add(1, 2) = %d. Expected 3", got)
+		}
+	})
+	t.Run("zero", func(t *testing.T) {
+		if got := Add(0, 0); got != 0 {
+			t.Errorf("Add(0, 0) = %d. Expected 0", got)
+		}
+	})
+}
+
+// Return the first element.
+func TestFirst(t *testing.T) {
+	if got := First([]int{7}); got != 7 {
+		t.Errorf("First([7]) = %d. Expected 7", got)
+	}
+}
+`
+
+func exercise(t *testing.T, code string) (string, Exercise) {
+	t.Helper()
+	root := t.TempDir()
+	testutil.WriteTree(t, root, map[string]string{
+		"go.mod":                       "module example.com/labs\n\ngo 1.19\n",
+		"practice/ex/ex.go":            "package main\n\n" + code + "\nfunc main() {}\n",
+		"practice/ex/testdata/" + File: tests,
+		"practice/other/other.go":      "package main\n\nfunc main() {}\n",
+	})
+	exercises, err := Find(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exercises) != 1 || exercises[0].Dir != "practice/ex" {
+		t.Fatalf("Find returned %+v. Expected only practice/ex", exercises)
+	}
+	return root, exercises[0]
+}
+
+func TestLoad(t *testing.T) {
+	_, ex := exercise(t, "")
+	expected := []Test{
+		{Name: "TestAdd", Func: "Add", Hint: "Add the two numbers."},
+		{Name: "TestFirst", Func: "First", Hint: "Return the first element."},
+	}
+	if !reflect.DeepEqual(ex.Tests, expected) {
+		t.Errorf("Load returned tests %+v. Expected %+v", ex.Tests, expected)
+	}
+}
+
+func TestGrade(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	tests := []struct {
+		name    string
+		code    string
+		passed  int
+		total   int
+		results map[string][]Case // by function; nil for a missing one
+		build   string            // part of the compiler errors
+	}{
+		{
+			name:   "solved",
+			code:   "func Add(a, b int) int { return a + b }\nfunc First(s []int) int { return s[0] }\n",
+			passed: 3, total: 3,
+			results: map[string][]Case{
+				"Add":   {{Name: "small numbers", Passed: true}, {Name: "zero", Passed: true}},
+				"First": {{Passed: true}},
+			},
+		},
+		{
+			name:   "stubs",
+			code:   "func Add(a, b int) int { return 0 }\nfunc First(s []int) int { return 0 }\n",
+			passed: 1, total: 3,
+			results: map[string][]Case{
+				"Add":   {{Name: "small numbers", Message: "Add(1, 2) = 0. Expected 3"}, {Name: "zero", Passed: true}},
+				"First": {{Message: "First([7]) = 0. Expected 7"}},
+			},
+		},
+		{
+			name:   "missing",
+			code:   "func Add(a, b int) int { return a + b }\n",
+			passed: 0, total: 1,
+			results: map[string][]Case{"Add": nil, "First": nil},
+		},
+		{
+			name:  "build error",
+			code:  "func Add(a, b int) int { return a + \"b\" }\nfunc First(s []int) int { return 0 }\n",
+			build: "ex.go:3",
+		},
+		{
+			name:   "panic",
+			code:   "func Add(a, b int) int { var s []int; return s[a] }\nfunc First(s []int) int { return s[0] }\n",
+			passed: 0, total: 1,
+			results: map[string][]Case{"Add": {{Name: "small numbers"}}, "First": nil},
+		},
+		{
+			name:   "timeout",
+			code:   "func Add(a, b int) int { return a + b }\nfunc First(s []int) int { for {} }\n",
+			passed: 2, total: 3,
+			results: map[string][]Case{
+				"Add":   {{Name: "small numbers", Passed: true}, {Name: "zero", Passed: true}},
+				"First": {{Message: "test timed out after 3s: " + timeoutHint}},
+			},
+		},
+	}
+	saved := timeout
+	timeout = 3 * time.Second
+	t.Cleanup(func() { timeout = saved })
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			root, ex := exercise(t, test.code)
+			r, err := Grade(context.Background(), root, ex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.build != "" {
+				if !strings.Contains(r.Build, test.build) {
+					t.Errorf("Build is %q. Expected it to mention %s", r.Build, test.build)
+				}
+				return
+			}
+			if passed, total := r.Count(); passed != test.passed || total != test.total {
+				t.Errorf("Count returned %d, %d. Expected %d, %d", passed, total, test.passed, test.total)
+			}
+			for _, res := range r.Results {
+				expected := test.results[res.Func]
+				if test.name == "panic" && len(res.Cases) > 0 {
+					// Keep only the first line of the panic.
+					res.Cases[0].Message = ""
+				}
+				if !reflect.DeepEqual(res.Cases, expected) {
+					t.Errorf("%s: cases %+v. Expected %+v", res.Func, res.Cases, expected)
+				}
+			}
+			if test.name == "missing" && (r.Results[0].Missing || !r.Results[1].Missing) {
+				t.Errorf("Missing is %v, %v. Expected only First", r.Results[0].Missing, r.Results[1].Missing)
+			}
+		})
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	src := `{"Action":"run","Test":"TestA"}
+{"Action":"output","Test":"TestA","Output":"=== RUN   TestA\n"}
+{"Action":"run","Test":"TestA/x_y"}
+{"Action":"run","Test":"TestA/x_y/deep"}
+{"Action":"output","Test":"TestA/x_y/deep","Output":"    a_test.go:9: deep failure\n"}
+{"Action":"fail","Test":"TestA/x_y/deep"}
+{"Action":"output","Test":"TestA/x_y","Output":"--- FAIL: TestA/x_y (0.00s)\n"}
+{"Action":"fail","Test":"TestA/x_y"}
+{"Action":"fail","Test":"TestA"}
+{"Action":"build-output","Output":"# pkg\n"}
+not json
+`
+	cases, build, err := parseEvents(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]Case{"TestA": {{Name: "x y", Message: "deep failure"}}}
+	if !reflect.DeepEqual(cases, expected) {
+		t.Errorf("parseEvents returned %+v. Expected %+v", cases, expected)
+	}
+	if build != "# pkg\nnot json\n" {
+		t.Errorf("parseEvents returned build output %q", build)
+	}
+}
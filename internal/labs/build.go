@@ -0,0 +1,139 @@
+package labs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BuildOptions configures Build.
+type BuildOptions struct {
+	Jobs   int    // concurrent builds; defaults to the number of CPUs
+	OutDir string // where to keep binaries; empty discards them
+	Tags   string // passed to go build -tags
+	Go     string // go command; defaults to "go"
+}
+
+// Result is the outcome of building one lab.
+type Result struct {
+	Lab      Lab
+	Err      error
+	Output   string // compiler output, without go's "# package" headers
+	Duration time.Duration
+}
+
+// OK reports whether the lab built.
+func (r Result) OK() bool {
+	return r.Err == nil
+}
+
+// Build compiles each lab with go build, running at most opts.Jobs builds at
+// once. Results are returned in the order of labs; done, if not nil, is
+// called as each build finishes, from a single goroutine at a time.
+func Build(ctx context.Context, root string, labs []Lab, opts BuildOptions, done func(Result)) []Result {
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	goCmd := opts.Go
+	if goCmd == "" {
+		goCmd = "go"
+	}
+	outDir := opts.OutDir
+	if outDir == "" {
+		tmp, err := os.MkdirTemp("", "golabs-build-")
+		if err != nil {
+			return failAll(labs, err)
+		}
+		defer os.RemoveAll(tmp)
+		outDir = tmp
+	} else if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return failAll(labs, err)
+	}
+
+	results := make([]Result, len(labs))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i, lab := range labs {
+		wg.Add(1)
+		go func(i int, lab Lab) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			r := buildOne(ctx, root, lab, goCmd, opts.Tags, outDir)
+			results[i] = r
+			if done != nil {
+				mu.Lock()
+				done(r)
+				mu.Unlock()
+			}
+		}(i, lab)
+	}
+	wg.Wait()
+	return results
+}
+
+func buildOne(ctx context.Context, root string, lab Lab, goCmd, tags, outDir string) Result {
+	start := time.Now()
+	exe := filepath.Join(outDir, strings.ReplaceAll(strings.TrimSuffix(lab.Path(), ".go"), "/", "_"))
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	// Lab binaries are throwaway, so skip VCS stamping; it also fails in
+	// checkouts without git.
+	args := []string{"build", "-buildvcs=false", "-o", exe}
+	if tags != "" {
+		args = append(args, "-tags", tags)
+	}
+	args = append(args, lab.Target())
+
+	cmd := exec.CommandContext(ctx, goCmd, args...)
+	cmd.Dir = root
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	r := Result{Lab: lab, Output: cleanOutput(out.String()), Duration: time.Since(start)}
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			err = fmt.Errorf("go build failed (exit status %d)", exit.ExitCode())
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		r.Err = err
+	}
+	return r
+}
+
+// cleanOutput drops the "# import/path" lines go build prints before each
+// package's errors; the summary already names the lab.
+func cleanOutput(s string) string {
+	var kept []string
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		if strings.HasPrefix(line, "# ") || line == "" {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+func failAll(labs []Lab, err error) []Result {
+	results := make([]Result, len(labs))
+	for i, lab := range labs {
+		results[i] = Result{Lab: lab, Err: err}
+	}
+	return results
+}
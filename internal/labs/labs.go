@@ -0,0 +1,181 @@
+// Package labs finds the runnable labs of the repository, the directories
+// that hold a main package, and builds them. A directory whose files each
+// declare their own main function holds one lab per file instead, built and
+// run as a single file.
+package labs
+
+import (
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Lab is a directory holding a main package, or one file of a directory of
+// single-file programs.
+type Lab struct {
+	Dir     string   // slash-separated path relative to the module root
+	Chapter string   // first element of Dir, such as "06_maps"
+	Files   []string // Go source files, excluding tests
+	// File is set for a single-file program: the file, which is then also
+	// the only element of Files.
+	File string
+}
+
+// Path returns the lab's directory, or the path of its file for a
+// single-file program. It identifies the lab.
+func (l Lab) Path() string {
+	if l.File != "" {
+		return l.Dir + "/" + l.File
+	}
+	return l.Dir
+}
+
+// Target returns the argument that builds or runs the lab with go build or
+// go run from the module root, such as "./06_maps".
+func (l Lab) Target() string {
+	return "./" + l.Path()
+}
+
+// Name returns the last element of the lab's path, without ".go".
+func (l Lab) Name() string {
+	return strings.TrimSuffix(path.Base(l.Path()), ".go")
+}
+
+// ErrNoModule is returned by FindRoot outside a Go module.
+var ErrNoModule = errors.New("labs: no go.mod found in this directory or any parent")
+
+// FindRoot returns the nearest directory at or above dir that contains a
+// go.mod file.
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoModule
+		}
+		dir = parent
+	}
+}
+
+// skipDir reports directories that never contain labs: hidden ones, the go
+// tool's special names, and the repository's own tooling.
+func skipDir(rel, name string) bool {
+	switch {
+	case rel == ".":
+		return false
+	case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"):
+		return true
+	case name == "testdata" || name == "vendor":
+		return true
+	case rel == "cmd" || rel == "internal":
+		return true
+	}
+	return false
+}
+
+// Discover walks root and returns its labs in path order. Directories whose
+// files declare several packages are still returned, since they are labs
+// that fail to build; Build reports the error. A directory in which several
+// files declare func main yields a lab for each of those files.
+func Discover(root string) ([]Lab, error) {
+	var found []Lab
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if skipDir(filepath.ToSlash(rel), d.Name()) {
+			return filepath.SkipDir
+		}
+		if rel == "." {
+			return nil
+		}
+		found = append(found, inspect(p, filepath.ToSlash(rel))...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Path() < found[j].Path() })
+	return found, nil
+}
+
+// inspect returns the labs of dir: none, the directory's main package, or
+// its single-file programs.
+func inspect(dir, rel string) []Lab {
+	pkg, err := build.Default.ImportDir(dir, 0)
+	var multi *build.MultiplePackageError
+	switch {
+	case errors.As(err, &multi):
+		isMain := false
+		for _, name := range multi.Packages {
+			isMain = isMain || name == "main"
+		}
+		if !isMain {
+			return nil
+		}
+		return []Lab{newLab(rel, multi.Files)}
+	case err != nil || pkg.Name != "main":
+		return nil
+	}
+	files := append(append([]string(nil), pkg.GoFiles...), pkg.CgoFiles...)
+	if mains := mainFiles(dir, files); len(mains) > 1 {
+		var programs []Lab
+		for _, f := range mains {
+			lab := newLab(rel, []string{f})
+			lab.File = f
+			programs = append(programs, lab)
+		}
+		return programs
+	}
+	return []Lab{newLab(rel, files)}
+}
+
+// mainFiles returns the files that declare func main.
+func mainFiles(dir string, files []string) []string {
+	var mains []string
+	fset := token.NewFileSet()
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			// Let the compiler report it.
+			continue
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				mains = append(mains, name)
+				break
+			}
+		}
+	}
+	return mains
+}
+
+func newLab(rel string, files []string) Lab {
+	sort.Strings(files)
+	chapter := rel
+	if i := strings.IndexByte(rel, '/'); i >= 0 {
+		chapter = rel[:i]
+	}
+	return Lab{Dir: rel, Chapter: chapter, Files: files}
+}
@@ -0,0 +1,111 @@
+package labs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go-labs/internal/testutil"
+)
+
+var tree = map[string]string{
+	"go.mod":                       "module example.com/labs\n\ngo 1.19\n",
+	"01_intro/hello/hello.go":      "package main\n\nfunc main() {}\n",
+	"01_intro/hello/hello_test.go": "package main\n",
+	"02_maps/maps.go":              "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(1) }\n",
+	"02_maps/lib/lib.go":           "package lib\n",
+	"02_maps/lib/tool/main.go":     "package main\n\nfunc main() {}\n",
+	"03_broken/broken.go":          "package main\n\nfunc main() { missing() }\n",
+	"04_mixed/a.go":                "package main\n\nfunc main() {}\n",
+	"04_mixed/b.go":                "package other\n",
+	"05_programs/a.go":             "package main\n\nfunc main() {}\n",
+	"05_programs/b.go":             "package main\n\nfunc main() {}\n",
+	"05_programs/doc.go":           "// Package main holds two programs.\npackage main\n",
+	"cmd/tool/main.go":             "package main\n\nfunc main() {}\n",
+	"02_maps/testdata/x/main.go":   "package main\n",
+	".git/hooks/main.go":           "package main\n",
+	"docs/topics.md":               "# Topics\n",
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteTree(t, root, tree)
+
+	got, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Lab{
+		{Dir: "01_intro/hello", Chapter: "01_intro", Files: []string{"hello.go"}},
+		{Dir: "02_maps", Chapter: "02_maps", Files: []string{"maps.go"}},
+		{Dir: "02_maps/lib/tool", Chapter: "02_maps", Files: []string{"main.go"}},
+		{Dir: "03_broken", Chapter: "03_broken", Files: []string{"broken.go"}},
+		{Dir: "04_mixed", Chapter: "04_mixed", Files: []string{"a.go", "b.go"}},
+		{Dir: "05_programs", Chapter: "05_programs", Files: []string{"a.go"}, File: "a.go"},
+		{Dir: "05_programs", Chapter: "05_programs", Files: []string{"b.go"}, File: "b.go"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Discover returned\n%+v\nExpected\n%+v", got, expected)
+	}
+	if name := got[2].Name(); name != "tool" {
+		t.Errorf("Name() returned %q. Expected \"tool\"", name)
+	}
+	if p, name := got[6].Path(), got[6].Name(); p != "05_programs/b.go" || name != "b" {
+		t.Errorf("Path() and Name() returned %q, %q. Expected \"05_programs/b.go\", \"b\"", p, name)
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteTree(t, root, tree)
+	got, err := FindRoot(filepath.Join(root, "02_maps", "lib"))
+	if err != nil || got != root {
+		t.Errorf("FindRoot returned %q, %v. Expected %q", got, err, root)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	root := t.TempDir()
+	testutil.WriteTree(t, root, tree)
+	found, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "bin")
+	calls := 0
+	results := Build(context.Background(), root, found, BuildOptions{Jobs: 2, OutDir: out}, func(Result) { calls++ })
+	if calls != len(found) {
+		t.Errorf("done was called %d times. Expected %d", calls, len(found))
+	}
+	for i, r := range results {
+		if r.Lab.Path() != found[i].Path() {
+			t.Errorf("result %d is for %s. Expected %s", i, r.Lab.Path(), found[i].Path())
+		}
+		broken := strings.HasPrefix(r.Lab.Dir, "03_") || strings.HasPrefix(r.Lab.Dir, "04_")
+		if r.OK() == broken {
+			t.Errorf("%s: OK() is %v with output %q", r.Lab.Path(), r.OK(), r.Output)
+		}
+	}
+	if msg := results[3].Output; !strings.Contains(msg, "undefined: missing") || strings.Contains(msg, "# ") {
+		t.Errorf("03_broken output is %q. Expected the compiler error without the package header", msg)
+	}
+	exe := filepath.Join(out, "01_intro_hello")
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	if _, err := os.Stat(exe); err != nil {
+		t.Errorf("binary was not kept in OutDir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "05_programs_a"+filepath.Ext(exe))); err != nil {
+		t.Errorf("binary of a single-file program was not kept in OutDir: %v", err)
+	}
+}
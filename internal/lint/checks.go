@@ -0,0 +1,161 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// EmptyLab reports files that declare nothing and labs whose main function
+// is all there is and does nothing. A file holding the package doc comment,
+// like a doc.go, need not declare anything.
+var EmptyLab = &Analyzer{
+	Name: "emptylab",
+	Doc:  "report files without declarations and labs with an empty main",
+	Run: func(pass *Pass) {
+		decls := 0
+		var main *ast.FuncDecl
+		var mainFile *ast.File
+		for _, f := range pass.Files {
+			n := 0
+			for _, d := range f.Decls {
+				if g, ok := d.(*ast.GenDecl); ok && g.Tok == token.IMPORT {
+					continue
+				}
+				n++
+				if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+					main, mainFile = fn, f
+				}
+			}
+			if n == 0 && f.Doc == nil {
+				pass.Reportf(f.Name.Pos(), "%s declares nothing; remove it or write its example", pass.Filename(f.Pos()))
+			}
+			decls += n
+		}
+		if main != nil && decls == 1 && isEmpty(mainFile, main) {
+			pass.Reportf(main.Pos(), "lab %s does nothing: func main is empty", pass.Dir)
+		}
+	},
+}
+
+// isEmpty reports whether fn has neither statements nor comments, such as
+// instructions in an exercise stub.
+func isEmpty(f *ast.File, fn *ast.FuncDecl) bool {
+	if fn.Body == nil || len(fn.Body.List) > 0 {
+		return false
+	}
+	for _, cg := range f.Comments {
+		if cg.Pos() > fn.Body.Lbrace && cg.End() < fn.Body.Rbrace {
+			return false
+		}
+	}
+	return true
+}
+
+// headerRe matches a header comment naming a file, such as "// maps.go".
+var headerRe = regexp.MustCompile(`^[\w.-]+\.go$`)
+
+// numberPrefix matches the "01_" that orders the files of a lab.
+var numberPrefix = regexp.MustCompile(`^\d+_`)
+
+// Header reports "// name.go" header comments that name another file. The
+// header may leave out the file's number prefix.
+var Header = &Analyzer{
+	Name: "header",
+	Doc:  "report header comments that do not match the file name",
+	Run: func(pass *Pass) {
+		for _, f := range pass.Files {
+			name := pass.Filename(f.Pos())
+			for _, cg := range f.Comments {
+				if cg.Pos() > f.Package {
+					break
+				}
+				header := strings.TrimSpace(cg.Text())
+				if !headerRe.MatchString(header) {
+					continue
+				}
+				if header != name && header != numberPrefix.ReplaceAllString(name, "") {
+					pass.Reportf(cg.Pos(), "header comment names %s, but the file is %s", header, name)
+				}
+				break
+			}
+		}
+	},
+}
+
+// MainFunc reports func main outside package main, where it never runs.
+var MainFunc = &Analyzer{
+	Name: "mainfunc",
+	Doc:  "report func main in packages other than main",
+	Run: func(pass *Pass) {
+		for _, f := range pass.Files {
+			if f.Name.Name == "main" {
+				continue
+			}
+			for _, d := range f.Decls {
+				if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+					pass.Reportf(fn.Pos(), "func main in package %s never runs; only package main has an entry point", f.Name.Name)
+				}
+			}
+		}
+	},
+}
+
+// IgnoredError reports errors assigned to the blank identifier, as in
+// data, _ := json.Marshal(v).
+var IgnoredError = &Analyzer{
+	Name: "ignorederr",
+	Doc:  "report errors discarded by assigning them to _",
+	Run: func(pass *Pass) {
+		errType := types.Universe.Lookup("error").Type()
+		check := func(lhs []ast.Expr, rhs []ast.Expr) {
+			if len(rhs) != 1 {
+				return
+			}
+			call, ok := rhs[0].(*ast.CallExpr)
+			if !ok {
+				return
+			}
+			tuple, ok := pass.Info.Types[call].Type.(*types.Tuple)
+			if !ok || tuple.Len() != len(lhs) {
+				return
+			}
+			for i, e := range lhs {
+				if id, ok := e.(*ast.Ident); ok && id.Name == "_" && types.Identical(tuple.At(i).Type(), errType) {
+					pass.Reportf(id.Pos(), "error returned by %s is ignored", types.ExprString(call.Fun))
+				}
+			}
+		}
+		for _, f := range pass.Files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.AssignStmt:
+					check(n.Lhs, n.Rhs)
+				case *ast.ValueSpec:
+					lhs := make([]ast.Expr, len(n.Names))
+					for i, id := range n.Names {
+						lhs[i] = id
+					}
+					check(lhs, n.Values)
+				}
+				return true
+			})
+		}
+	},
+}
+
+// MissingTests reports library packages without tests. Labs, which are
+// main packages, are checked by their golden files instead.
+var MissingTests = &Analyzer{
+	Name: "tests",
+	Doc:  "report packages other than main without _test.go files",
+	Run: func(pass *Pass) {
+		f := pass.Files[0]
+		if f.Name.Name == "main" || len(pass.Tests) > 0 {
+			return
+		}
+		pass.Reportf(f.Name.Pos(), "package %s has no tests", f.Name.Name)
+	},
+}
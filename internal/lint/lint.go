@@ -0,0 +1,270 @@
+// Package lint checks the lab sources for the conventions of this
+// repository.
+//
+// Each check is an Analyzer in the style of golang.org/x/tools/go/analysis,
+// reduced to what the checks need: an analyzer's Run function receives a
+// Pass holding one package's syntax trees and type information, and reports
+// Diagnostics through it. The module's go directive predates the
+// x/tools versions that build with the current toolchain, so the package
+// carries this small driver of its own instead.
+//
+// A comment of the form
+//
+//	//lint:ignore CHECK[,CHECK...] REASON
+//
+// silences the named checks on its own line and the next one.
+//
+// Type information comes from the standard library's export data. Packages
+// of this module and its dependencies are not imported, so expressions that
+// use them have no type and checks skip them.
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// An Analyzer is a check run on every package.
+type Analyzer struct {
+	Name string // used in diagnostics and to select checks
+	Doc  string
+	Run  func(*Pass)
+}
+
+// A Pass is the application of an analyzer to a package.
+type Pass struct {
+	Analyzer *Analyzer
+	Fset     *token.FileSet
+	Dir      string      // package directory, slash-separated, relative to the root
+	Files    []*ast.File // the package's files, without tests
+	Tests    []string    // names of the directory's _test.go files
+	Info     *types.Info // possibly incomplete; see the package doc
+
+	diagnostics *[]Diagnostic
+}
+
+// Reportf reports a problem at pos.
+func (p *Pass) Reportf(pos token.Pos, format string, args ...any) {
+	*p.diagnostics = append(*p.diagnostics, Diagnostic{
+		Pos:      p.Fset.Position(pos),
+		Message:  fmt.Sprintf(format, args...),
+		Analyzer: p.Analyzer.Name,
+	})
+}
+
+// Filename returns the name of the file holding pos, without its directory.
+func (p *Pass) Filename(pos token.Pos) string {
+	return filepath.Base(p.Fset.Position(pos).Filename)
+}
+
+// A Diagnostic is a problem found by an analyzer.
+type Diagnostic struct {
+	Pos      token.Position // Filename is relative to the root
+	Message  string
+	Analyzer string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s (%s)", d.Pos, d.Message, d.Analyzer)
+}
+
+// Analyzers are all the checks, in the order they run.
+var Analyzers = []*Analyzer{EmptyLab, Header, MainFunc, IgnoredError, MissingTests}
+
+// Skipped are the directories of the root that Run leaves out unless asked
+// for: the course tooling rather than the labs.
+var Skipped = []string{"cmd", "internal"}
+
+// Run applies the analyzers to the packages in dirs and their
+// subdirectories, or to the whole tree under root if dirs is empty, and
+// returns the diagnostics sorted by position.
+func Run(root string, analyzers []*Analyzer, dirs ...string) ([]Diagnostic, error) {
+	skipTop := len(dirs) == 0
+	if skipTop {
+		dirs = []string{root}
+	}
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "gc", nil)
+	var diagnostics []Diagnostic
+	for _, top := range dirs {
+		err := filepath.WalkDir(top, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if path != top && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			if skipTop && filepath.Dir(path) == filepath.Clean(root) {
+				for _, s := range Skipped {
+					if name == s {
+						return filepath.SkipDir
+					}
+				}
+			}
+			pkgs, err := parseDir(fset, root, path)
+			if err != nil {
+				return err
+			}
+			for _, pkg := range pkgs {
+				info := check(fset, imp, pkg.files)
+				var found []Diagnostic
+				for _, a := range analyzers {
+					a.Run(&Pass{
+						Analyzer:    a,
+						Fset:        fset,
+						Dir:         pkg.dir,
+						Files:       pkg.files,
+						Tests:       pkg.tests,
+						Info:        info,
+						diagnostics: &found,
+					})
+				}
+				ignored := ignores(fset, pkg.files)
+				for _, d := range found {
+					if !ignored[ignoreKey{d.Pos.Filename, d.Pos.Line, d.Analyzer}] {
+						diagnostics = append(diagnostics, d)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Pos, diagnostics[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return diagnostics, nil
+}
+
+// Select returns the analyzers named in a comma-separated list.
+func Select(names string) ([]*Analyzer, error) {
+	var selected []*Analyzer
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, a := range Analyzers {
+			if a.Name == name {
+				selected = append(selected, a)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no check named %q", name)
+		}
+	}
+	return selected, nil
+}
+
+type pkg struct {
+	dir   string
+	files []*ast.File
+	tests []string
+}
+
+// parseDir parses the Go files of a directory into one package per package
+// clause, with file names relative to root.
+func parseDir(fset *token.FileSet, root, dir string) ([]*pkg, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	rel = filepath.ToSlash(rel)
+	var pkgs []*pkg
+	byName := make(map[string]*pkg)
+	var tests []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			tests = append(tests, name)
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, filepath.ToSlash(filepath.Join(rel, name)), src, parser.ParseComments)
+		if err != nil {
+			// Syntax errors are the compiler's to report.
+			continue
+		}
+		p := byName[f.Name.Name]
+		if p == nil {
+			p = &pkg{dir: rel}
+			byName[f.Name.Name] = p
+			pkgs = append(pkgs, p)
+		}
+		p.files = append(p.files, f)
+	}
+	for _, p := range pkgs {
+		p.tests = tests
+	}
+	return pkgs, nil
+}
+
+type ignoreKey struct {
+	file     string
+	line     int
+	analyzer string
+}
+
+var ignoreRe = regexp.MustCompile(`^//lint:ignore (\S+)`)
+
+// ignores returns the lines and checks silenced by //lint:ignore comments.
+func ignores(fset *token.FileSet, files []*ast.File) map[ignoreKey]bool {
+	ignored := make(map[ignoreKey]bool)
+	for _, f := range files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				m := ignoreRe.FindStringSubmatch(c.Text)
+				if m == nil {
+					continue
+				}
+				pos := fset.Position(c.Pos())
+				for _, name := range strings.Split(m[1], ",") {
+					ignored[ignoreKey{pos.Filename, pos.Line, name}] = true
+					ignored[ignoreKey{pos.Filename, pos.Line + 1, name}] = true
+				}
+			}
+		}
+	}
+	return ignored
+}
+
+// check type-checks files as far as it can, ignoring errors.
+func check(fset *token.FileSet, imp types.Importer, files []*ast.File) *types.Info {
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+		Defs:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: imp, Error: func(error) {}}
+	conf.Check(files[0].Name.Name, fset, files, info)
+	return info
+}
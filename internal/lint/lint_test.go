@@ -0,0 +1,93 @@
+package lint
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-labs/internal/testutil"
+)
+
+var tree = map[string]string{
+	"go.mod": "module example.com/labs\n\ngo 1.19\n",
+
+	"01_empty/01_empty.go": "package main\n\nfunc main() {\n\n}\n",
+	"02_stub/stub.go":      "package main\n\nfunc main() {\n\t// Print the sum here.\n}\n",
+	"03_blank/01_a.go":     "package main\n\nfunc main() { println() }\n",
+	"03_blank/02_b.go":     "package main\n",
+
+	"04_headers/01_maps.go":    "// maps.go\n\npackage main\n\nfunc main() { println() }\n",
+	"04_headers/02_more.go":    "// 02_more.go\npackage main\n\nfunc more() {}\n",
+	"04_headers/03_other.go":   "// examples.go\npackage main\n\nfunc other() {}\n",
+	"04_headers/04_doc.go":     "// Not a header.go file name.\npackage main\n\nfunc doc() {}\n",
+	"04_headers/05_ignored.go": "//lint:ignore header kept as in the book\n// book.go\npackage main\n\nfunc book() {}\n",
+
+	"05_errors/errors.go": `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+func main() {
+	data, _ := json.Marshal(1)
+	var n, _ = strconv.Atoi("1")
+	_, _ = fmt.Println(string(data), n)
+	m, _ := strconv.Atoi("2") //lint:ignore ignorederr "2" is a number
+	v, _ := map[string]int{}["x"]
+	println(m, v)
+}
+`,
+
+	"util/doc.go":            "// Package util helps.\npackage util\n",
+	"util/util.go":           "package util\n\nfunc main() {}\n\n// Helper helps.\nfunc Helper() {}\n",
+	"tested/tested.go":       "package tested\n\nfunc F() {}\n",
+	"tested/f_test.go":       "package tested\n",
+	"cmd/tool/main.go":       "package main\n\nfunc main() {}\n",
+	"01_empty/testdata/x.go": "package main\n",
+}
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteTree(t, root, tree)
+	diagnostics, err := Run(root, Analyzers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, d.String())
+	}
+	expected := []string{
+		"01_empty/01_empty.go:3:1: lab 01_empty does nothing: func main is empty (emptylab)",
+		"03_blank/02_b.go:1:9: 02_b.go declares nothing; remove it or write its example (emptylab)",
+		"04_headers/03_other.go:1:1: header comment names examples.go, but the file is 03_other.go (header)",
+		"05_errors/errors.go:10:8: error returned by json.Marshal is ignored (ignorederr)",
+		"05_errors/errors.go:11:9: error returned by strconv.Atoi is ignored (ignorederr)",
+		"05_errors/errors.go:12:5: error returned by fmt.Println is ignored (ignorederr)",
+		"util/doc.go:2:9: package util has no tests (tests)",
+		"util/util.go:3:1: func main in package util never runs; only package main has an entry point (mainfunc)",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Run returned\n%q\nExpected\n%q", got, expected)
+	}
+
+	diagnostics, err = Run(root, []*Analyzer{MainFunc}, filepath.Join(root, "cmd"), filepath.Join(root, "util"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Pos.Filename != "util/util.go" {
+		t.Errorf("Run on cmd and util returned %v. Expected only the main of util", diagnostics)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	analyzers, err := Select("header, tests")
+	if err != nil || len(analyzers) != 2 || analyzers[0] != Header || analyzers[1] != MissingTests {
+		t.Errorf("Select(\"header, tests\") returned %v, %v", analyzers, err)
+	}
+	if _, err := Select("nosuch"); err == nil {
+		t.Errorf("Select(\"nosuch\") returned no error")
+	}
+}
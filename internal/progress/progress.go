@@ -0,0 +1,217 @@
+// Package progress records what a learner has done: the labs they have run
+// and their answers to quiz questions. Questions are scheduled for review
+// with the SM-2 algorithm, so that those answered wrongly come back soon and
+// those answered well come back at growing intervals.
+//
+// Progress is kept in a JSON file, by default ~/.golabs_progress.json or the
+// file named by GOLABS_PROGRESS.
+package progress
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Env names the environment variable that overrides DefaultPath.
+const Env = "GOLABS_PROGRESS"
+
+// DefaultPath returns the progress file of the current user.
+func DefaultPath() string {
+	if p := os.Getenv(Env); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".golabs_progress.json"
+	}
+	return filepath.Join(home, ".golabs_progress.json")
+}
+
+// Progress is everything recorded for one learner.
+type Progress struct {
+	Labs      map[string]*Lab  `json:"labs"`      // by lab directory
+	Questions map[string]*Card `json:"questions"` // by QuestionID
+}
+
+// Lab records the runs of a lab.
+type Lab struct {
+	Runs      int       `json:"runs"`
+	LastRun   time.Time `json:"last_run"`
+	Completed time.Time `json:"completed,omitempty"` // first successful run
+}
+
+// Done reports whether the lab has run successfully.
+func (l *Lab) Done() bool {
+	return l != nil && !l.Completed.IsZero()
+}
+
+// New returns empty progress.
+func New() *Progress {
+	return &Progress{Labs: make(map[string]*Lab), Questions: make(map[string]*Card)}
+}
+
+// Load reads a progress file. A missing file is empty progress.
+func Load(path string) (*Progress, error) {
+	p := New()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	if p.Labs == nil {
+		p.Labs = make(map[string]*Lab)
+	}
+	if p.Questions == nil {
+		p.Questions = make(map[string]*Card)
+	}
+	return p, nil
+}
+
+// Save writes p to path, replacing the file only once it is complete.
+func (p *Progress) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RecordRun records a run of the lab in dir; a successful run completes it.
+func (p *Progress) RecordRun(dir string, ok bool, now time.Time) {
+	l := p.Labs[dir]
+	if l == nil {
+		l = &Lab{}
+		p.Labs[dir] = l
+	}
+	l.Runs++
+	l.LastRun = now
+	if ok && l.Completed.IsZero() {
+		l.Completed = now
+	}
+}
+
+// QuestionID identifies a question by its quiz file and content, so that
+// renumbering or reordering questions keeps their history.
+func QuestionID(quizFile, text, body string) string {
+	sum := sha1.Sum([]byte(text + "\n" + body))
+	return quizFile + "#" + hex.EncodeToString(sum[:6])
+}
+
+// Card is the review schedule of a question.
+type Card struct {
+	Reps     int       `json:"reps"`     // correct answers in a row
+	Interval int       `json:"interval"` // days until the next review
+	Ease     float64   `json:"ease"`     // SM-2 easiness factor, at least 1.3
+	Due      time.Time `json:"due"`
+	History  []Review  `json:"history"`
+}
+
+// Review is one answer to a question.
+type Review struct {
+	Time    time.Time `json:"time"`
+	Correct bool      `json:"correct"`
+}
+
+// Grades of the SM-2 algorithm for a right and a wrong answer, on its scale
+// from 0 (blackout) to 5 (perfect).
+const (
+	GradeCorrect = 4
+	GradeWrong   = 1
+)
+
+// Answer records an answer to the question id and schedules its next review.
+func (p *Progress) Answer(id string, correct bool, now time.Time) *Card {
+	c := p.Questions[id]
+	if c == nil {
+		c = &Card{Ease: 2.5}
+		p.Questions[id] = c
+	}
+	grade := GradeWrong
+	if correct {
+		grade = GradeCorrect
+	}
+	c.review(grade, now)
+	c.History = append(c.History, Review{Time: now, Correct: correct})
+	return c
+}
+
+// review applies SM-2: a grade of 3 or more lengthens the interval from 1 to
+// 6 days and then by the easiness factor; a lower grade starts over. The
+// factor itself moves with each grade.
+func (c *Card) review(grade int, now time.Time) {
+	if grade >= 3 {
+		switch c.Reps {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Reps++
+	} else {
+		c.Reps = 0
+		c.Interval = 1
+	}
+	q := float64(5 - grade)
+	c.Ease += 0.1 - q*(0.08+q*0.02)
+	if c.Ease < 1.3 {
+		c.Ease = 1.3
+	}
+	c.Due = now.AddDate(0, 0, c.Interval)
+}
+
+// Learned reports whether the question was last answered correctly.
+func (c *Card) Learned() bool {
+	return c != nil && c.Reps > 0
+}
+
+// Due returns the IDs of the questions due for review at now, most overdue
+// first.
+func (p *Progress) Due(now time.Time) []string {
+	var ids []string
+	for id, c := range p.Questions {
+		if !c.Due.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := p.Questions[ids[i]], p.Questions[ids[j]]
+		if !a.Due.Equal(b.Due) {
+			return a.Due.Before(b.Due)
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// NextDue returns when the next question is due, or false if none has been
+// answered.
+func (p *Progress) NextDue() (time.Time, bool) {
+	var next time.Time
+	for _, c := range p.Questions {
+		if next.IsZero() || c.Due.Before(next) {
+			next = c.Due
+		}
+	}
+	return next, !next.IsZero()
+}
@@ -0,0 +1,200 @@
+package progress
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestAnswer(t *testing.T) {
+	tests := []struct {
+		answers  []bool
+		reps     int
+		interval int
+		ease     float64
+	}{
+		{[]bool{true}, 1, 1, 2.5},
+		{[]bool{true, true}, 2, 6, 2.5},
+		{[]bool{true, true, true}, 3, 15, 2.5},
+		{[]bool{true, true, false}, 0, 1, 1.96},
+		{[]bool{false, false, false}, 0, 1, 1.3},
+		{[]bool{false, true, true, true}, 3, 12, 1.96},
+	}
+	for _, test := range tests {
+		p := New()
+		var c *Card
+		for i, correct := range test.answers {
+			c = p.Answer("q", correct, now.AddDate(0, 0, i))
+		}
+		if c.Reps != test.reps || c.Interval != test.interval || !near(c.Ease, test.ease) {
+			t.Errorf("%v: Answer returned reps %d, interval %d, ease %.2f. Expected %d, %d, %.2f",
+				test.answers, c.Reps, c.Interval, c.Ease, test.reps, test.interval, test.ease)
+		}
+		last := now.AddDate(0, 0, len(test.answers)-1)
+		if !c.Due.Equal(last.AddDate(0, 0, test.interval)) {
+			t.Errorf("%v: due %v. Expected %d days after the last answer", test.answers, c.Due, test.interval)
+		}
+		if len(c.History) != len(test.answers) {
+			t.Errorf("%v: history has %d reviews", test.answers, len(c.History))
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return a-b < 1e-9 && b-a < 1e-9
+}
+
+func TestDue(t *testing.T) {
+	p := New()
+	p.Answer("late", false, now.AddDate(0, 0, -5))
+	p.Answer("later", false, now.AddDate(0, 0, -3))
+	p.Answer("learned", true, now)
+	if ids := p.Due(now); !reflect.DeepEqual(ids, []string{"late", "later"}) {
+		t.Errorf("Due returned %v. Expected [late later]", ids)
+	}
+	if next, ok := p.NextDue(); !ok || !next.Equal(now.AddDate(0, 0, -4)) {
+		t.Errorf("NextDue returned %v, %v", next, ok)
+	}
+	if !p.Questions["learned"].Learned() || p.Questions["late"].Learned() {
+		t.Errorf("Learned does not follow the last answer")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "progress.json")
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of a missing file: %v", err)
+	}
+	if len(p.Labs) != 0 || len(p.Questions) != 0 {
+		t.Fatalf("Load of a missing file returned %+v. Expected empty progress", p)
+	}
+	p.RecordRun("06_maps", false, now)
+	p.RecordRun("06_maps", true, now.Add(time.Hour))
+	p.RecordRun("06_maps", true, now.Add(2*time.Hour))
+	p.RecordRun("11_pointers", false, now)
+	p.Answer(QuestionID("quiz.md", "Why?", ""), true, now)
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("Load after Save returned\n%+v\nExpected\n%+v", got, p)
+	}
+	maps := got.Labs["06_maps"]
+	if maps.Runs != 3 || !maps.Completed.Equal(now.Add(time.Hour)) {
+		t.Errorf("06_maps has %d runs, completed %v. Expected 3, at the first success", maps.Runs, maps.Completed)
+	}
+	if got.Labs["11_pointers"].Done() || got.Labs["missing"].Done() {
+		t.Errorf("Done is true for a lab that never ran successfully")
+	}
+}
+
+func TestQuestionID(t *testing.T) {
+	a := QuestionID("a/quiz.md", "Why?", "")
+	if a != QuestionID("a/quiz.md", "Why?", "") {
+		t.Errorf("QuestionID is not stable")
+	}
+	if !strings.HasPrefix(a, "a/quiz.md#") {
+		t.Errorf("QuestionID returned %q. Expected the file as prefix", a)
+	}
+	if a == QuestionID("a/quiz.md", "Why?", "x := 1") || a == QuestionID("b/quiz.md", "Why?", "") {
+		t.Errorf("QuestionID does not tell questions apart")
+	}
+}
+
+const topics = `# Topics
+
+## 1. Introduction to Go
+
+- History and Philosophy of Go
+- Hello World Program
+
+## 2. Basics of Go
+
+- Basic Syntax and Types
+- Control Structures (if, else, switch, for)
+
+## 3. Advanced Data Types
+
+- Arrays and Slices
+- Maps
+- Pointers
+`
+
+func TestParseTopics(t *testing.T) {
+	chapters, err := ParseTopics(strings.NewReader(topics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ch := range chapters {
+		got = append(got, ch.Title)
+		for _, tp := range ch.Topics {
+			got = append(got, "  "+tp.Title)
+		}
+	}
+	expected := []string{
+		"Introduction to Go", "  History and Philosophy of Go", "  Hello World Program",
+		"Basics of Go", "  Basic Syntax and Types", "  Control Structures (if, else, switch, for)",
+		"Advanced Data Types", "  Arrays and Slices", "  Maps", "  Pointers",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseTopics returned\n%q\nExpected\n%q", got, expected)
+	}
+}
+
+func TestCover(t *testing.T) {
+	chapters, err := ParseTopics(strings.NewReader(topics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unplaced := Cover(chapters, []Item{
+		{Path: "06_maps", Done: true},
+		{Path: "06_maps/kvstore/kvtool"},
+		{Path: "05_arrays_and_slices/01_declaration_and_initialization/slices", Done: true},
+		{Path: "03_built-in_nonprimitive_types/02_slices/basic_slices"},
+		{Path: "09_control_structures/05_for_construct"},
+		{Path: "02_basic_constructs_and_elementary_data_types/03_overview_of_data_types/strings"},
+		{Path: "11_pointers/quiz.md", Question: true, Done: true},
+		{Path: "01_introduction-to-go/04_quiz.md", Question: true},
+		{Path: "12_concurrency"},
+	})
+	if len(unplaced) != 1 || unplaced[0].Path != "12_concurrency" {
+		t.Errorf("Cover left %v unplaced. Expected only 12_concurrency", unplaced)
+	}
+	tests := []struct {
+		chapter, topic  int // topic -1 for the chapter itself
+		labs, questions Count
+	}{
+		{0, -1, Count{}, Count{0, 1}},
+		{0, 0, Count{}, Count{}},
+		{1, 0, Count{0, 1}, Count{}},
+		{1, 1, Count{0, 1}, Count{}},
+		{2, -1, Count{2, 4}, Count{1, 1}},
+		{2, 0, Count{1, 2}, Count{}},
+		{2, 1, Count{1, 2}, Count{}},
+		{2, 2, Count{}, Count{1, 1}},
+	}
+	for _, test := range tests {
+		ch := chapters[test.chapter]
+		name, labs, questions := ch.Title, ch.Labs, ch.Questions
+		if test.topic >= 0 {
+			tp := ch.Topics[test.topic]
+			name, labs, questions = tp.Title, tp.Labs, tp.Questions
+		}
+		if labs != test.labs || questions != test.questions {
+			t.Errorf("%s: labs %v, questions %v. Expected %v, %v", name, labs, questions, test.labs, test.questions)
+		}
+	}
+	if pct := Percent(chapters[2].Labs, chapters[2].Questions); pct != 60 {
+		t.Errorf("Percent of chapter 3 is %d. Expected 60", pct)
+	}
+}
@@ -0,0 +1,221 @@
+package progress
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Chapter is a numbered chapter of docs/topics.md with its coverage.
+type Chapter struct {
+	Number int
+	Title  string
+	Topics []Topic
+	// Labs and Questions count everything placed in the chapter, in one of
+	// its topics or only in the chapter itself.
+	Labs, Questions Count
+	keywords        []string
+}
+
+// Topic is a bullet of a chapter.
+type Topic struct {
+	Title           string
+	Labs, Questions Count
+	keywords        []string
+}
+
+// Count is how many of some items are done.
+type Count struct {
+	Done, Total int
+}
+
+func (c *Count) add(done bool) {
+	c.Total++
+	if done {
+		c.Done++
+	}
+}
+
+// Percent returns the done share of c and of d together, rounded.
+func Percent(c, d Count) int {
+	total := c.Total + d.Total
+	if total == 0 {
+		return 0
+	}
+	return (200*(c.Done+d.Done) + total) / (2 * total)
+}
+
+var chapterRe = regexp.MustCompile(`^##\s+(\d+)\.\s*(.+)$`)
+
+// ParseTopics reads the chapters of docs/topics.md: "## N. Title" headings
+// followed by "- Topic" bullets.
+func ParseTopics(r io.Reader) ([]Chapter, error) {
+	var chapters []Chapter
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if m := chapterRe.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			title := strings.TrimSpace(m[2])
+			chapters = append(chapters, Chapter{Number: n, Title: title, keywords: keywords(title)})
+			continue
+		}
+		if len(chapters) > 0 && (strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ")) {
+			ch := &chapters[len(chapters)-1]
+			title := strings.TrimSpace(line[2:])
+			ch.Topics = append(ch.Topics, Topic{Title: title, keywords: keywords(title)})
+		}
+	}
+	return chapters, sc.Err()
+}
+
+// Item is a lab or a quiz question to place among the topics.
+type Item struct {
+	Path     string // lab directory or quiz file, slash-separated
+	Question bool
+	Done     bool
+}
+
+// Cover places each item as Place does and counts it in its chapter and
+// topic. It returns the items that match nothing.
+func Cover(chapters []Chapter, items []Item) (unplaced []Item) {
+	for _, it := range items {
+		c, t := Place(chapters, it.Path)
+		if c < 0 {
+			unplaced = append(unplaced, it)
+			continue
+		}
+		ch := &chapters[c]
+		if it.Question {
+			ch.Questions.add(it.Done)
+		} else {
+			ch.Labs.add(it.Done)
+		}
+		if t >= 0 {
+			topic := &ch.Topics[t]
+			if it.Question {
+				topic.Questions.add(it.Done)
+			} else {
+				topic.Labs.add(it.Done)
+			}
+		}
+	}
+	return unplaced
+}
+
+// Place returns the indexes of the chapter and the topic whose title
+// shares the largest part of its words with path. When several topics share
+// as much, the one matching a deeper path element wins, and then the first.
+// A path holding every word of a chapter title, and no topic as fully,
+// belongs to that chapter only, as does a path matching no topic at all:
+// topic is then -1. Both are -1 when path matches nothing.
+func Place(chapters []Chapter, path string) (chapter, topic int) {
+	words := pathWords(path)
+	chapter, topic = -1, -1
+	var best score
+	for c := range chapters {
+		for t := range chapters[c].Topics {
+			if s := match(chapters[c].Topics[t].keywords, words); s.better(best) {
+				best, chapter, topic = s, c, t
+			}
+		}
+	}
+	for c := range chapters {
+		s := match(chapters[c].keywords, words)
+		if (chapter < 0 || s.frac == 1) && s.better(best) {
+			best, chapter, topic = s, c, -1
+		}
+	}
+	return chapter, topic
+}
+
+type score struct {
+	frac  float64 // share of the keywords found
+	depth int     // deepest path element holding one
+}
+
+func (s score) better(than score) bool {
+	if s.frac != than.frac {
+		return s.frac > than.frac
+	}
+	return s.depth > than.depth
+}
+
+func match(keywords []string, words map[string]int) score {
+	if len(keywords) == 0 {
+		return score{}
+	}
+	var s score
+	found := 0
+	for _, k := range keywords {
+		if d, ok := words[k]; ok {
+			found++
+			if d > s.depth {
+				s.depth = d
+			}
+		}
+	}
+	s.frac = float64(found) / float64(len(keywords))
+	return s
+}
+
+// pathWords returns the words of a path, each with the depth, from 1, of
+// the deepest element it appears in.
+func pathWords(path string) map[string]int {
+	words := make(map[string]int)
+	for i, elem := range strings.Split(strings.TrimSuffix(path, ".md"), "/") {
+		for _, w := range split(elem) {
+			words[w] = i + 1
+		}
+	}
+	return words
+}
+
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "to": true,
+	"in": true, "with": true, "for": true, "from": true, "its": true, "on": true,
+	"go": true, "golang": true, "basic": true, "using": true, "working": true,
+	"writing": true, "reading": true, "understanding": true, "popular": true,
+}
+
+// keywords returns the significant words of a title, leaving out
+// parenthesised examples such as "(if, else, switch, for)".
+func keywords(title string) []string {
+	for {
+		i := strings.Index(title, "(")
+		j := strings.Index(title, ")")
+		if i < 0 || j < i {
+			break
+		}
+		title = title[:i] + title[j+1:]
+	}
+	return split(title)
+}
+
+// split breaks s into lower-case singular words, without stopwords.
+func split(s string) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z')
+	}) {
+		w = stem(w)
+		if !stopwords[w] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// stem makes a plural singular: "libraries" becomes "library" and "maps"
+// becomes "map".
+func stem(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y"
+	case len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+		return w[:len(w)-1]
+	}
+	return w
+}
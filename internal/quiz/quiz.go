@@ -0,0 +1,495 @@
+// Package quiz reads the Markdown quizzes of the course and lets them be
+// taken in a terminal. A quiz file looks like this:
+//
+//	# Quiz: Golang Introduction
+//
+//	### 1. Which features are missing from Go?
+//
+//	**A**. Functions\
+//	**B**. Operator overloading
+//
+//	> **Answer**: **B**. Operator overloading
+//	> Go leaves it out to keep code explicit.
+//
+// Text between a question's heading and its options, such as a code block,
+// belongs to the question. Blockquote lines after the answer explain it.
+//
+// A multi-select question lists all its correct letters, as in
+// "> **Answer**: **A**, **C**". A question without options is answered in
+// free text: its answer line holds the accepted answer, or several separated
+// by "|", as in "> **Answer**: `G0G`". Free-text answers are compared
+// ignoring case, surrounding backticks and repeated spaces.
+//
+// Parse and Validate check the format and report every problem with its
+// line: missing or extra options and answers, answers that name no option
+// or disagree with its text, duplicate questions, and misnumbered headings.
+package quiz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Quiz is a parsed quiz file.
+type Quiz struct {
+	Title     string
+	Intro     string // text before the first question
+	Questions []Question
+}
+
+// Kind is the way a question is answered.
+type Kind int
+
+const (
+	SingleChoice Kind = iota
+	MultipleChoice
+	FreeText
+)
+
+// Question is one quiz question.
+type Question struct {
+	Line    int    // line of the question's heading
+	Text    string // the heading, without its number
+	Body    string // Markdown between the heading and the options
+	Options []Option
+	// Answers holds the letters of the correct options, several for a
+	// multi-select question, or the accepted answers of a free-text one.
+	Answers     []string
+	Explanation string
+}
+
+// Option is a lettered choice.
+type Option struct {
+	Letter string
+	Text   string
+}
+
+// Kind reports how q is answered.
+func (q Question) Kind() Kind {
+	switch {
+	case len(q.Options) == 0:
+		return FreeText
+	case len(q.Answers) > 1:
+		return MultipleChoice
+	}
+	return SingleChoice
+}
+
+// Option returns the option with the given letter.
+func (q Question) Option(letter string) (Option, bool) {
+	for _, o := range q.Options {
+		if strings.EqualFold(o.Letter, letter) {
+			return o, true
+		}
+	}
+	return Option{}, false
+}
+
+// Valid reports whether answer is a well-formed reply to q: one option
+// letter, a set of option letters for a multi-select question, or any text.
+func (q Question) Valid(answer string) bool {
+	switch q.Kind() {
+	case FreeText:
+		return normalize(answer) != ""
+	case MultipleChoice:
+		letters := selection(answer)
+		for _, l := range letters {
+			if _, ok := q.Option(l); !ok {
+				return false
+			}
+		}
+		return len(letters) > 0
+	}
+	_, ok := q.Option(strings.TrimSpace(answer))
+	return ok
+}
+
+// Correct reports whether answer is right. Letters may be in either case;
+// those of a multi-select question may be given in any order, as "A, C",
+// "a c" or "ac".
+func (q Question) Correct(answer string) bool {
+	switch q.Kind() {
+	case FreeText:
+		for _, a := range q.Answers {
+			if normalize(a) == normalize(answer) {
+				return true
+			}
+		}
+		return false
+	case MultipleChoice:
+		got := selection(answer)
+		sort.Strings(got)
+		want := append([]string(nil), q.Answers...)
+		sort.Strings(want)
+		return strings.Join(got, ",") == strings.Join(want, ",")
+	}
+	return len(q.Answers) == 1 && strings.EqualFold(strings.TrimSpace(answer), q.Answers[0])
+}
+
+// AnswerText describes the correct answer, such as "B. Operator
+// overloading".
+func (q Question) AnswerText() string {
+	if q.Kind() == FreeText {
+		return strings.Join(q.Answers, " or ")
+	}
+	parts := make([]string, len(q.Answers))
+	for i, l := range q.Answers {
+		o, _ := q.Option(l)
+		parts[i] = l + ". " + o.Text
+	}
+	return strings.Join(parts, "; ")
+}
+
+// selection splits a multi-select reply into upper-case letters. "ac" is
+// read as A and C.
+func selection(answer string) []string {
+	fields := strings.FieldsFunc(strings.ToUpper(answer), func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';' || r == '.' || r == '&'
+	})
+	var out []string
+	for _, f := range fields {
+		switch {
+		case f == "AND":
+		case len(fields) == 1:
+			out = append(out, strings.Split(f, "")...)
+		default:
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// normalize prepares free text for comparison.
+func normalize(s string) string {
+	s = strings.Trim(strings.TrimSpace(s), "`")
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Error is a problem in a quiz file.
+type Error struct {
+	File string // empty when parsing a reader
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Errors lists the problems of a quiz file by line.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+var (
+	questionRe = regexp.MustCompile(`^###\s+(?:(\d+)[.)]\s*)?(.+)$`)
+	optionRe   = regexp.MustCompile(`^\*\*([A-Z])\*\*[.)]?\s*(.*?)\s*\\?$`)
+	answerRe   = regexp.MustCompile(`^>\s*\*\*Answer\*\*:?\s*(.*)$`)
+	letterRe   = regexp.MustCompile(`^(?:\*\*([A-Z])\*\*|([A-Z])\b)`)
+	boldRe     = regexp.MustCompile(`^\*\*[A-Z]\*\*`)
+	joinRe     = regexp.MustCompile(`^\s*(?:,|and\b|&)\s*`)
+	explainRe  = regexp.MustCompile(`^\*\*Explanation\*\*:?\s*`)
+)
+
+// Parse reads a quiz. If the quiz has problems, it returns them all as
+// Errors.
+func Parse(r io.Reader) (*Quiz, error) {
+	q, errs := parse(r)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return q, nil
+}
+
+// Validate returns the problems of a quiz, or nil if there are none.
+func Validate(r io.Reader) Errors {
+	_, errs := parse(r)
+	return errs
+}
+
+// ParseFile reads a quiz file. Its problems are reported as
+// "path:line: msg".
+func ParseFile(path string) (*Quiz, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	q, errs := parse(f)
+	if len(errs) > 0 {
+		for _, e := range errs {
+			e.File = path
+		}
+		return nil, errs
+	}
+	return q, nil
+}
+
+// parser holds the state of parse.
+type parser struct {
+	quiz  Quiz
+	errs  Errors
+	intro []string
+	seen  map[string]int // line of each question, by content
+
+	cur        *Question
+	number     int // number of the last question heading
+	body       []string
+	optLines   []int
+	answerLine int  // 0 until the answer is read
+	boldAnswer bool // the answer names a letter in bold
+	inAnswer   bool // within the answer's blockquote
+}
+
+func (p *parser) errorf(line int, format string, args ...any) {
+	p.errs = append(p.errs, &Error{Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+func parse(r io.Reader) (*Quiz, Errors) {
+	p := &parser{seen: make(map[string]int)}
+	fence := 0 // line of the open code fence
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		isFence := strings.HasPrefix(line, "```")
+		if isFence {
+			if fence == 0 {
+				fence = n
+			} else {
+				fence = 0
+			}
+		}
+		if fence != 0 || isFence {
+			if p.cur == nil {
+				p.intro = append(p.intro, line)
+			} else if len(p.cur.Options) == 0 && p.answerLine == 0 {
+				p.body = append(p.body, line)
+			}
+			continue
+		}
+		p.line(n, line)
+	}
+	if err := sc.Err(); err != nil {
+		p.errorf(n, "%v", err)
+	}
+	if fence != 0 {
+		p.errorf(fence, "code block is not closed")
+	}
+	p.finish()
+	p.quiz.Intro = strings.TrimSpace(strings.Join(p.intro, "\n"))
+	sort.SliceStable(p.errs, func(i, j int) bool { return p.errs[i].Line < p.errs[j].Line })
+	return &p.quiz, p.errs
+}
+
+func (p *parser) line(n int, line string) {
+	if m := questionRe.FindStringSubmatch(line); m != nil {
+		p.finish()
+		p.cur = &Question{Line: n, Text: plain(m[2])}
+		p.body, p.optLines, p.answerLine, p.inAnswer = nil, nil, 0, false
+		if m[1] != "" {
+			num, _ := strconv.Atoi(m[1])
+			if num != p.number+1 {
+				p.errorf(n, "question is numbered %d; expected %d", num, p.number+1)
+			}
+			p.number = num
+		} else {
+			p.number++
+		}
+		return
+	}
+	if p.cur == nil {
+		switch {
+		case strings.HasPrefix(line, "# ") && p.quiz.Title == "":
+			p.quiz.Title = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "# "), "Quiz:"))
+		case answerRe.MatchString(line):
+			p.errorf(n, "answer before the first question")
+		default:
+			p.intro = append(p.intro, line)
+		}
+		return
+	}
+
+	q := p.cur
+	if m := answerRe.FindStringSubmatch(line); m != nil {
+		if p.answerLine != 0 {
+			p.errorf(n, "second answer; the question is already answered at line %d", p.answerLine)
+			return
+		}
+		p.answerLine, p.inAnswer = n, true
+		p.answer(n, m[1])
+		return
+	}
+	if p.answerLine != 0 {
+		// Only the answer's own blockquote explains it; anything after it,
+		// up to the next question, is ignored.
+		if p.inAnswer = p.inAnswer && strings.HasPrefix(line, ">"); p.inAnswer {
+			text := explainRe.ReplaceAllString(strings.TrimSpace(strings.TrimPrefix(line, ">")), "")
+			q.Explanation += plain(text) + "\n"
+		}
+		return
+	}
+	if m := optionRe.FindStringSubmatch(line); m != nil {
+		letter := m[1]
+		for i, o := range q.Options {
+			if o.Letter == letter {
+				p.errorf(n, "option %s is repeated; it is first given at line %d", letter, p.optLines[i])
+				return
+			}
+		}
+		if want := string(rune('A' + len(q.Options))); letter != want {
+			p.errorf(n, "option %s is out of order; expected %s", letter, want)
+		}
+		q.Options = append(q.Options, Option{Letter: letter, Text: plain(m[2])})
+		p.optLines = append(p.optLines, n)
+		return
+	}
+	if len(q.Options) == 0 {
+		p.body = append(p.body, line)
+	}
+}
+
+// answer reads the text of an answer line. Without options it is free
+// text, unless it names letters in bold.
+func (p *parser) answer(n int, text string) {
+	q := p.cur
+	p.boldAnswer = boldRe.MatchString(text)
+	if len(q.Options) == 0 && !p.boldAnswer {
+		for _, a := range strings.Split(text, "|") {
+			if a = strings.TrimSpace(a); a != "" {
+				q.Answers = append(q.Answers, a)
+			}
+		}
+		if len(q.Answers) == 0 {
+			p.errorf(n, "answer is empty")
+		}
+		return
+	}
+
+	rest := text
+	for {
+		m := letterRe.FindStringSubmatch(rest)
+		if m == nil {
+			break
+		}
+		letter := m[1] + m[2]
+		for _, a := range q.Answers {
+			if a == letter {
+				p.errorf(n, "answer lists %s twice", letter)
+			}
+		}
+		q.Answers = append(q.Answers, letter)
+		rest = rest[len(m[0]):]
+		j := joinRe.FindString(rest)
+		if j == "" {
+			break
+		}
+		rest = rest[len(j):]
+	}
+	if len(q.Answers) == 0 {
+		p.errorf(n, "answer %q does not start with an option letter", text)
+		return
+	}
+	if len(q.Options) == 0 {
+		return // reported by finish
+	}
+	for _, l := range q.Answers {
+		if _, ok := q.Option(l); !ok {
+			p.errorf(n, "answer %s is not one of the options %s-%s", l, q.Options[0].Letter, q.Options[len(q.Options)-1].Letter)
+		}
+	}
+	rest = plain(strings.TrimLeft(rest, ".):"))
+	if len(q.Answers) == 1 && rest != "" {
+		if o, ok := q.Option(q.Answers[0]); ok && !sameText(o.Text, rest) {
+			p.errorf(n, "answer text %q does not match option %s, %q", rest, o.Letter, o.Text)
+		}
+	}
+}
+
+// finish checks the current question and adds it to the quiz.
+func (p *parser) finish() {
+	q := p.cur
+	if q == nil {
+		return
+	}
+	p.cur = nil
+	q.Body = strings.TrimSpace(strings.Join(p.body, "\n"))
+	q.Explanation = strings.TrimSpace(q.Explanation)
+	switch {
+	case len(q.Options) == 0 && (p.answerLine == 0 || p.boldAnswer):
+		p.errorf(q.Line, "question %q has no options", q.Text)
+	case len(q.Options) == 1:
+		p.errorf(q.Line, "question %q has only one option", q.Text)
+	}
+	if p.answerLine == 0 {
+		p.errorf(q.Line, "question %q has no answer line", q.Text)
+	}
+
+	key := normalize(q.Text) + "\n" + strings.Join(strings.Fields(q.Body), " ")
+	for _, o := range q.Options {
+		key += "\n" + o.Letter + normalize(o.Text)
+	}
+	if first, ok := p.seen[key]; ok {
+		p.errorf(q.Line, "question duplicates the one at line %d", first)
+	} else {
+		p.seen[key] = q.Line
+	}
+	p.quiz.Questions = append(p.quiz.Questions, *q)
+}
+
+// sameText compares an answer's text with its option's, ignoring case and
+// a final period.
+func sameText(a, b string) bool {
+	return strings.TrimSuffix(normalize(a), ".") == strings.TrimSuffix(normalize(b), ".")
+}
+
+// plain removes bold markers and a trailing line-break backslash.
+func plain(s string) string {
+	s = strings.TrimSuffix(strings.TrimSpace(s), `\`)
+	return strings.TrimSpace(strings.ReplaceAll(s, "**", ""))
+}
+
+// Find returns the quiz files under root, relative to it: Markdown files
+// with "quiz" in their name.
+func Find(root string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(name), ".md") && strings.Contains(strings.ToLower(name), "quiz") {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			out = append(out, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return out, err
+}
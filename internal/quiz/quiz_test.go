@@ -0,0 +1,308 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-labs/internal/labs"
+)
+
+const sample = "# Quiz: Sample\n" +
+	"\n" +
+	"Choose one answer.\n" +
+	"\n" +
+	"### 1. What does this print?\n" +
+	"\n" +
+	"```go\n" +
+	"fmt.Println(1 + 2)\n" +
+	"```\n" +
+	"\n" +
+	"**A**. `12`\\\n" +
+	"**B**. `3`\n" +
+	"\n" +
+	"> **Answer**: **B**. `3`\n" +
+	"> **Explanation**: Both operands are **untyped** constants.\n" +
+	">\n" +
+	"> The sum is 3.\n" +
+	"\n" +
+	"Trailing notes are ignored.\n" +
+	"\n" +
+	"### 2. Which is **false**?\n" +
+	"\n" +
+	"**A**. Go has goroutines.\\\n" +
+	"**B**. Go has classes.\\\n" +
+	"**C**. None of the above\n" +
+	"\n" +
+	"> **Answer**: B\n"
+
+func TestParse(t *testing.T) {
+	q, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Quiz{
+		Title: "Sample",
+		Intro: "Choose one answer.",
+		Questions: []Question{
+			{
+				Line:        5,
+				Text:        "What does this print?",
+				Body:        "```go\nfmt.Println(1 + 2)\n```",
+				Options:     []Option{{"A", "`12`"}, {"B", "`3`"}},
+				Answers:     []string{"B"},
+				Explanation: "Both operands are untyped constants.\n\nThe sum is 3.",
+			},
+			{
+				Line:    21,
+				Text:    "Which is false?",
+				Options: []Option{{"A", "Go has goroutines."}, {"B", "Go has classes."}, {"C", "None of the above"}},
+				Answers: []string{"B"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(q, expected) {
+		t.Errorf("Parse returned\n%#v\nExpected\n%#v", q, expected)
+	}
+	if !q.Questions[1].Correct(" b ") || q.Questions[1].Correct("A") {
+		t.Errorf("Correct does not accept exactly b and B")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected []string
+	}{
+		{sample, nil},
+		{"# Q\n\n### 1. Why?\n\n> **Answer**: **A**\n", []string{"line 3: question \"Why?\" has no options"}},
+		{"### 1. Why?\n**A**. Yes\n**B**. No\n", []string{"line 1: question \"Why?\" has no answer line"}},
+		{"### 1. Why?\n**A**. Yes\n", []string{
+			"line 1: question \"Why?\" has only one option",
+			"line 1: question \"Why?\" has no answer line",
+		}},
+		{"### 1. Why?\n**A**. Yes\n**B**. No\n\n> **Answer**: **C**. Maybe\n", []string{"line 5: answer C is not one of the options A-B"}},
+		{"### 1. Why?\n**A**. Yes\n**B**. No\n> **Answer**: **A**. No\n", []string{"line 4: answer text \"No\" does not match option A, \"Yes\""}},
+		{"### 1. Why?\n**A**. Yes\n**B**. No\n> **Answer**: yes\n", []string{"line 4: answer \"yes\" does not start with an option letter"}},
+		{"### 1. Why?\n**A**. Yes\n**B**. No\n> **Answer**: **A**\n> **Answer**: **B**\n", []string{"line 5: second answer; the question is already answered at line 4"}},
+		{"### 1. Why?\n**A**. Yes\n**B**. No\n> **Answer**: **A**, **A**\n", []string{"line 4: answer lists A twice"}},
+		{"### 1. Why?\n**A**. Yes\n**C**. No\n**A**. Maybe\n> **Answer**: A\n", []string{
+			"line 3: option C is out of order; expected B",
+			"line 4: option A is repeated; it is first given at line 2",
+		}},
+		{"### 1. Why?\n> **Answer**:\n", []string{"line 2: answer is empty"}},
+		{"# Q\n> **Answer**: A\n", []string{"line 2: answer before the first question"}},
+		{"### 1. Why?\n**A**. Yes\n**B**. No\n> **Answer**: A\n\n### 3. Why?\n**A**. Yes\n**B**. No\n> **Answer**: B\n", []string{
+			"line 6: question is numbered 3; expected 2",
+			"line 6: question duplicates the one at line 1",
+		}},
+		{"### What?\n```go\nx := 1\n", []string{
+			"line 1: question \"What?\" has no options",
+			"line 1: question \"What?\" has no answer line",
+			"line 2: code block is not closed",
+		}},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, e := range Validate(strings.NewReader(test.src)) {
+			got = append(got, e.Error())
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Validate(%q) returned\n%q\nExpected\n%q", test.src, got, test.expected)
+		}
+	}
+
+	_, err := Parse(strings.NewReader("### 1. Why?\n**A**. Yes\n"))
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("Parse returned %v. Expected Errors with 2 problems", err)
+	}
+}
+
+func TestAnswerKinds(t *testing.T) {
+	src := "### 1. Which are reference types?\n" +
+		"**A**. map\\\n**B**. array\\\n**C**. slice\n" +
+		"> **Answer**: **A** and **C**\n" +
+		"### 2. What does `len(\"héllo\")` return?\n" +
+		"> **Answer**: `6` | six\n"
+	q, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	multi, free := q.Questions[0], q.Questions[1]
+	if multi.Kind() != MultipleChoice || free.Kind() != FreeText {
+		t.Fatalf("kinds are %v and %v. Expected MultipleChoice and FreeText", multi.Kind(), free.Kind())
+	}
+	tests := []struct {
+		q              Question
+		answer         string
+		valid, correct bool
+	}{
+		{multi, "A, C", true, true},
+		{multi, "c a", true, true},
+		{multi, "ac", true, true},
+		{multi, "a and c", true, true},
+		{multi, "A", true, false},
+		{multi, "A, B, C", true, false},
+		{multi, "A, D", false, false},
+		{multi, "", false, false},
+		{free, "6", true, true},
+		{free, " `6` ", true, true},
+		{free, "SIX", true, true},
+		{free, "5", true, false},
+		{free, "  ", false, false},
+	}
+	for _, test := range tests {
+		if v, c := test.q.Valid(test.answer), test.q.Correct(test.answer); v != test.valid || c != test.correct {
+			t.Errorf("%q: Valid, Correct returned %v, %v. Expected %v, %v", test.answer, v, c, test.valid, test.correct)
+		}
+	}
+	if s := multi.AnswerText(); s != "A. map; C. slice" {
+		t.Errorf("AnswerText() returned %q", s)
+	}
+	if s := free.AnswerText(); s != "`6` or six" {
+		t.Errorf("AnswerText() returned %q", s)
+	}
+}
+
+func TestQuizFiles(t *testing.T) {
+	root, err := labs.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := Find(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("Find returned no quizzes")
+	}
+	for _, f := range files {
+		q, err := ParseFile(filepath.Join(root, filepath.FromSlash(f)))
+		if err != nil {
+			t.Error(err)
+		} else if len(q.Questions) == 0 {
+			t.Errorf("%s has no questions", f)
+		}
+	}
+}
+
+func TestSession(t *testing.T) {
+	q, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	s := NewSession(q, nil, 0)
+	var answers []string
+	s.OnAnswer = func(i int, correct bool) {
+		answers = append(answers, fmt.Sprint(i, correct))
+	}
+	score, err := s.Run(strings.NewReader("z\nb.\na\n"), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"0 true", "1 false"}; !reflect.DeepEqual(answers, expected) {
+		t.Errorf("OnAnswer was called with %q. Expected %q", answers, expected)
+	}
+	if score != (Score{Correct: 1, Asked: 2, Total: 2}) {
+		t.Errorf("Run returned %+v. Expected 1 of 2 correct", score)
+	}
+	expected := `Sample
+======
+
+Question 1 of 2: What does this print?
+
+    fmt.Println(1 + 2)
+
+  A. ` + "`12`" + `
+  B. ` + "`3`" + `
+
+Your answer: Please answer A, B, or q to quit.
+Your answer: Correct!
+Both operands are untyped constants.
+
+The sum is 3.
+
+Question 2 of 2: Which is false?
+
+  A. Go has goroutines.
+  B. Go has classes.
+  C. None of the above
+
+Your answer: Wrong. The answer is B. Go has classes.
+
+Score: 1/2 correct (50%)
+`
+	if out.String() != expected {
+		t.Errorf("Run printed\n%s\nExpected\n%s", out.String(), expected)
+	}
+
+	// Quitting stops early, and the score counts only answered questions.
+	out.Reset()
+	score, _ = NewSession(q, nil, 0).Run(strings.NewReader("B\nq\n"), &out)
+	if s := score.String(); s != "1/1 correct (100%), 1 of 2 questions answered" {
+		t.Errorf("Score after quitting is %q", s)
+	}
+}
+
+func TestSessionKinds(t *testing.T) {
+	q, err := Parse(strings.NewReader("### Pick the reference types\n" +
+		"**A**. map\\\n**B**. array\\\n**C**. slice\n" +
+		"> **Answer**: **A**, **C**\n" +
+		"### Name the zero value of a pointer\n" +
+		"> **Answer**: `nil`\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	score, _ := NewSession(q, nil, 0).Run(strings.NewReader("a, x\nc a\n\nNULL\n"), &out)
+	expected := `Question 1 of 2: Pick the reference types
+
+  A. map
+  B. array
+  C. slice
+Select all that apply, such as A, C.
+
+Your answer: Please answer with letters from A, B, C, or q to quit.
+Your answer: Correct!
+
+Question 2 of 2: Name the zero value of a pointer
+
+Type your answer.
+
+Your answer: Please type an answer, or q to quit.
+Your answer: Wrong. The answer is ` + "`nil`" + `
+
+Score: 1/2 correct (50%)
+`
+	if out.String() != expected {
+		t.Errorf("Run printed\n%s\nExpected\n%s", out.String(), expected)
+	}
+	if score.Correct != 1 {
+		t.Errorf("Run scored %d correct. Expected 1", score.Correct)
+	}
+}
+
+func TestNewSession(t *testing.T) {
+	q := &Quiz{Questions: make([]Question, 10)}
+	if s := NewSession(q, nil, 0); !reflect.DeepEqual(s.Order, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("NewSession(nil rng) order is %v. Expected file order", s.Order)
+	}
+	a := NewSession(q, rand.New(rand.NewSource(1)), 4)
+	b := NewSession(q, rand.New(rand.NewSource(1)), 4)
+	if len(a.Order) != 4 || !reflect.DeepEqual(a.Order, b.Order) {
+		t.Errorf("NewSession with seed 1 and limit 4 gave %v and %v. Expected the same 4 questions", a.Order, b.Order)
+	}
+	seen := map[int]bool{}
+	for _, i := range NewSession(q, rand.New(rand.NewSource(2)), 0).Order {
+		seen[i] = true
+	}
+	if len(seen) != 10 {
+		t.Errorf("shuffled order asks %d distinct questions. Expected 10", len(seen))
+	}
+}
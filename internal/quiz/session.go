@@ -0,0 +1,160 @@
+package quiz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+)
+
+// Session asks the questions of a quiz and scores the answers.
+type Session struct {
+	Quiz  *Quiz
+	Order []int // indexes into Quiz.Questions, in the order they are asked
+	// OnAnswer, if not nil, is called after each answer with the index of
+	// the question in Quiz.Questions.
+	OnAnswer func(i int, correct bool)
+}
+
+// NewSession returns a session asking every question, shuffled by rng, or
+// in file order if rng is nil. A positive limit asks only that many.
+func NewSession(q *Quiz, rng *rand.Rand, limit int) *Session {
+	order := make([]int, len(q.Questions))
+	for i := range order {
+		order[i] = i
+	}
+	if rng != nil {
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	if limit > 0 && limit < len(order) {
+		order = order[:limit]
+	}
+	return &Session{Quiz: q, Order: order}
+}
+
+// Score is the outcome of a session.
+type Score struct {
+	Correct int
+	Asked   int // questions answered before the end or quitting
+	Total   int
+}
+
+func (s Score) String() string {
+	pct := 0
+	if s.Asked > 0 {
+		pct = (200*s.Correct + s.Asked) / (2 * s.Asked) // rounded
+	}
+	out := fmt.Sprintf("%d/%d correct (%d%%)", s.Correct, s.Asked, pct)
+	if s.Asked < s.Total {
+		out += fmt.Sprintf(", %d of %d questions answered", s.Asked, s.Total)
+	}
+	return out
+}
+
+// Run asks the questions on out, reading one answer per line from in, and
+// tells after each whether it was right. Entering q, or the end of in,
+// stops early.
+func (s *Session) Run(in io.Reader, out io.Writer) (Score, error) {
+	score := Score{Total: len(s.Order)}
+	sc := bufio.NewScanner(in)
+	if s.Quiz.Title != "" {
+		fmt.Fprintf(out, "%s\n%s\n\n", s.Quiz.Title, strings.Repeat("=", len([]rune(s.Quiz.Title))))
+	}
+	for i, qi := range s.Order {
+		q := s.Quiz.Questions[qi]
+		fmt.Fprintf(out, "Question %d of %d: %s\n", i+1, len(s.Order), q.Text)
+		if q.Body != "" {
+			fmt.Fprintf(out, "\n%s\n", render(q.Body))
+		}
+		fmt.Fprintln(out)
+		for _, o := range q.Options {
+			fmt.Fprintf(out, "  %s. %s\n", o.Letter, o.Text)
+		}
+		switch q.Kind() {
+		case MultipleChoice:
+			fmt.Fprintln(out, "Select all that apply, such as A, C.")
+		case FreeText:
+			fmt.Fprintln(out, "Type your answer.")
+		}
+
+		answer, ok := s.ask(sc, out, q)
+		if !ok {
+			fmt.Fprintln(out)
+			break
+		}
+		score.Asked++
+		correct := q.Correct(answer)
+		if correct {
+			score.Correct++
+			fmt.Fprintln(out, "Correct!")
+		} else {
+			fmt.Fprintf(out, "Wrong. The answer is %s\n", q.AnswerText())
+		}
+		if s.OnAnswer != nil {
+			s.OnAnswer(qi, correct)
+		}
+		if q.Explanation != "" {
+			fmt.Fprintln(out, q.Explanation)
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out, "Score:", score)
+	return score, sc.Err()
+}
+
+// ask reads answers until one is valid for q. It returns false when the
+// user quits or the input ends.
+func (s *Session) ask(sc *bufio.Scanner, out io.Writer, q Question) (string, bool) {
+	for {
+		fmt.Fprint(out, "\nYour answer: ")
+		if !sc.Scan() {
+			return "", false
+		}
+		answer := strings.TrimSpace(sc.Text())
+		if q.Kind() != FreeText {
+			answer = strings.TrimRight(answer, ".)")
+		}
+		if strings.EqualFold(answer, "q") || strings.EqualFold(answer, "quit") {
+			return "", false
+		}
+		if q.Valid(answer) {
+			return answer, true
+		}
+		letters := make([]string, len(q.Options))
+		for i, o := range q.Options {
+			letters[i] = o.Letter
+		}
+		switch q.Kind() {
+		case FreeText:
+			fmt.Fprint(out, "Please type an answer, or q to quit.")
+		case MultipleChoice:
+			fmt.Fprintf(out, "Please answer with letters from %s, or q to quit.", strings.Join(letters, ", "))
+		default:
+			fmt.Fprintf(out, "Please answer %s, or q to quit.", strings.Join(letters, ", "))
+		}
+	}
+}
+
+// render prepares Markdown for a terminal: code blocks lose their fences
+// and are indented, and bold markers are dropped.
+func render(md string) string {
+	var b strings.Builder
+	fence := false
+	for _, line := range strings.Split(md, "\n") {
+		if strings.HasPrefix(line, "```") {
+			fence = !fence
+			continue
+		}
+		if fence {
+			if line != "" {
+				b.WriteString("    ")
+			}
+			b.WriteString(line)
+		} else {
+			b.WriteString(strings.ReplaceAll(line, "**", ""))
+		}
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,360 @@
+// Package scaffold creates new labs from the templates in its templates
+// directory, so that every lab starts out laid out and named the same way.
+//
+// A new lab NAME in a chapter gets the next free number NN and consists of:
+//
+//	CHAPTER/NN_NAME/01_NAME.go       main package, with a "// 01_NAME.go" header
+//	CHAPTER/NN_NAME/01_NAME_test.go  a test of the skeleton's function
+//	CHAPTER/NN_NAME/README.md        the lab's notes
+//	CHAPTER/README.md                a line in its "Labs" section
+//
+// With an exercise, it also gets a stub to practise on and its hidden tests,
+// in the practice chapter matching CHAPTER (see package grade):
+//
+//	practice/PCHAPTER/NN_NAME/NAME.go
+//	practice/PCHAPTER/NN_NAME/testdata/grade_test.go
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"go-labs/internal/grade"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Options describe a new lab.
+type Options struct {
+	Chapter  string // chapter number or directory name, as for Chapter
+	Name     string // lab name, such as "labeled break" or labeled_break
+	Title    string // defaults to the name, capitalized
+	Exercise bool   // also create a practice exercise
+}
+
+// File is a file to write.
+type File struct {
+	Path    string // slash-separated, relative to the module root
+	Content []byte
+	Update  bool // the file exists and Content replaces it
+}
+
+var (
+	numberPrefix = regexp.MustCompile(`^(\d+)_`)
+	nameRe       = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// Name turns a lab name into the snake_case form used for its directory
+// and files: "Labeled break" becomes "labeled_break".
+func Name(s string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+	if !nameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid lab name %q: use letters, digits and spaces or underscores, starting with a letter", s)
+	}
+	return name, nil
+}
+
+// Chapter returns the chapter directory named by name: a chapter number
+// ("9"), a directory ("09_control_structures") or a unique part of one
+// ("control"). A numbered directory name that does not exist yet, such as
+// "13_generics", names a new chapter.
+func Chapter(root, name string) (string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return "", err
+	}
+	var chapters []string
+	for _, e := range entries {
+		if e.IsDir() && numberPrefix.MatchString(e.Name()) {
+			chapters = append(chapters, e.Name())
+		}
+	}
+	n, numErr := strconv.Atoi(name)
+	var matches []string
+	for _, ch := range chapters {
+		m := numberPrefix.FindStringSubmatch(ch)
+		num, _ := strconv.Atoi(m[1])
+		switch {
+		case ch == name:
+			return ch, nil
+		case numErr == nil && num == n:
+			return ch, nil
+		case numErr != nil && strings.Contains(ch, strings.ToLower(name)):
+			matches = append(matches, ch)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return "", fmt.Errorf("chapter %q is ambiguous: %s", name, strings.Join(matches, ", "))
+	case numberPrefix.MatchString(name) && nameRe.MatchString(numberPrefix.ReplaceAllString(name, "")):
+		return name, nil
+	}
+	return "", fmt.Errorf("no chapter %q; give its number, its directory, or NN_name for a new one", name)
+}
+
+// Plan is a new lab, ready to write.
+type Plan struct {
+	Dir         string // lab directory
+	ExerciseDir string // empty without an exercise
+	Files       []File
+}
+
+// New plans a new lab without writing anything. It fails if the lab or its
+// exercise already exists.
+func New(root string, opts Options) (*Plan, error) {
+	chapter, err := Chapter(root, opts.Chapter)
+	if err != nil {
+		return nil, err
+	}
+	name, err := Name(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	title := opts.Title
+	if title == "" {
+		title = capitalize(strings.ReplaceAll(name, "_", " "))
+	}
+	num, err := nextNumber(filepath.Join(root, chapter))
+	if err != nil {
+		return nil, err
+	}
+	dir := fmt.Sprintf("%s/%02d_%s", chapter, num, name)
+	data := map[string]string{
+		"Name":  name,
+		"Title": title,
+		"Lower": lower(title),
+		"Dir":   dir,
+		"File":  "01_" + name,
+	}
+
+	plan := &Plan{Dir: dir}
+	var files []File
+	add := func(path, tmpl string) error {
+		content, err := render(tmpl, data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		files = append(files, File{Path: path, Content: content})
+		return nil
+	}
+	if err := add(dir+"/01_"+name+".go", "lab.go.tmpl"); err != nil {
+		return nil, err
+	}
+	if err := add(dir+"/01_"+name+"_test.go", "lab_test.go.tmpl"); err != nil {
+		return nil, err
+	}
+	if err := add(dir+"/README.md", "README.md.tmpl"); err != nil {
+		return nil, err
+	}
+	readme := chapter + "/README.md"
+	old, err := os.ReadFile(filepath.Join(root, readme))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	bullet := labBullet(fmt.Sprintf("%02d_%s", num, name), title)
+	if old == nil {
+		// A new README lists the labs already in the chapter too.
+		existing, err := labBullets(filepath.Join(root, chapter))
+		if err != nil {
+			return nil, err
+		}
+		bullet = strings.Join(append(existing, bullet), "\n")
+	}
+	files = append(files, File{Path: readme, Content: AddLab(old, capitalize(strings.ReplaceAll(numberPrefix.ReplaceAllString(chapter, ""), "_", " ")), bullet), Update: old != nil})
+
+	if opts.Exercise {
+		practice, err := practiceChapter(root, chapter)
+		if err != nil {
+			return nil, err
+		}
+		exDir := fmt.Sprintf("practice/%s/%02d_%s", practice, num, name)
+		plan.ExerciseDir = exDir
+		if err := add(exDir+"/"+name+".go", "exercise.go.tmpl"); err != nil {
+			return nil, err
+		}
+		if err := add(exDir+"/testdata/"+grade.File, "grade_test.go.tmpl"); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range files {
+		if f.Update {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(f.Path))); err == nil {
+			return nil, fmt.Errorf("%s already exists", f.Path)
+		}
+	}
+	plan.Files = files
+	return plan, nil
+}
+
+// Write writes the files of p under root, creating directories as needed.
+func (p *Plan) Write(root string) error {
+	for _, f := range p.Files {
+		path := filepath.Join(root, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddLab adds a bullet to the "## Labs" section of a chapter README, which
+// it creates, with the whole README if need be, as its last section.
+func AddLab(readme []byte, chapterTitle, bullet string) []byte {
+	if len(readme) == 0 {
+		return []byte(fmt.Sprintf("# %s\n\n## Labs\n\n%s\n", chapterTitle, bullet))
+	}
+	lines := strings.Split(strings.TrimRight(string(readme), "\n"), "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "## Labs" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return []byte(strings.Join(lines, "\n") + "\n\n## Labs\n\n" + bullet + "\n")
+	}
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "#") {
+			end = i
+			break
+		}
+	}
+	// Insert after the section's last non-blank line.
+	at := end
+	for at > start+1 && strings.TrimSpace(lines[at-1]) == "" {
+		at--
+	}
+	out := append([]string{}, lines[:at]...)
+	if at == start+1 {
+		out = append(out, "")
+	}
+	out = append(out, bullet)
+	if end < len(lines) {
+		out = append(out, "")
+	}
+	out = append(out, lines[end:]...)
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+func labBullet(dir, title string) string {
+	return fmt.Sprintf("- [`%s`](%s): %s.", dir, dir, title)
+}
+
+// labBullets returns a README bullet for each numbered lab directory or
+// single-file lab of the chapter directory, titled after its name.
+func labBullets(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var bullets []string
+	for _, e := range entries {
+		name := e.Name()
+		if !numberPrefix.MatchString(name) {
+			continue
+		}
+		if !e.IsDir() && (!strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go")) {
+			continue
+		}
+		title := capitalize(strings.ReplaceAll(numberPrefix.ReplaceAllString(strings.TrimSuffix(name, ".go"), ""), "_", " "))
+		bullets = append(bullets, labBullet(name, title))
+	}
+	return bullets, nil
+}
+
+// nextNumber returns one more than the highest number prefixing an entry
+// of the chapter directory, which may not exist yet.
+func nextNumber(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	highest := 0
+	for _, e := range entries {
+		if m := numberPrefix.FindStringSubmatch(e.Name()); m != nil {
+			if n, _ := strconv.Atoi(m[1]); n > highest {
+				highest = n
+			}
+		}
+	}
+	return highest + 1, nil
+}
+
+// practiceChapter returns the directory under practice that mirrors the
+// chapter: one with the same name apart from its number, as chapters are
+// numbered differently there, or the chapter's own name.
+func practiceChapter(root, chapter string) (string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, "practice"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	want := numberPrefix.ReplaceAllString(chapter, "")
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && numberPrefix.ReplaceAllString(e.Name(), "") == want {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) == 0 {
+		return chapter, nil
+	}
+	sort.Strings(names)
+	return names[0], nil
+}
+
+// render executes a template, formatting Go output with gofmt.
+func render(name string, data map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	if err := templates.ExecuteTemplate(&b, name, data); err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(name, ".go.tmpl") {
+		return format.Source(b.Bytes())
+	}
+	return b.Bytes(), nil
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// lower lowers the first letter of a title to use it within a sentence,
+// unless it starts an acronym such as "JSON".
+func lower(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if next, _ := utf8.DecodeRuneInString(s[size:]); unicode.IsUpper(next) {
+		return s
+	}
+	return string(unicode.ToLower(r)) + s[size:]
+}
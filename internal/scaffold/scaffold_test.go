@@ -0,0 +1,162 @@
+package scaffold
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-labs/internal/testutil"
+)
+
+var tree = map[string]string{
+	"go.mod":                           "module example.com/labs\n\ngo 1.19\n",
+	"05_arrays_and_slices/README.md":   "# Arrays\n\n## Labs\n\n- [`01_arrays`](01_arrays): Arrays.\n\n## See also\n\nThe spec.\n",
+	"05_arrays_and_slices/01_arrays/a": "",
+	"05_arrays_and_slices/03_copy.go":  "package main\n",
+	"06_maps/maps.go":                  "package main\n",
+	"06_maps/02_word_count/x":          "",
+	"06_maps/01_maps.go":               "package main\n",
+	"06_maps/01_maps.md":               "# Maps\n",
+	"09_control_structures/x.go":       "package main\n",
+	"practice/04_arrays_and_slices/x":  "",
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		in, expected string
+	}{
+		{"labeled_break", "labeled_break"},
+		{"Labeled break", "labeled_break"},
+		{"  for-range  loops ", "for_range_loops"},
+		{"utf8", "utf8"},
+		{"2d arrays", ""},
+		{"maps!", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		got, err := Name(test.in)
+		if got != test.expected || (err != nil) != (test.expected == "") {
+			t.Errorf("Name(%q) returned %q, %v. Expected %q", test.in, got, err, test.expected)
+		}
+	}
+}
+
+func TestChapter(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteTree(t, root, tree)
+	tests := []struct {
+		name, expected string
+	}{
+		{"5", "05_arrays_and_slices"},
+		{"06", "06_maps"},
+		{"09_control_structures", "09_control_structures"},
+		{"control", "09_control_structures"},
+		{"13_generics", "13_generics"},
+		{"s", ""}, // in every chapter
+		{"7", ""},
+		{"generics", ""},
+	}
+	for _, test := range tests {
+		got, err := Chapter(root, test.name)
+		if got != test.expected || (err != nil) != (test.expected == "") {
+			t.Errorf("Chapter(%q) returned %q, %v. Expected %q", test.name, got, err, test.expected)
+		}
+	}
+}
+
+func TestAddLab(t *testing.T) {
+	bullet := "- [`02_b`](02_b): B."
+	tests := []struct {
+		readme, expected string
+	}{
+		{"", "# Maps\n\n## Labs\n\n- [`02_b`](02_b): B.\n"},
+		{"# Maps\n\nText.\n", "# Maps\n\nText.\n\n## Labs\n\n- [`02_b`](02_b): B.\n"},
+		{"# Maps\n\n## Labs\n\n- a\n", "# Maps\n\n## Labs\n\n- a\n- [`02_b`](02_b): B.\n"},
+		{"# Maps\n\n## Labs\n\n- a\n\n## More\n", "# Maps\n\n## Labs\n\n- a\n- [`02_b`](02_b): B.\n\n## More\n"},
+		{"# Maps\n\n## Labs\n## More\n", "# Maps\n\n## Labs\n\n- [`02_b`](02_b): B.\n\n## More\n"},
+	}
+	for _, test := range tests {
+		if got := string(AddLab([]byte(test.readme), "Maps", bullet)); got != test.expected {
+			t.Errorf("AddLab(%q) returned\n%q\nExpected\n%q", test.readme, got, test.expected)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteTree(t, root, tree)
+	plan, err := New(root, Options{Chapter: "5", Name: "Copying slices", Title: "JSON \"copy\"", Exercise: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range plan.Files {
+		paths = append(paths, f.Path)
+		if strings.HasSuffix(f.Path, ".go") {
+			if formatted, err := format.Source(f.Content); err != nil || string(formatted) != string(f.Content) {
+				t.Errorf("%s is not gofmt'ed: %v\n%s", f.Path, err, f.Content)
+			}
+			for _, line := range strings.Split(string(f.Content), "\n") {
+				if strings.HasPrefix(line, "//") && len(line) > 100 {
+					t.Errorf("%s has a comment line over 100 columns: %q", f.Path, line)
+				}
+			}
+		}
+	}
+	expected := []string{
+		"05_arrays_and_slices/04_copying_slices/01_copying_slices.go",
+		"05_arrays_and_slices/04_copying_slices/01_copying_slices_test.go",
+		"05_arrays_and_slices/04_copying_slices/README.md",
+		"05_arrays_and_slices/README.md",
+		"practice/04_arrays_and_slices/04_copying_slices/copying_slices.go",
+		"practice/04_arrays_and_slices/04_copying_slices/testdata/grade_test.go",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("New planned\n%q\nExpected\n%q", paths, expected)
+	}
+	if plan.Dir != "05_arrays_and_slices/04_copying_slices" || plan.ExerciseDir != "practice/04_arrays_and_slices/04_copying_slices" {
+		t.Errorf("New planned directories %q and %q", plan.Dir, plan.ExerciseDir)
+	}
+	lab := string(plan.Files[0].Content)
+	for _, s := range []string{"// 01_copying_slices.go\n", "// Command copying_slices demonstrates JSON \"copy\".\n", `greeting("JSON \"copy\"")`} {
+		if !strings.Contains(lab, s) {
+			t.Errorf("lab file lacks %q:\n%s", s, lab)
+		}
+	}
+	if exercise := string(plan.Files[4].Content); !strings.Contains(exercise, "//\t05_arrays_and_slices/04_copying_slices\n") {
+		t.Errorf("exercise stub does not name its lab on a line of its own:\n%s", exercise)
+	}
+	if readme := plan.Files[3]; !readme.Update || !strings.Contains(string(readme.Content), "- [`01_arrays`](01_arrays): Arrays.\n- [`04_copying_slices`](04_copying_slices): JSON \"copy\".\n\n## See also") {
+		t.Errorf("chapter README is\n%s", readme.Content)
+	}
+
+	if err := plan.Write(root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "practice/04_arrays_and_slices/04_copying_slices/testdata/grade_test.go")); err != nil {
+		t.Error(err)
+	}
+	if _, err := New(root, Options{Chapter: "5", Name: "copying slices"}); err != nil {
+		t.Errorf("New after Write returned %v. Expected the next number, 05", err)
+	}
+
+	plan, err = New(root, Options{Chapter: "13_generics", Name: "constraints"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 4 || plan.Dir != "13_generics/01_constraints" || plan.Files[3].Update {
+		t.Errorf("New in a new chapter planned %+v", plan)
+	}
+
+	plan, err = New(root, Options{Chapter: "6", Name: "sets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedReadme := "# Maps\n\n## Labs\n\n- [`01_maps.go`](01_maps.go): Maps.\n- [`02_word_count`](02_word_count): Word count.\n- [`03_sets`](03_sets): Sets.\n"
+	if readme := plan.Files[3]; readme.Update || string(readme.Content) != expectedReadme {
+		t.Errorf("new chapter README is\n%s\nExpected\n%s", readme.Content, expectedReadme)
+	}
+}
@@ -0,0 +1,85 @@
+package site
+
+import (
+	"go/scanner"
+	"go/token"
+	"html"
+	"strings"
+)
+
+// predeclared are the identifiers of the universe block, highlighted like
+// keywords of a lesser kind.
+var predeclared = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true,
+	"complex64": true, "complex128": true, "error": true, "float32": true,
+	"float64": true, "int": true, "int8": true, "int16": true, "int32": true,
+	"int64": true, "rune": true, "string": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"true": true, "false": true, "iota": true, "nil": true,
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true,
+	"copy": true, "delete": true, "imag": true, "len": true, "make": true,
+	"max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true,
+}
+
+// highlight returns Go source as HTML, with keywords, predeclared
+// identifiers, literals and comments in spans of the classes kw, pre, str,
+// num and com. The source need not compile, or even parse: snippets of the
+// notes are highlighted token by token.
+func highlight(src string) string {
+	var b strings.Builder
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, []byte(src), func(token.Position, string) {}, scanner.ScanComments)
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		class := ""
+		switch {
+		case tok.IsKeyword():
+			class = "kw"
+		case tok == token.IDENT && predeclared[lit]:
+			class = "pre"
+		case tok == token.STRING || tok == token.CHAR:
+			class = "str"
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			class = "num"
+		case tok == token.COMMENT:
+			class = "com"
+		}
+		if class == "" {
+			continue
+		}
+		start := file.Offset(pos)
+		end := tokenEnd(src, start, lit)
+		b.WriteString(html.EscapeString(src[last:start]))
+		b.WriteString(`<span class="` + class + `">` + html.EscapeString(src[start:end]) + "</span>")
+		last = end
+	}
+	b.WriteString(html.EscapeString(src[last:]))
+	return b.String()
+}
+
+// tokenEnd returns the offset after the token lit that starts at start.
+// The scanner drops carriage returns from raw strings and comments, so the
+// end comes from the source, not from the length of lit.
+func tokenEnd(src string, start int, lit string) int {
+	if !strings.Contains(src[start:start+len(lit)], "\r") && strings.HasPrefix(src[start:], lit) {
+		return start + len(lit)
+	}
+	// Count the characters of lit in src, skipping carriage returns.
+	i, j := start, 0
+	for i < len(src) && j < len(lit) {
+		if src[i] == '\r' && lit[j] != '\r' {
+			i++
+			continue
+		}
+		i++
+		j++
+	}
+	return i
+}
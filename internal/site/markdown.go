@@ -0,0 +1,686 @@
+package site
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// markdown renders the Markdown of the course: the CommonMark blocks and
+// inlines its notes use, GitHub's pipe tables, and raw HTML such as the
+// <details> sections of the README, which passes through unchanged. Fenced
+// Go code is highlighted.
+type markdown struct {
+	// link rewrites the destination of a link or image; nil keeps it.
+	link func(dest string) string
+	ids  map[string]int // heading ids given so far
+}
+
+// render returns src as HTML.
+func (m *markdown) render(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	var b strings.Builder
+	m.blocks(&b, strings.Split(src, "\n"), false)
+	return b.String()
+}
+
+var (
+	headingRe   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	hrRe        = regexp.MustCompile(`^ {0,3}([-*_])(?:[ \t]*[-*_]){2,}[ \t]*$`)
+	fenceRe     = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*)$")
+	itemRe      = regexp.MustCompile(`^( {0,3})([-*+]|(\d{1,9})[.)])( +|$)`)
+	htmlBlockRe = regexp.MustCompile(`^ {0,3}<(?:[a-zA-Z][a-zA-Z0-9-]*(?:[\s/>]|$)|/[a-zA-Z][a-zA-Z0-9-]*\s*>|!--)`)
+	setextRe    = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	delimRowRe  = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+)
+
+func blank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// interrupts reports whether line starts a block that ends a paragraph.
+func interrupts(line string) bool {
+	if headingRe.MatchString(line) || hrRe.MatchString(line) || fenceRe.MatchString(line) ||
+		htmlBlockRe.MatchString(line) || strings.HasPrefix(strings.TrimLeft(line, " "), ">") {
+		return true
+	}
+	// Only lists starting at 1 interrupt a paragraph, so that a line such
+	// as "2021. A year" stays text.
+	if m := itemRe.FindStringSubmatch(line); m != nil && m[4] != "" {
+		return m[3] == "" || m[3] == "1"
+	}
+	return false
+}
+
+// blocks renders lines as a sequence of blocks. In a tight list item,
+// paragraphs are not wrapped in <p>.
+func (m *markdown) blocks(b *strings.Builder, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case blank(line):
+			i++
+
+		case fenceRe.MatchString(line):
+			i = m.fence(b, lines, i)
+
+		case headingRe.MatchString(line):
+			sub := headingRe.FindStringSubmatch(line)
+			m.heading(b, len(sub[1]), sub[2])
+			i++
+
+		case hrRe.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case htmlBlockRe.MatchString(line):
+			for ; i < len(lines) && !blank(lines[i]); i++ {
+				b.WriteString(m.rawHTML(lines[i]))
+				b.WriteByte('\n')
+			}
+
+		case strings.HasPrefix(strings.TrimLeft(line, " "), ">"):
+			i = m.quote(b, lines, i)
+
+		case itemRe.MatchString(line):
+			i = m.list(b, lines, i)
+
+		case indent(line) >= 4:
+			var code []string
+			for ; i < len(lines) && (blank(lines[i]) || indent(lines[i]) >= 4); i++ {
+				code = append(code, strings.TrimPrefix(lines[i], "    "))
+			}
+			for len(code) > 0 && blank(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+			m.code(b, "", strings.Join(code, "\n"))
+
+		case i+1 < len(lines) && strings.Contains(line, "|") && delimRowRe.MatchString(lines[i+1]):
+			i = m.table(b, lines, i)
+
+		default:
+			i = m.paragraph(b, lines, i, tight)
+		}
+	}
+}
+
+func (m *markdown) fence(b *strings.Builder, lines []string, i int) int {
+	sub := fenceRe.FindStringSubmatch(lines[i])
+	ind, marker := len(sub[1]), sub[2]
+	lang := strings.Fields(sub[3] + " ")
+	var code []string
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if t := strings.TrimSpace(line); indent(line) < 4 && strings.HasPrefix(t, marker) && strings.Trim(t, marker[:1]) == "" {
+			i++
+			break
+		}
+		n := indent(line)
+		if n > ind {
+			n = ind
+		}
+		code = append(code, line[n:])
+	}
+	info := ""
+	if len(lang) > 0 {
+		info = lang[0]
+	}
+	m.code(b, info, strings.Join(code, "\n"))
+	return i
+}
+
+func (m *markdown) code(b *strings.Builder, lang, code string) {
+	if lang == "" {
+		b.WriteString("<pre><code>")
+	} else {
+		b.WriteString(`<pre><code class="language-` + html.EscapeString(lang) + `">`)
+	}
+	if lang == "go" {
+		b.WriteString(highlight(code))
+	} else {
+		b.WriteString(html.EscapeString(code))
+	}
+	if code != "" {
+		b.WriteByte('\n')
+	}
+	b.WriteString("</code></pre>\n")
+}
+
+func (m *markdown) heading(b *strings.Builder, level int, text string) {
+	content := m.inline(text)
+	id := slug(content)
+	if m.ids == nil {
+		m.ids = make(map[string]int)
+	}
+	if n := m.ids[id]; n > 0 {
+		m.ids[id]++
+		id += "-" + strconv.Itoa(n)
+	} else {
+		m.ids[id] = 1
+	}
+	h := "h" + strconv.Itoa(level)
+	b.WriteString("<" + h + ` id="` + id + `">` + content + "</" + h + ">\n")
+}
+
+var (
+	tagRe  = regexp.MustCompile(`<[^>]*>`)
+	attrRe = regexp.MustCompile(`\b(src|href)="([^"]*)"`)
+)
+
+// rawHTML returns raw HTML with the destinations of its src and href
+// attributes rewritten like those of Markdown links.
+func (m *markdown) rawHTML(s string) string {
+	if m.link == nil {
+		return s
+	}
+	return attrRe.ReplaceAllStringFunc(s, func(attr string) string {
+		sub := attrRe.FindStringSubmatch(attr)
+		dest := m.link(html.UnescapeString(sub[2]))
+		return sub[1] + `="` + html.EscapeString(dest) + `"`
+	})
+}
+
+// slug makes a heading id the way GitHub does, so that links to the
+// headings of the notes keep working: "Maps & Keys" becomes "maps--keys".
+func slug(content string) string {
+	text := strings.ToLower(html.UnescapeString(tagRe.ReplaceAllString(content, "")))
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+func (m *markdown) quote(b *strings.Builder, lines []string, i int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		t := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(t, ">") {
+			t = strings.TrimPrefix(t[1:], " ")
+			inner = append(inner, t)
+			continue
+		}
+		// A lazy continuation line carries on the quoted paragraph.
+		if blank(line) || interrupts(line) || len(inner) == 0 || blank(inner[len(inner)-1]) {
+			break
+		}
+		inner = append(inner, line)
+	}
+	b.WriteString("<blockquote>\n")
+	m.blocks(b, inner, false)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+func (m *markdown) list(b *strings.Builder, lines []string, i int) int {
+	first := itemRe.FindStringSubmatch(lines[i])
+	ordered := first[3] != ""
+	kind := first[2][len(first[2])-1:] // "-", "*", "+", "." or ")"
+	var items [][]string
+	loose := false
+	for i < len(lines) {
+		sub := itemRe.FindStringSubmatch(lines[i])
+		if sub == nil || sub[2][len(sub[2])-1:] != kind {
+			break
+		}
+		width := len(sub[0])
+		if len(sub[4]) > 4 {
+			width = len(sub[1]) + len(sub[2]) + 1
+		}
+		item := []string{strings.TrimLeft(lines[i][len(sub[1])+len(sub[2]):], " ")}
+		if len(sub[4]) > 4 {
+			item[0] = lines[i][width:]
+		}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			switch {
+			case blank(line):
+				item = append(item, "")
+				continue
+			case indent(line) >= width:
+				item = append(item, line[width:])
+				continue
+			case !blank(item[len(item)-1]) && !interrupts(line) && !itemRe.MatchString(line):
+				// Lazy continuation of the item's paragraph.
+				item = append(item, strings.TrimLeft(line, " "))
+				continue
+			}
+			break
+		}
+		n := len(item)
+		for n > 0 && blank(item[n-1]) {
+			n--
+		}
+		// A blank line between items, or between the blocks of one, makes
+		// the list loose.
+		if n < len(item) && i < len(lines) && itemRe.MatchString(lines[i]) || hasInnerBlank(item[:n]) {
+			loose = true
+		}
+		items = append(items, item[:n])
+	}
+
+	switch {
+	case !ordered:
+		b.WriteString("<ul>\n")
+	case first[3] != "1":
+		start, _ := strconv.Atoi(first[3])
+		b.WriteString(`<ol start="` + strconv.Itoa(start) + `">` + "\n")
+	default:
+		b.WriteString("<ol>\n")
+	}
+	for _, item := range items {
+		b.WriteString("<li>")
+		if loose {
+			b.WriteByte('\n')
+		}
+		m.blocks(b, item, !loose)
+		b.WriteString("</li>\n")
+	}
+	if ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+	return i
+}
+
+// hasInnerBlank reports whether an item's blocks are separated by blank
+// lines, which makes its list loose. Blank lines inside fenced code do not
+// count.
+func hasInnerBlank(item []string) bool {
+	inFence := ""
+	for _, line := range item {
+		if sub := fenceRe.FindStringSubmatch(line); sub != nil {
+			switch {
+			case inFence == "":
+				inFence = sub[2]
+			case strings.HasPrefix(sub[2], inFence) && sub[3] == "":
+				inFence = ""
+			}
+			continue
+		}
+		if inFence == "" && blank(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *markdown) table(b *strings.Builder, lines []string, i int) int {
+	header := cells(lines[i])
+	var align []string
+	for _, c := range cells(lines[i+1]) {
+		switch {
+		case strings.HasPrefix(c, ":") && strings.HasSuffix(c, ":"):
+			align = append(align, ` style="text-align:center"`)
+		case strings.HasSuffix(c, ":"):
+			align = append(align, ` style="text-align:right"`)
+		case strings.HasPrefix(c, ":"):
+			align = append(align, ` style="text-align:left"`)
+		default:
+			align = append(align, "")
+		}
+	}
+	row := func(tag string, cs []string) {
+		b.WriteString("<tr>")
+		for j := range align {
+			c := ""
+			if j < len(cs) {
+				c = cs[j]
+			}
+			b.WriteString("<" + tag + align[j] + ">" + m.inline(c) + "</" + tag + ">")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("<table>\n<thead>\n")
+	row("th", header)
+	b.WriteString("</thead>\n<tbody>\n")
+	for i += 2; i < len(lines) && !blank(lines[i]) && strings.Contains(lines[i], "|") && !interrupts(lines[i]); i++ {
+		row("td", cells(lines[i]))
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return i
+}
+
+// cells splits a table row at the pipes outside code spans and escapes.
+func cells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var out []string
+	var cell strings.Builder
+	ticks := 0
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+			continue
+		case c == '`':
+			n := runLen(line, i, '`')
+			switch {
+			case ticks == 0:
+				ticks = n
+			case ticks == n:
+				ticks = 0
+			}
+			cell.WriteString(line[i : i+n])
+			i += n - 1
+			continue
+		case c == '|' && ticks == 0:
+			out = append(out, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(c)
+	}
+	return append(out, strings.TrimSpace(cell.String()))
+}
+
+func (m *markdown) paragraph(b *strings.Builder, lines []string, i int, tight bool) int {
+	var para []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if blank(line) || (len(para) > 0 && interrupts(line) && !setextRe.MatchString(line)) {
+			break
+		}
+		if len(para) > 0 && setextRe.MatchString(line) {
+			level := 1
+			if strings.TrimSpace(line)[0] == '-' {
+				level = 2
+			}
+			m.heading(b, level, strings.Join(para, "\n"))
+			return i + 1
+		}
+		line = strings.TrimLeft(line, " ")
+		if strings.HasSuffix(line, "  ") {
+			line = strings.TrimRight(line, " ") + "\\" // a hard line break
+		}
+		para = append(para, line)
+	}
+	text := strings.TrimSuffix(strings.Join(para, "\n"), "\\")
+	if tight {
+		b.WriteString(m.inline(text))
+		b.WriteByte('\n')
+		return i
+	}
+	b.WriteString("<p>" + m.inline(text) + "</p>\n")
+	return i
+}
+
+var (
+	entityRe     = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]*|#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6});`)
+	autolinkRe   = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*)>`)
+	inlineHTMLRe = regexp.MustCompile(`^(?:<[a-zA-Z][a-zA-Z0-9-]*(?:\s+[a-zA-Z_:][\w.:-]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>|</[a-zA-Z][a-zA-Z0-9-]*\s*>|<!--[\s\S]*?-->)`)
+)
+
+const punctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+func runLen(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// inline renders the inline content of a block.
+func (m *markdown) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '\\':
+			switch {
+			case i+1 < len(s) && s[i+1] == '\n':
+				b.WriteString("<br>\n")
+				i += 2
+				continue
+			case i+1 < len(s) && strings.IndexByte(punctuation, s[i+1]) >= 0:
+				b.WriteString(html.EscapeString(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+
+		case '`':
+			if code, n := codeSpan(s, i); n > 0 {
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += n
+				continue
+			}
+			n := runLen(s, i, '`')
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+
+		case '!', '[':
+			if out, n := m.linkAt(s, i); n > 0 {
+				b.WriteString(out)
+				i += n
+				continue
+			}
+
+		case '<':
+			if sub := autolinkRe.FindStringSubmatch(s[i:]); sub != nil {
+				b.WriteString(`<a href="` + html.EscapeString(sub[1]) + `">` + html.EscapeString(sub[1]) + "</a>")
+				i += len(sub[0])
+				continue
+			}
+			if raw := inlineHTMLRe.FindString(s[i:]); raw != "" {
+				b.WriteString(m.rawHTML(raw))
+				i += len(raw)
+				continue
+			}
+
+		case '&':
+			if e := entityRe.FindString(s[i:]); e != "" {
+				b.WriteString(e)
+				i += len(e)
+				continue
+			}
+
+		case '*', '_':
+			if out, n := m.emphasis(s, i); n > 0 {
+				b.WriteString(out)
+				i += n
+				continue
+			}
+			n := runLen(s, i, c)
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// codeSpan returns the content of the code span starting at s[i] and its
+// length in s, or 0 if the backticks are not closed.
+func codeSpan(s string, i int) (string, int) {
+	n := runLen(s, i, '`')
+	for j := i + n; j < len(s); {
+		k := strings.IndexByte(s[j:], '`')
+		if k < 0 {
+			return "", 0
+		}
+		j += k
+		if m := runLen(s, j, '`'); m != n {
+			j += m
+			continue
+		}
+		code := strings.ReplaceAll(s[i+n:j], "\n", " ")
+		if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+			code = code[1 : len(code)-1]
+		}
+		return code, j + n - i
+	}
+	return "", 0
+}
+
+// linkAt renders the link or image starting at s[i], returning its length
+// in s, or 0 if there is none.
+func (m *markdown) linkAt(s string, i int) (string, int) {
+	image := s[i] == '!'
+	start := i
+	if image {
+		if i+1 >= len(s) || s[i+1] != '[' {
+			return "", 0
+		}
+		i++
+	}
+	// Find the closing bracket, skipping code spans and escapes.
+	depth, j := 0, i
+	for ; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			if _, n := codeSpan(s, j); n > 0 {
+				j += n - 1
+			}
+			continue
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j >= len(s) || j+1 >= len(s) || s[j+1] != '(' {
+		return "", 0
+	}
+	text := s[i+1 : j]
+	dest, title, n := linkDest(s[j+2:])
+	if n < 0 {
+		return "", 0
+	}
+	end := j + 2 + n
+	if m.link != nil {
+		dest = m.link(dest)
+	}
+	if title != "" {
+		title = ` title="` + html.EscapeString(title) + `"`
+	}
+	if image {
+		alt := html.UnescapeString(tagRe.ReplaceAllString(m.inline(text), ""))
+		return `<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(alt) + `"` + title + ">", end - start
+	}
+	return `<a href="` + html.EscapeString(dest) + `"` + title + ">" + m.inline(text) + "</a>", end - start
+}
+
+// linkDest parses `dest "title")` and returns the destination, the title
+// and the length up to and including the closing parenthesis, or -1.
+func linkDest(s string) (dest, title string, n int) {
+	i := len(s) - len(strings.TrimLeft(s, " \n"))
+	if i < len(s) && s[i] == '<' {
+		k := strings.IndexAny(s[i:], ">\n")
+		if k < 0 || s[i+k] != '>' {
+			return "", "", -1
+		}
+		dest = s[i+1 : i+k]
+		i += k + 1
+	} else {
+		depth, j := 0, i
+		for ; j < len(s); j++ {
+			c := s[j]
+			if c == '\\' && j+1 < len(s) {
+				j++
+				continue
+			}
+			if c == ' ' || c == '\n' || (c == ')' && depth == 0) {
+				break
+			}
+			if c == '(' {
+				depth++
+			}
+			if c == ')' {
+				depth--
+			}
+		}
+		dest = s[i:j]
+		i = j
+	}
+	i += len(s[i:]) - len(strings.TrimLeft(s[i:], " \n"))
+	if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+		k := strings.IndexByte(s[i+1:], s[i])
+		if k < 0 {
+			return "", "", -1
+		}
+		title = s[i+1 : i+1+k]
+		i += k + 2
+		i += len(s[i:]) - len(strings.TrimLeft(s[i:], " \n"))
+	}
+	if i >= len(s) || s[i] != ')' {
+		return "", "", -1
+	}
+	return dest, title, i + 1
+}
+
+// emphasis renders the emphasis or strong emphasis opened at s[i],
+// returning its length in s, or 0 if it is not closed. An underscore inside
+// a word, as in snake_case, is text.
+func (m *markdown) emphasis(s string, i int) (string, int) {
+	c := s[i]
+	n := runLen(s, i, c)
+	if n > 2 {
+		n = 2
+	}
+	open := i + n
+	if open >= len(s) || s[open] == ' ' || s[open] == '\n' || (c == '_' && i > 0 && isWordByte(s[i-1])) {
+		return "", 0
+	}
+	delim := s[i:open]
+	for j := open; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			if _, k := codeSpan(s, j); k > 0 {
+				j += k - 1
+			}
+			continue
+		}
+		if !strings.HasPrefix(s[j:], delim) || j == open || s[j-1] == ' ' || s[j-1] == '\n' {
+			continue
+		}
+		if c == '_' && j+n < len(s) && isWordByte(s[j+n]) {
+			continue
+		}
+		if n == 1 && j+1 < len(s) && s[j+1] == c {
+			// "*a **b** c*": skip the strong delimiters inside.
+			j += runLen(s, j, c) - 1
+			continue
+		}
+		tag := "em"
+		if n == 2 {
+			tag = "strong"
+		}
+		return "<" + tag + ">" + m.inline(s[open:j]) + "</" + tag + ">", j + n - i
+	}
+	return "", 0
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
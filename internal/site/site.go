@@ -0,0 +1,630 @@
+// Package site renders the course as a static HTML site that works offline:
+// every page links to the others and to its stylesheet and images with
+// relative URLs, and nothing is fetched from the network.
+//
+// The site has a page for each Markdown file, with the repository README as
+// its index, and one for each package directory, or for each program of a
+// directory of single-file programs. A lab's page shows its
+// Markdown notes, its highlighted sources and the output captured in its
+// golden file (see package golden); a library package's page shows its
+// README and sources. Quiz answers are folded away until clicked.
+//
+// Pages are ordered by the chapters of docs/topics.md, into which they are
+// placed as progress.Place places them, and a contents page lists them by
+// chapter and topic. Links between Markdown files and to package directories
+// are rewritten to point at the pages rendering them, and the local files
+// the pages link to, such as images, are copied along.
+package site
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-labs/internal/catalog"
+	"go-labs/internal/golden"
+	"go-labs/internal/grade"
+	"go-labs/internal/progress"
+	"go-labs/internal/quiz"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// Page is a page of the site.
+type Page struct {
+	Path   string // output file, slash-separated, such as "06_maps/index.html"
+	Source string // Markdown file, package directory or program file, relative to the root
+	Title  string
+	Body   template.HTML
+	// Chapter and Topic index the chapters of docs/topics.md and their
+	// topics; they are -1 for pages placed in none.
+	Chapter, Topic int
+}
+
+// Site is the rendered course.
+type Site struct {
+	Root     string
+	Title    string // the README's heading
+	Chapters []progress.Chapter
+	Pages    []*Page  // in reading order, starting with the index
+	Assets   []string // local files linked from the pages, relative to the root
+}
+
+// Generate renders the course found under root.
+func Generate(root string) (*Site, error) {
+	f, err := os.Open(filepath.Join(root, "docs", "topics.md"))
+	if err != nil {
+		return nil, err
+	}
+	chapters, err := progress.ParseTopics(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	c, err := catalog.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	quizzes, err := quiz.Find(root)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{
+		root:     root,
+		site:     &Site{Root: root, Title: "Course", Chapters: chapters},
+		labs:     make(map[string]catalog.Entry),
+		programs: make(map[string][]catalog.Entry),
+		quizzes:  make(map[string]bool),
+		targets:  make(map[string]target),
+		assets:   make(map[string]bool),
+	}
+	for _, e := range c.Entries {
+		g.labs[e.Path()] = e
+		if e.File != "" {
+			g.programs[e.Dir] = append(g.programs[e.Dir], e)
+		}
+	}
+	for _, q := range quizzes {
+		g.quizzes[q] = true
+	}
+	if err := g.discover(); err != nil {
+		return nil, err
+	}
+	for _, p := range g.site.Pages {
+		if err := g.render(p); err != nil {
+			return nil, err
+		}
+	}
+	g.order()
+	for a := range g.assets {
+		g.site.Assets = append(g.site.Assets, a)
+	}
+	sort.Strings(g.site.Assets)
+	return g.site, nil
+}
+
+type generator struct {
+	root     string
+	site     *Site
+	labs     map[string]catalog.Entry   // by path
+	programs map[string][]catalog.Entry // single-file programs, by directory
+	quizzes  map[string]bool
+	// targets maps the Markdown files, package directories and Go files
+	// that links may name to the pages showing them.
+	targets map[string]target
+	assets  map[string]bool
+	notes   map[string]string // Markdown notes shown on a package's page, by directory
+	sources map[string][]string
+}
+
+type target struct {
+	page     *Page
+	fragment string
+}
+
+// skip reports directories that hold no course content.
+func skip(rel, name string) bool {
+	switch {
+	case rel == ".":
+		return false
+	case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"):
+		return true
+	case name == "testdata" || name == "vendor":
+		return true
+	case rel == "cmd" || rel == "internal":
+		return true
+	}
+	return false
+}
+
+// discover walks the tree and creates a page for every Markdown file and
+// package directory.
+func (g *generator) discover() error {
+	index := &Page{Path: "index.html", Source: "README.md", Chapter: -1, Topic: -1}
+	contents := &Page{Path: "contents.html", Source: "docs/topics.md", Title: "Contents", Chapter: -1, Topic: -1}
+	g.site.Pages = []*Page{index, contents}
+	g.targets[index.Source] = target{page: index}
+	g.targets[contents.Source] = target{page: contents}
+	g.notes = make(map[string]string)
+	g.sources = make(map[string][]string)
+
+	var markdown []string
+	err := filepath.WalkDir(g.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(g.root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if skip(rel, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		dir := path.Dir(rel)
+		switch name := d.Name(); {
+		case strings.EqualFold(path.Ext(name), ".md"):
+			if _, ok := g.targets[rel]; !ok {
+				markdown = append(markdown, rel)
+			}
+		case strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") && dir != ".":
+			g.sources[dir] = append(g.sources[dir], name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var dirs []string
+	for dir := range g.sources {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if programs := g.programs[dir]; programs != nil {
+			g.discoverPrograms(programs)
+			continue
+		}
+		p := &Page{Path: dir + "/index.html", Source: dir}
+		g.site.Pages = append(g.site.Pages, p)
+		g.targets[dir] = target{page: p}
+		for _, name := range g.sources[dir] {
+			g.targets[dir+"/"+name] = target{page: p, fragment: name}
+		}
+		notes := ""
+		if e, ok := g.labs[dir]; ok {
+			notes = e.Notes
+		} else if _, err := os.Stat(filepath.Join(g.root, filepath.FromSlash(dir), "README.md")); err == nil {
+			notes = dir + "/README.md"
+		}
+		if notes != "" {
+			g.notes[dir] = notes
+			g.targets[notes] = target{page: p}
+		}
+	}
+	for _, md := range markdown {
+		if _, ok := g.targets[md]; ok {
+			continue
+		}
+		out := strings.TrimSuffix(md, path.Ext(md)) + ".html"
+		dir := path.Dir(md)
+		if strings.EqualFold(path.Base(md), "README.md") {
+			// A directory's README is the page its directory links to.
+			out = path.Join(dir, "index.html")
+		}
+		p := &Page{Path: out, Source: md}
+		g.site.Pages = append(g.site.Pages, p)
+		g.targets[md] = target{page: p}
+		if _, ok := g.targets[dir]; !ok && out == path.Join(dir, "index.html") {
+			g.targets[dir] = target{page: p}
+		}
+	}
+	return nil
+}
+
+// discoverPrograms creates a page for each program of a directory of
+// single-file programs. A program's page shows the Markdown notes named
+// after it; notes shared by the directory get a page of their own.
+func (g *generator) discoverPrograms(programs []catalog.Entry) {
+	for _, e := range programs {
+		name := strings.TrimSuffix(e.Path(), ".go")
+		p := &Page{Path: name + ".html", Source: e.Path()}
+		g.site.Pages = append(g.site.Pages, p)
+		g.targets[e.Path()] = target{page: p, fragment: e.File}
+		if e.Notes == name+".md" {
+			g.notes[e.Path()] = e.Notes
+			g.targets[e.Notes] = target{page: p}
+		}
+	}
+}
+
+// order places the pages in the chapters of docs/topics.md and sorts them
+// into reading order: the index and contents, then each chapter's pages, by
+// topic, and last the pages placed in no chapter.
+func (g *generator) order() {
+	for _, p := range g.site.Pages[2:] {
+		p.Chapter, p.Topic = progress.Place(g.site.Chapters, p.Source)
+	}
+	rest := g.site.Pages[2:]
+	sort.SliceStable(rest, func(i, j int) bool {
+		a, b := rest[i], rest[j]
+		if a.Chapter != b.Chapter {
+			return b.Chapter < 0 || (a.Chapter >= 0 && a.Chapter < b.Chapter)
+		}
+		if a.Topic != b.Topic {
+			return a.Topic < b.Topic
+		}
+		return a.Source < b.Source
+	})
+}
+
+// render fills in the title and body of a page.
+func (g *generator) render(p *Page) error {
+	m := &markdown{link: g.linker(p)}
+	switch {
+	case p.Path == "contents.html":
+		// Rendered as the site is written, once the pages are in order.
+		return nil
+	case g.sources[p.Source] != nil, g.labs[p.Source].File != "":
+		return g.renderPackage(p, m)
+	}
+	src, err := os.ReadFile(filepath.Join(g.root, filepath.FromSlash(p.Source)))
+	if err != nil {
+		return err
+	}
+	text := string(src)
+	if g.quizzes[p.Source] {
+		text = foldAnswers(text)
+	}
+	p.Body = template.HTML(m.render(text))
+	p.Title = heading(text)
+	if p.Title == "" {
+		name := strings.TrimSuffix(p.Source, path.Ext(p.Source))
+		if strings.EqualFold(path.Base(name), "README") {
+			name = path.Dir(name)
+		}
+		p.Title = humanize(path.Base(name))
+	}
+	if p.Source == "README.md" {
+		g.site.Title = p.Title
+	}
+	return nil
+}
+
+// renderPackage renders the page of a package directory or program: its
+// notes, its sources and, for a lab, its captured output.
+func (g *generator) renderPackage(p *Page, m *markdown) error {
+	var b strings.Builder
+	dir, files := p.Source, g.sources[p.Source]
+	e, isLab := g.labs[p.Source]
+	if e.File != "" {
+		dir, files = e.Dir, e.Files
+	}
+	p.Title = e.Title
+	if notes := g.notes[p.Source]; notes != "" {
+		src, err := os.ReadFile(filepath.Join(g.root, filepath.FromSlash(notes)))
+		if err != nil {
+			return err
+		}
+		b.WriteString(m.render(string(src)))
+		if !isLab {
+			p.Title = heading(string(src))
+		}
+	}
+	var doc string
+	if isLab {
+		doc = e.Doc
+	} else {
+		var name string
+		name, doc = packageDoc(filepath.Join(g.root, filepath.FromSlash(dir)), files)
+		if p.Title == "" {
+			p.Title = "Package " + name
+		}
+	}
+	if g.notes[p.Source] == "" {
+		m.heading(&b, 1, html.EscapeString(p.Title))
+		var cp comment.Parser
+		printer := comment.Printer{HeadingLevel: 2, DocLinkBaseURL: "https://pkg.go.dev"}
+		b.Write(printer.HTML(cp.Parse(doc)))
+	}
+
+	if isLab {
+		if _, err := os.Stat(grade.Path(g.root, e.Path())); err == nil {
+			b.WriteString(`<p class="run">Check your solution with <code>go run ./cmd/golabs grade ` + html.EscapeString(e.Path()) + "</code></p>\n")
+		} else {
+			b.WriteString(`<p class="run">Run it with <code>go run ./cmd/golabs run ` + html.EscapeString(e.Path()) + "</code></p>\n")
+		}
+	}
+
+	m.heading(&b, 2, "Source")
+	for _, name := range files {
+		src, err := os.ReadFile(filepath.Join(g.root, filepath.FromSlash(dir), name))
+		if err != nil {
+			return err
+		}
+		b.WriteString(`<h3 id="` + html.EscapeString(name) + `" class="file">` + html.EscapeString(name) + "</h3>\n")
+		b.WriteString(`<pre><code class="language-go">` + highlight(strings.TrimRight(string(src), "\n")) + "\n</code></pre>\n")
+	}
+
+	if isLab && !strings.HasPrefix(dir, "practice/") {
+		m.heading(&b, 2, "Output")
+		out, err := os.ReadFile(golden.Path(g.root, e.Lab))
+		rules, rerr := golden.ReadRules(g.root, e.Lab)
+		switch {
+		case err == nil:
+			b.WriteString(`<pre class="output"><samp>` + html.EscapeString(string(out)) + "</samp></pre>\n")
+		case rerr == nil && rules.Skip != "":
+			b.WriteString("<p>Not captured: " + html.EscapeString(rules.Skip) + "</p>\n")
+		default:
+			b.WriteString("<p>No output captured yet. Record it with <code>go test ./internal/golden -run 'TestLabs/" + html.EscapeString(e.Path()) + "$' -update</code></p>\n")
+		}
+	}
+	p.Body = template.HTML(b.String())
+	return nil
+}
+
+// packageDoc returns the name and doc comment of the package in dir.
+func packageDoc(dir string, files []string) (name, doc string) {
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, file), nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		name = f.Name.Name
+		if f.Doc != nil {
+			return name, f.Doc.Text()
+		}
+	}
+	return name, ""
+}
+
+var schemeRe = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// linker returns the function rewriting the links of page p: links to
+// Markdown files, package directories and Go files lead to the pages
+// showing them, and links to other local files record them as assets.
+func (g *generator) linker(p *Page) func(string) string {
+	srcDir := path.Dir(p.Source)
+	if g.sources[p.Source] != nil {
+		srcDir = p.Source // the notes live in the package directory
+	}
+	return func(dest string) string {
+		if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "/") || schemeRe.MatchString(dest) {
+			return dest
+		}
+		ref, fragment, _ := strings.Cut(dest, "#")
+		ref = path.Join(srcDir, ref)
+		if t, ok := g.targets[ref]; ok {
+			if fragment == "" {
+				fragment = t.fragment
+			}
+			url := relURL(p.Path, t.page.Path)
+			if fragment != "" {
+				url += "#" + fragment
+			}
+			return url
+		}
+		if strings.HasPrefix(ref, "../") {
+			return dest
+		}
+		if info, err := os.Stat(filepath.Join(g.root, filepath.FromSlash(ref))); err == nil && !info.IsDir() {
+			g.assets[ref] = true
+		}
+		url := relURL(p.Path, ref)
+		if fragment != "" {
+			url += "#" + fragment
+		}
+		return url
+	}
+}
+
+// relURL returns the relative URL of the file to from the page from, both
+// slash-separated paths relative to the root of the site.
+func relURL(from, to string) string {
+	fromDir := strings.Split(path.Dir(from), "/")
+	if fromDir[0] == "." {
+		fromDir = nil
+	}
+	toParts := strings.Split(to, "/")
+	i := 0
+	for i < len(fromDir) && i < len(toParts)-1 && fromDir[i] == toParts[i] {
+		i++
+	}
+	return strings.Repeat("../", len(fromDir)-i) + strings.Join(toParts[i:], "/")
+}
+
+// foldAnswers wraps the answer blockquotes of a quiz in <details>, so that
+// each answer stays hidden until asked for.
+func foldAnswers(text string) string {
+	lines := strings.Split(text, "\n")
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "> **Answer**") {
+			out = append(out, lines[i])
+			continue
+		}
+		out = append(out, "", "<details>", "<summary>Answer</summary>", "")
+		for ; i < len(lines) && strings.HasPrefix(lines[i], ">"); i++ {
+			out = append(out, lines[i])
+		}
+		i--
+		out = append(out, "", "</details>", "")
+	}
+	return strings.Join(out, "\n")
+}
+
+// heading returns the first level-one heading of Markdown text, or else its
+// first heading, without markup.
+func heading(text string) string {
+	first, fence := "", ""
+	for _, line := range strings.Split(text, "\n") {
+		if sub := fenceRe.FindStringSubmatch(line); sub != nil {
+			switch {
+			case fence == "":
+				fence = sub[2][:1]
+			case sub[2][:1] == fence && strings.TrimSpace(sub[3]) == "":
+				fence = ""
+			}
+			continue
+		}
+		sub := headingRe.FindStringSubmatch(line)
+		if fence != "" || sub == nil || sub[2] == "" {
+			continue
+		}
+		title := strings.TrimSpace(strings.NewReplacer("`", "", "**", "").Replace(sub[2]))
+		if len(sub[1]) == 1 {
+			return title
+		}
+		if first == "" {
+			first = title
+		}
+	}
+	return first
+}
+
+var numberPrefix = regexp.MustCompile(`^\d+[_-]`)
+
+// humanize turns "01_origin-and-evolution-of-go" into "Origin and
+// evolution of go".
+func humanize(name string) string {
+	name = numberPrefix.ReplaceAllString(name, "")
+	name = strings.NewReplacer("_", " ", "-", " ").Replace(name)
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// Write writes the site to dir: its pages, stylesheet and assets.
+func (s *Site) Write(dir string) error {
+	style, err := templateFS.ReadFile("templates/style.css")
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, "style.css"), style); err != nil {
+		return err
+	}
+	for i, p := range s.Pages {
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "page.html", s.view(i)); err != nil {
+			return fmt.Errorf("%s: %w", p.Path, err)
+		}
+		if err := writeFile(filepath.Join(dir, filepath.FromSlash(p.Path)), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	for _, a := range s.Assets {
+		data, err := os.ReadFile(filepath.Join(s.Root, filepath.FromSlash(a)))
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dir, filepath.FromSlash(a)), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
+
+// view is what the page template shows.
+type view struct {
+	Site       string
+	Base       string // relative URL of the root of the site
+	Page       *Page
+	Body       template.HTML
+	Nav        []navGroup
+	Prev, Next *link
+}
+
+type navGroup struct {
+	Number int
+	Title  string
+	Topics []navTopic // for the contents page
+	Links  []link     // every page of the group
+}
+
+type navTopic struct {
+	Title string
+	Links []link
+}
+
+type link struct {
+	URL, Title string
+	Current    bool
+}
+
+// view returns the template data of the i'th page.
+func (s *Site) view(i int) view {
+	p := s.Pages[i]
+	v := view{Site: s.Title, Base: relURL(p.Path, ""), Page: p, Body: p.Body}
+	linkTo := func(q *Page) link {
+		return link{URL: relURL(p.Path, q.Path), Title: q.Title, Current: q == p}
+	}
+	for c, ch := range s.Chapters {
+		// The untitled first topic holds the pages of the chapter itself.
+		group := navGroup{Number: ch.Number, Title: ch.Title, Topics: []navTopic{{}}}
+		for _, t := range ch.Topics {
+			group.Topics = append(group.Topics, navTopic{Title: t.Title})
+		}
+		for _, q := range s.Pages[2:] {
+			if q.Chapter != c {
+				continue
+			}
+			group.Links = append(group.Links, linkTo(q))
+			t := &group.Topics[q.Topic+1]
+			t.Links = append(t.Links, linkTo(q))
+		}
+		v.Nav = append(v.Nav, group)
+	}
+	more := navGroup{Title: "More", Topics: []navTopic{{}}}
+	for _, q := range s.Pages[2:] {
+		if q.Chapter < 0 {
+			more.Links = append(more.Links, linkTo(q))
+		}
+	}
+	if len(more.Links) > 0 {
+		more.Topics[0].Links = more.Links
+		v.Nav = append(v.Nav, more)
+	}
+	if i > 0 {
+		prev := linkTo(s.Pages[i-1])
+		v.Prev = &prev
+	}
+	if i+1 < len(s.Pages) {
+		next := linkTo(s.Pages[i+1])
+		v.Next = &next
+	}
+	if p.Path == "contents.html" {
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "contents.html", v); err == nil {
+			v.Body = template.HTML(buf.String())
+		}
+	}
+	return v
+}
@@ -0,0 +1,268 @@
+package site
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-labs/internal/labs"
+	"go-labs/internal/testutil"
+)
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		in, expected string
+	}{
+		{"# Maps & Keys", `<h1 id="maps--keys">Maps &amp; Keys</h1>` + "\n"},
+		{"Title\n=====", `<h1 id="title">Title</h1>` + "\n"},
+		{"a *b* **c** _d_ snake_case_name", "<p>a <em>b</em> <strong>c</strong> <em>d</em> snake_case_name</p>\n"},
+		{"`a < b` and `` x`y ``", "<p><code>a &lt; b</code> and <code>x`y</code></p>\n"},
+		{"[spec](https://go.dev/ref/spec) ![pic](img/a.png \"A\")", `<p><a href="https://go.dev/ref/spec">spec</a> <img src="img/a.png" alt="pic" title="A"></p>` + "\n"},
+		{"**A**. One\\\n**B**. Two  \nThree", "<p><strong>A</strong>. One<br>\n<strong>B</strong>. Two<br>\nThree</p>\n"},
+		{"1 &lt; 2 & <code>&#92;</code> <cycle *T>", "<p>1 &lt; 2 &amp; <code>&#92;</code> &lt;cycle *T&gt;</p>\n"},
+		{"- a\n- b\n  - c\n", "<ul>\n<li>a\n</li>\n<li>b\n<ul>\n<li>c\n</li>\n</ul>\n</li>\n</ul>\n"},
+		{"3. a\n\n4. b", "<ol start=\"3\">\n<li>\n<p>a</p>\n</li>\n<li>\n<p>b</p>\n</li>\n</ol>\n"},
+		{"1. Run:\n\n   ```go\n   x := 1\n   ```", "<ol>\n<li>\n<p>Run:</p>\n<pre><code class=\"language-go\">x := <span class=\"num\">1</span>\n</code></pre>\n</li>\n</ol>\n"},
+		{"```\n<b>\n\n```", "<pre><code>&lt;b&gt;\n\n</code></pre>\n"},
+		{"> **Note**: quoted\nlazy\n\nafter", "<blockquote>\n<p><strong>Note</strong>: quoted\nlazy</p>\n</blockquote>\n<p>after</p>\n"},
+		{"<details>\n<summary>More</summary>\n\n*in*\n\n</details>", "<details>\n<summary>More</summary>\n<p><em>in</em></p>\n</details>\n"},
+		{"| Op | Use |\n|:---|---:|\n| `\\|\\|` | or |", "<table>\n<thead>\n<tr><th style=\"text-align:left\">Op</th><th style=\"text-align:right\">Use</th></tr>\n</thead>\n<tbody>\n<tr><td style=\"text-align:left\"><code>||</code></td><td style=\"text-align:right\">or</td></tr>\n</tbody>\n</table>\n"},
+		{"text\n2021. A year\n\n---", "<p>text\n2021. A year</p>\n<hr>\n"},
+	}
+	for _, test := range tests {
+		m := &markdown{}
+		if got := m.render(test.in); got != test.expected {
+			t.Errorf("render(%q) returned\n%q\nExpected\n%q", test.in, got, test.expected)
+		}
+	}
+
+	m := &markdown{}
+	if got := m.render("## Use Case\n## Use Case"); !strings.Contains(got, `id="use-case-1"`) {
+		t.Errorf("render gave repeated headings the same id:\n%s", got)
+	}
+	m = &markdown{link: func(dest string) string { return "x/" + dest }}
+	if got := m.render(`[a](b.md) <img src="c.png" width="3">`); got != `<p><a href="x/b.md">a</a> <img src="x/c.png" width="3"></p>`+"\n" {
+		t.Errorf("render did not rewrite the links: %q", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	src := "package main\n\n// Hi says \"hi\".\nfunc Hi() string { return `<hi>` + string(rune(1.5)) }"
+	expected := `<span class="kw">package</span> main` + "\n\n" +
+		`<span class="com">// Hi says &#34;hi&#34;.</span>` + "\n" +
+		`<span class="kw">func</span> Hi() <span class="pre">string</span> { <span class="kw">return</span> <span class="str">` + "`&lt;hi&gt;`" + `</span> + <span class="pre">string</span>(<span class="pre">rune</span>(<span class="num">1.5</span>)) }`
+	if got := highlight(src); got != expected {
+		t.Errorf("highlight returned\n%s\nExpected\n%s", got, expected)
+	}
+	if got := highlight("x := \"unterminated"); got != `x := <span class="str">&#34;unterminated</span>` {
+		t.Errorf("highlight of a broken snippet returned %s", got)
+	}
+}
+
+func TestRelURL(t *testing.T) {
+	tests := []struct {
+		from, to, expected string
+	}{
+		{"index.html", "06_maps/index.html", "06_maps/index.html"},
+		{"06_maps/index.html", "index.html", "../index.html"},
+		{"06_maps/kvstore/index.html", "06_maps/01_maps.html", "../01_maps.html"},
+		{"06_maps/index.html", "06_maps/img/a.png", "img/a.png"},
+		{"a/b/index.html", "", "../../"},
+	}
+	for _, test := range tests {
+		if got := relURL(test.from, test.to); got != test.expected {
+			t.Errorf("relURL(%q, %q) returned %q. Expected %q", test.from, test.to, got, test.expected)
+		}
+	}
+}
+
+var tree = map[string]string{
+	"go.mod":         "module example.com/labs\n\ngo 1.19\n",
+	"README.md":      "# Labs\n\nSee [maps](02_maps) and [the topics](docs/topics.md).\n",
+	"docs/topics.md": "# Topics\n\n## 1. Introduction to Go\n\n- Hello World Program\n\n## 2. Data Types\n\n- Maps\n- Channels\n",
+
+	"01_introduction/01_history.md":  "# History\n\n![Gopher](img/gopher.png)\n\nNext: [the quiz](02_quiz.md#1-what).\n",
+	"01_introduction/02_quiz.md":     "# Quiz: Intro\n\n### 1. What?\n\n**A**. This\\\n**B**. That\n\n> **Answer**: **A**. This\n",
+	"01_introduction/img/gopher.png": "PNG",
+	"01_introduction/img/unused.png": "PNG",
+
+	"02_maps/README.md":                             "# Maps\n\nThe lab is [here](01_maps.go); see also [kv](kv) and [b](progs/02_b.go).\n",
+	"02_maps/01_maps.go":                            "// maps.go\n\npackage main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"map[a:1]\") }\n",
+	"02_maps/testdata/output.golden":                "map[a:1]\n",
+	"02_maps/kv/kv.go":                              "// Package kv stores values.\n//\n//\tkv.Put(\"a\", 1)\npackage kv\n",
+	"02_maps/kv/kv_test.go":                         "package kv\n",
+	"02_maps/progs/01_a.go":                         "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"a\") }\n",
+	"02_maps/progs/01_a.md":                         "# Program A\n\nSee [b](02_b.go).\n",
+	"02_maps/progs/02_b.go":                         "// b.go\npackage main\n\nfunc main() {}\n",
+	"02_maps/progs/testdata/01_a.golden":            "a\n",
+	"02_maps/skipped/main.go":                       "//golden:skip reads the network\npackage main\n\nfunc main() {}\n",
+	"practice/02_maps/count/count.go":               "// Command count is an exercise on maps.\npackage main\n\nfunc main() {}\n",
+	"practice/02_maps/count/testdata/grade_test.go": "package main\n",
+	"cmd/tool/main.go":                              "package main\n\nfunc main() {}\n",
+	"notes/odd.md":                                  "Misc notes.\n",
+}
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteTree(t, root, tree)
+	s, err := Generate(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, p := range s.Pages {
+		paths = append(paths, p.Path+" "+p.Title)
+	}
+	expected := []string{
+		"index.html Labs",
+		"contents.html Contents",
+		"01_introduction/01_history.html History",
+		"01_introduction/02_quiz.html Quiz: Intro",
+		"02_maps/index.html Maps",
+		"02_maps/kv/index.html Package kv",
+		"02_maps/progs/01_a.html Program A",
+		"02_maps/progs/02_b.html B",
+		"02_maps/skipped/index.html Skipped",
+		"practice/02_maps/count/index.html An exercise on maps",
+		"notes/odd.html Odd",
+	}
+	if strings.Join(paths, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Generate made pages\n%s\nExpected\n%s", strings.Join(paths, "\n"), strings.Join(expected, "\n"))
+	}
+	if len(s.Assets) != 1 || s.Assets[0] != "01_introduction/img/gopher.png" {
+		t.Errorf("Generate found assets %q. Expected only the linked image", s.Assets)
+	}
+
+	page := func(p string) string {
+		for _, q := range s.Pages {
+			if q.Path == p {
+				return string(q.Body)
+			}
+		}
+		return ""
+	}
+	for p, want := range map[string][]string{
+		"index.html":                      {`href="02_maps/index.html"`, `href="contents.html"`},
+		"01_introduction/01_history.html": {`href="02_quiz.html#1-what"`, `src="img/gopher.png"`},
+		"01_introduction/02_quiz.html":    {"<details>\n<summary>Answer</summary>"},
+		"02_maps/index.html": {
+			`href="index.html#01_maps.go"`, `href="kv/index.html"`, `href="progs/02_b.html#02_b.go"`,
+			`<h3 id="01_maps.go" class="file">`, `<span class="kw">func</span> main()`,
+			"<samp>map[a:1]\n</samp>", "golabs run 02_maps",
+		},
+		"02_maps/kv/index.html":             {"<pre>kv.Put(&quot;a&quot;, 1)\n</pre>"},
+		"02_maps/progs/01_a.html":           {`href="02_b.html#02_b.go"`, "golabs run 02_maps/progs/01_a.go", "<samp>a\n</samp>"},
+		"02_maps/progs/02_b.html":           {`<h3 id="02_b.go" class="file">`, "TestLabs/02_maps/progs/02_b.go$"},
+		"02_maps/skipped/index.html":        {"Not captured: reads the network"},
+		"practice/02_maps/count/index.html": {"golabs grade practice/02_maps/count"},
+	} {
+		body := page(p)
+		for _, w := range want {
+			if !strings.Contains(body, w) {
+				t.Errorf("page %s lacks %q:\n%s", p, w, body)
+			}
+		}
+	}
+	if body := page("02_maps/kv/index.html"); strings.Contains(body, `id="output"`) {
+		t.Errorf("library page shows an output section:\n%s", body)
+	}
+
+	dir := t.TempDir()
+	if err := s.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, dir)
+	contents, err := os.ReadFile(filepath.Join(dir, "contents.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{
+		"<h2>1. Introduction to Go</h2>", `<li class="empty">Hello World Program</li>`,
+		`<li class="empty">Channels</li>`, "<h2>More</h2>", `<a href="notes/odd.html">Odd</a>`,
+	} {
+		if !strings.Contains(string(contents), w) {
+			t.Errorf("contents.html lacks %q:\n%s", w, contents)
+		}
+	}
+	kv, err := os.ReadFile(filepath.Join(dir, "02_maps", "kv", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{`href="../../style.css"`, `<a rel="prev" href="../index.html">← Maps</a>`, `aria-current="page">Package kv</a>`} {
+		if !strings.Contains(string(kv), w) {
+			t.Errorf("02_maps/kv/index.html lacks %q", w)
+		}
+	}
+}
+
+// TestRepository renders the course itself and checks that the site works
+// offline: every local link and image leads to a written file.
+func TestRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("renders the whole repository")
+	}
+	root, err := labs.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Generate(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := s.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, dir)
+}
+
+var urlRe = regexp.MustCompile(`(?:href|src)="([^"]*)"`)
+
+// checkLinks reports the relative links of the pages in dir that lead to no
+// file, or to no element of a page.
+func checkLinks(t *testing.T, dir string) {
+	t.Helper()
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".html") {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		for _, m := range urlRe.FindAllStringSubmatch(string(data), -1) {
+			u := m[1]
+			if schemeRe.MatchString(u) {
+				continue
+			}
+			ref, fragment, _ := strings.Cut(u, "#")
+			target := p
+			if ref != "" {
+				target = filepath.Join(filepath.Dir(p), filepath.FromSlash(ref))
+			}
+			info, err := os.Stat(target)
+			if err != nil || info.IsDir() {
+				t.Errorf("%s links to %s, which is not a file", filepath.ToSlash(rel), u)
+				continue
+			}
+			if fragment == "" || path.Ext(target) != ".html" {
+				continue
+			}
+			page, err := os.ReadFile(target)
+			if err != nil {
+				return err
+			}
+			if !strings.Contains(string(page), `id="`+fragment+`"`) {
+				t.Errorf("%s links to %s, which has no such id", filepath.ToSlash(rel), u)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
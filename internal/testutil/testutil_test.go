@@ -0,0 +1,18 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteTree(t *testing.T) {
+	dir := t.TempDir()
+	WriteTree(t, dir, map[string]string{"a.txt": "a", "b/c/d.txt": "d"})
+	for name, expected := range map[string]string{"a.txt": "a", "b/c/d.txt": "d"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != expected {
+			t.Errorf("%s holds %q, %v. Expected %q", name, got, err, expected)
+		}
+	}
+}
@@ -1,6 +1,30 @@
+// Command arrays is an exercise on declaring, filling and iterating over
+// arrays, as in 05_arrays_and_slices/01_declaration_and_initialization/arrays.
+// Replace the body of each function, then check your answers with
+//
+//	golabs grade arrays
 package main
 
-// Arrays
-func main() {
+import "fmt"
+
+// Doubles returns an array whose element i is i * 2: [0 2 4 6 8].
+func Doubles() [5]int {
+	var arr [5]int
+	return arr
+}
+
+// Sum returns the sum of the elements of arr.
+func Sum(arr [5]int) int {
+	return 0
+}
+
+// Reverse returns arr with its elements in reverse order. As arrays are
+// values, the caller's array is left unchanged.
+func Reverse(arr [5]int) [5]int {
+	return arr
+}
 
+func main() {
+	arr := Doubles()
+	fmt.Println(arr, Sum(arr), Reverse(arr))
 }
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+// Loop over the indexes with for i := 0; i < len(arr); i++ and set
+// arr[i] = i * 2.
+func TestDoubles(t *testing.T) {
+	expected := [5]int{0, 2, 4, 6, 8}
+	if got := Doubles(); got != expected {
+		t.Errorf("Doubles() = %v. Expected %v", got, expected)
+	}
+}
+
+// Start from a total of 0 and add each value of a for range loop to it.
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name     string
+		arr      [5]int
+		expected int
+	}{
+		{"zeros", [5]int{}, 0},
+		{"doubles", [5]int{0, 2, 4, 6, 8}, 20},
+		{"negatives", [5]int{-1, 2, -3, 4, -5}, -3},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if got := Sum(test.arr); got != test.expected {
+				t.Errorf("Sum(%v) = %d. Expected %d", test.arr, got, test.expected)
+			}
+		})
+	}
+}
+
+// Element i of the result is element len(arr)-1-i of arr. Assigning to the
+// parameter arr does not change the caller's array.
+func TestReverse(t *testing.T) {
+	t.Run("ascending", func(t *testing.T) {
+		arr := [5]int{1, 2, 3, 4, 5}
+		expected := [5]int{5, 4, 3, 2, 1}
+		if got := Reverse(arr); got != expected {
+			t.Errorf("Reverse(%v) = %v. Expected %v", arr, got, expected)
+		}
+	})
+	t.Run("unchanged", func(t *testing.T) {
+		arr := [5]int{1, 2, 3, 4, 5}
+		Reverse(arr)
+		if arr != [5]int{1, 2, 3, 4, 5} {
+			t.Errorf("Reverse changed its argument to %v", arr)
+		}
+	})
+}
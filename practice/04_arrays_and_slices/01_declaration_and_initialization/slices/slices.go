@@ -1,5 +1,37 @@
+// Command slices is an exercise on creating slices with make, with slice
+// literals and from arrays, as in
+// 05_arrays_and_slices/01_declaration_and_initialization/slices. Replace the
+// body of each function, then check your answers with
+//
+//	golabs grade slices
 package main
 
-func main() {
+import "fmt"
+
+// Multiples returns a slice of length n whose element i is i * k, created
+// with make.
+func Multiples(n, k int) []int {
+	return nil
+}
+
+// Greeting returns a slice literal holding "hello" and "world".
+func Greeting() []string {
+	return nil
+}
 
+// Middle returns the elements 1, 2 and 3 of arr as a slice of arr itself,
+// so that changing the slice changes arr.
+func Middle(arr *[5]int) []int {
+	return nil
+}
+
+// Evens returns the even numbers of s, in order, in a new slice built with
+// append. s is left unchanged.
+func Evens(s []int) []int {
+	return nil
+}
+
+func main() {
+	arr := [5]int{0, 1, 2, 3, 4}
+	fmt.Println(Multiples(5, 10), Greeting(), Middle(&arr), Evens(arr[:]))
 }
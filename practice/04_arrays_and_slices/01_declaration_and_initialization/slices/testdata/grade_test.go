@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// make([]int, n) creates a slice of n zeros; then set each element in a
+// for range loop.
+func TestMultiples(t *testing.T) {
+	tests := []struct {
+		name     string
+		n, k     int
+		expected []int
+	}{
+		{"five tens", 5, 10, []int{0, 10, 20, 30, 40}},
+		{"one", 1, 3, []int{0}},
+		{"empty", 0, 7, []int{}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := Multiples(test.n, test.k)
+			if got == nil || !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("Multiples(%d, %d) = %#v. Expected %#v", test.n, test.k, got, test.expected)
+			}
+		})
+	}
+}
+
+// A slice literal is written like an array literal without the length:
+// []string{...}.
+func TestGreeting(t *testing.T) {
+	expected := []string{"hello", "world"}
+	if got := Greeting(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Greeting() = %q. Expected %q", got, expected)
+	}
+}
+
+// arr[low:high] includes index low but not index high, and shares arr's
+// memory.
+func TestMiddle(t *testing.T) {
+	t.Run("elements", func(t *testing.T) {
+		arr := [5]int{0, 1, 2, 3, 4}
+		if got := Middle(&arr); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+			t.Errorf("Middle(&%v) = %v. Expected [1 2 3]", arr, got)
+		}
+	})
+	t.Run("shared", func(t *testing.T) {
+		arr := [5]int{0, 1, 2, 3, 4}
+		s := Middle(&arr)
+		if len(s) == 0 {
+			t.Fatalf("Middle(&%v) returned an empty slice", arr)
+		}
+		s[0] = 100
+		if arr[1] != 100 {
+			t.Errorf("setting the first element of Middle's result left arr as %v. Expected it to change arr[1]", arr)
+		}
+	})
+}
+
+// Start from an empty slice and append each value v of s for which
+// v%2 == 0.
+func TestEvens(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        []int
+		expected []int
+	}{
+		{"mixed", []int{0, 1, 2, 3, 4}, []int{0, 2, 4}},
+		{"negative", []int{-4, -3}, []int{-4}},
+		{"none", []int{1, 3}, nil},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			in := append([]int(nil), test.s...)
+			got := Evens(in)
+			if len(got) != len(test.expected) || (len(got) > 0 && !reflect.DeepEqual(got, test.expected)) {
+				t.Errorf("Evens(%v) = %v. Expected %v", test.s, got, test.expected)
+			}
+			if !reflect.DeepEqual(in, test.s) {
+				t.Errorf("Evens changed its argument to %v", in)
+			}
+		})
+	}
+}
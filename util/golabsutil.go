@@ -1,5 +1,2 @@
+// Package util holds helpers shared by the labs.
 package util
-
-func main() {
-
-}
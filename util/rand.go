@@ -0,0 +1,22 @@
+package util
+
+import (
+	"math/rand/v2"
+	"os"
+	"strconv"
+)
+
+// SeedEnv names the environment variable that seeds Rand.
+const SeedEnv = "GOLABS_SEED"
+
+// Rand returns a random number generator. The top-level functions of
+// math/rand/v2 cannot be seeded, so labs whose output should be repeatable,
+// such as in golden tests, use this generator instead: it is seeded from
+// GOLABS_SEED when that is set to an unsigned integer, and randomly
+// otherwise.
+func Rand() *rand.Rand {
+	if seed, err := strconv.ParseUint(os.Getenv(SeedEnv), 10, 64); err == nil {
+		return rand.New(rand.NewPCG(seed, seed))
+	}
+	return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+}
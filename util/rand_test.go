@@ -0,0 +1,18 @@
+package util
+
+import "testing"
+
+func TestRand(t *testing.T) {
+	t.Setenv(SeedEnv, "42")
+	a, b := Rand(), Rand()
+	for i := 0; i < 5; i++ {
+		if x, y := a.Uint64(), b.Uint64(); x != y {
+			t.Fatalf("Rand with %s=42 drew %d and %d. Expected the same numbers", SeedEnv, x, y)
+		}
+	}
+
+	t.Setenv(SeedEnv, "")
+	if Rand().Uint64() == Rand().Uint64() {
+		t.Errorf("Rand without %s drew the same number twice", SeedEnv)
+	}
+}